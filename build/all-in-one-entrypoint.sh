@@ -11,7 +11,7 @@ if [ -z "$AGGREGATOR_OFF" ]; then
     --cache-dir=/var/lib/statshouse/cache/aggregator -u=root -g=root &
 fi
 if [ -z "$AGENT_OFF" ]; then
-  /bin/statshouse agent --cluster=local_test_cluster --log-level=trace --remote-write-enabled \
+  /bin/statshouse agent --cluster=local_test_cluster --log-level=trace \
     --agg-addr='127.0.0.1:13336,127.0.0.1:13336,127.0.0.1:13336' --cache-dir=/var/lib/statshouse/cache/agent \
     -u=root -g=root &
 fi
@@ -360,6 +360,7 @@ func run(argv args, cfg *api.Config, vkuthPublicKeys map[string][]byte) error {
 	a.Path("/" + api.EndpointMetric).Methods("POST").HandlerFunc(f.HandlePostMetric)
 	a.Path("/" + api.EndpointResetFlood).Methods("POST").HandlerFunc(f.HandlePostResetFlood)
 	a.Path("/" + api.EndpointQuery).Methods("GET").HandlerFunc(f.HandleSeriesQuery)
+	a.Path("/" + api.EndpointQueryLive).Methods("GET").HandlerFunc(f.HandleSeriesQueryLive)
 	a.Path("/" + api.EndpointPoint).Methods("GET").HandlerFunc(f.HandlePointQuery)
 	a.Path("/" + api.EndpointPoint).Methods("POST").HandlerFunc(f.HandlePointQuery)
 	a.Path("/" + api.EndpointTable).Methods("GET").HandlerFunc(f.HandleGetTable)
@@ -368,12 +369,32 @@ func run(argv args, cfg *api.Config, vkuthPublicKeys map[string][]byte) error {
 	a.Path("/" + api.EndpointDashboard).Methods("GET").HandlerFunc(f.HandleGetDashboard)
 	a.Path("/" + api.EndpointDashboardList).Methods("GET").HandlerFunc(f.HandleGetDashboardList)
 	a.Path("/"+api.EndpointDashboard).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostDashboard)
+	a.Path("/" + api.EndpointDashboardExport).Methods("GET").HandlerFunc(f.HandleGetDashboardExport)
+	a.Path("/" + api.EndpointDashboardImport).Methods("POST").HandlerFunc(f.HandlePostDashboardImport)
+	a.Path("/" + api.EndpointDashboardImportGrafana).Methods("POST").HandlerFunc(f.HandlePostDashboardImportGrafana)
+	a.Path("/" + api.EndpointMetadataSync).Methods("POST").HandlerFunc(f.HandlePostMetadataSync)
+	a.Path("/" + api.EndpointMappingSearch).Methods("GET").HandlerFunc(f.HandleGetMappingSearch)
+	a.Path("/" + api.EndpointMappingExport).Methods("GET").HandlerFunc(f.HandleGetMappingExport)
+	a.Path("/" + api.EndpointCardinality).Methods("GET").HandlerFunc(f.HandleGetCardinality)
+	a.Path("/" + api.EndpointSamplingExplain).Methods("GET").HandlerFunc(f.HandleGetSamplingExplain)
 	a.Path("/" + api.EndpointGroup).Methods("GET").HandlerFunc(f.HandleGetGroup)
 	a.Path("/" + api.EndpointGroupList).Methods("GET").HandlerFunc(f.HandleGetGroupsList)
 	a.Path("/"+api.EndpointGroup).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostGroup)
 	a.Path("/"+api.EndpointNamespace).Methods("POST", "PUT").HandlerFunc(f.HandlePostNamespace)
 	a.Path("/" + api.EndpointNamespace).Methods("GET").HandlerFunc(f.HandleGetNamespace)
 	a.Path("/" + api.EndpointNamespaceList).Methods("GET").HandlerFunc(f.HandleGetNamespaceList)
+	a.Path("/" + api.EndpointAlertRule).Methods("GET").HandlerFunc(f.HandleGetAlertRule)
+	a.Path("/" + api.EndpointAlertRuleList).Methods("GET").HandlerFunc(f.HandleGetAlertRuleList)
+	a.Path("/"+api.EndpointAlertRule).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostAlertRule)
+	a.Path("/" + api.EndpointRecordingRule).Methods("GET").HandlerFunc(f.HandleGetRecordingRule)
+	a.Path("/" + api.EndpointRecordingRuleList).Methods("GET").HandlerFunc(f.HandleGetRecordingRuleList)
+	a.Path("/"+api.EndpointRecordingRule).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostRecordingRule)
+	a.Path("/" + api.EndpointAnnotation).Methods("GET").HandlerFunc(f.HandleGetAnnotation)
+	a.Path("/" + api.EndpointAnnotationList).Methods("GET").HandlerFunc(f.HandleGetAnnotationList)
+	a.Path("/"+api.EndpointAnnotation).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostAnnotation)
+	a.Path("/" + api.EndpointAPIToken).Methods("GET").HandlerFunc(f.HandleGetAPIToken)
+	a.Path("/" + api.EndpointAPITokenList).Methods("GET").HandlerFunc(f.HandleGetAPITokenList)
+	a.Path("/"+api.EndpointAPIToken).Methods("POST", "PUT").HandlerFunc(f.HandlePutPostAPIToken)
 	a.Path("/" + api.EndpointPrometheus).Methods("GET").HandlerFunc(f.HandleGetPromConfig)
 	a.Path("/" + api.EndpointPrometheus).Methods("POST").HandlerFunc(f.HandlePostPromConfig)
 	a.Path("/" + api.EndpointPrometheusGenerated).Methods("GET").HandlerFunc(f.HandleGetPromConfigGenerated)
@@ -381,6 +402,8 @@ func run(argv args, cfg *api.Config, vkuthPublicKeys map[string][]byte) error {
 	a.Path("/" + api.EndpointKnownTags).Methods("GET").HandlerFunc(f.HandleGetKnownTags)
 	a.Path("/" + api.EndpointStatistics).Methods("POST").HandlerFunc(f.HandleFrontendStat)
 	a.Path("/" + api.EndpointHistory).Methods("GET").HandlerFunc(f.HandleGetHistory)
+	a.Path("/" + api.EndpointHistoryDiff).Methods("GET").HandlerFunc(f.HandleGetHistoryDiff)
+	a.Path("/" + api.EndpointHistoryRollback).Methods("POST").HandlerFunc(f.HandlePostHistoryRollback)
 	m.Path("/prom/api/v1/query").Methods("GET").HandlerFunc(f.HandleInstantQuery)
 	m.Path("/prom/api/v1/query").Methods("POST").HandlerFunc(f.HandleInstantQuery)
 	m.Path("/prom/api/v1/query_range").Methods("GET").HandlerFunc(f.HandleRangeQuery)
@@ -453,6 +476,8 @@ func run(argv args, cfg *api.Config, vkuthPublicKeys map[string][]byte) error {
 		RawGetQuery:      hr.RawGetQuery,
 		ReleaseChunks:    hr.ReleaseChunks,
 		RawGetQueryPoint: hr.RawGetQueryPoint,
+		CreateAnnotation: hr.CreateAnnotation,
+		RollbackEntity:   hr.RollbackEntity,
 	}
 	var hijackListener *rpc.HijackListener
 	metrics := util.NewRPCServerMetrics("statshouse_api")
@@ -312,6 +312,7 @@ func run() error {
 		PutTagMappingBootstrap: metadata.HandleProxyGen(&proxy, "put_bootstrap", handler.PutTagMappingBootstrap),
 		GetTagMappingBootstrap: metadata.HandleProxyGen(&proxy, "get_bootstrap", handler.GetTagMappingBootstrap),
 		ResetFlood2:            metadata.HandleProxyGen(&proxy, "resetFloo2", handler.ResetFlood2),
+		SearchMappings:         metadata.HandleProxyGen(&proxy, "search_mappings", handler.SearchMappings),
 	}
 	sh := &tlmetadata.Handler{
 		RawGetJournalnew: proxy.HandleProxy("getJournal", handler.RawGetJournal),
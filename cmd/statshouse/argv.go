@@ -50,6 +50,10 @@ var (
 		listenAddr                   string
 		listenAddrIPv6               string
 		listenAddrUnix               string
+		listenAddrOTLP               string
+		listenAddrPromRemoteWrite    string
+		listenAddrStream             string
+		listenAddrStreamUnix         string
 		coresUDP                     int
 		bufferSizeUDP                int
 		promRemoteMod                bool
@@ -130,6 +134,10 @@ func argvAddAgentFlags(legacyVerb bool) {
 	flag.StringVar(&argv.listenAddr, "p", ":13337", "RAW UDP & RPC TCP listen address")
 	flag.StringVar(&argv.listenAddrIPv6, "listen-addr-ipv6", "", "RAW UDP & RPC TCP listen address (IPv6)")
 	flag.StringVar(&argv.listenAddrUnix, "listen-addr-unix", "", "Unix datagram listen address.")
+	flag.StringVar(&argv.listenAddrOTLP, "listen-addr-otlp", "", "OpenTelemetry OTLP/HTTP and OTLP/gRPC listen address. Empty switches OTLP off.")
+	flag.StringVar(&argv.listenAddrStream, "listen-addr-stream", "", "TCP listen address for length-prefixed stream of metric batches with acknowledgements. Empty switches it off.")
+	flag.StringVar(&argv.listenAddrStreamUnix, "listen-addr-stream-unix", "", "Unix stream socket listen address for length-prefixed stream of metric batches with acknowledgements. Empty switches it off.")
+	flag.StringVar(&argv.listenAddrPromRemoteWrite, "listen-addr-prom-remote-write", "", "Prometheus remote write listen address, endpoint path is "+receiver.PromRemoteWritePath+". Empty switches remote write receiver off.")
 
 	flag.IntVar(&argv.coresUDP, "cores-udp", 1, "CPU cores to use for udp receiving. 0 switches UDP off")
 	flag.IntVar(&argv.bufferSizeUDP, "buffer-size-udp", receiver.DefaultConnBufSize, "UDP receiving buffer size")
@@ -179,6 +187,11 @@ func argvAddAggregatorFlags(legacyVerb bool) {
 	flag.StringVar(&argv.configAggregator.MetadataNet, "metadata-net", aggregator.DefaultConfigAggregator().MetadataNet, "")
 
 	flag.StringVar(&argv.configAggregator.KHAddr, "kh", "127.0.0.1:13338,127.0.0.1:13339", "clickhouse HTTP address:port")
+	flag.StringVar(&argv.configAggregator.InsertFileDir, "insert-file-dir", aggregator.DefaultConfigAggregator().InsertFileDir, "If set, aggregator writes inserts as newline-delimited JSON files into this directory instead of clickhouse, one file per row second. Set --kh to empty string to run without clickhouse.")
+
+	flag.StringVar(&argv.configAggregator.RemoteWriteURL, "remote-write-url", aggregator.DefaultConfigAggregator().RemoteWriteURL, "Prometheus remote write URL, data of --remote-write-namespaces is mirrored there after insert into clickhouse. Empty switches mirroring off.")
+	flag.StringVar(&argv.configAggregator.RemoteWriteNamespaces, "remote-write-namespaces", aggregator.DefaultConfigAggregator().RemoteWriteNamespaces, "Comma-separated list of namespaces to mirror to --remote-write-url, metrics without namespace belong to __default.")
+	flag.Int64Var(&argv.configAggregator.RemoteWriteMaxDiskSize, "remote-write-max-disk-size", aggregator.DefaultConfigAggregator().RemoteWriteMaxDiskSize, "Aggregator will use no more than this amount of disk space for data not yet accepted by --remote-write-url, oldest data is thrown out.")
 }
 
 func argvAddIngressProxyFlags() {
@@ -202,4 +215,5 @@ func printVerbUsage() {
 	_, _ = fmt.Fprintf(os.Stderr, "statshouse tlclient.api <options>      test API\n")
 	_, _ = fmt.Fprintf(os.Stderr, "statshouse simulator <options>         simulate 10 agents sending data\n")
 	_, _ = fmt.Fprintf(os.Stderr, "statshouse benchmark <options>         some brnchmark\n")
+	_, _ = fmt.Fprintf(os.Stderr, "statshouse metadata_sync <options>     sync metadata with declarative YAML or JSON files\n")
 }
@@ -17,10 +17,13 @@ import (
 )
 
 type statsHandler struct {
-	receiversUDP   []*receiver.UDP
-	receiverRPC    *receiver.RPCReceiver
-	sh2            *agent.Agent
-	metricsStorage *metajournal.MetricsStorage
+	receiversUDP            []*receiver.UDP
+	receiverRPC             *receiver.RPCReceiver
+	receiverOTLP            *receiver.OTLP
+	receiverPromRemoteWrite *receiver.PromRemoteWrite
+	receiverStream          *receiver.Stream
+	sh2                     *agent.Agent
+	metricsStorage          *metajournal.MetricsStorage
 }
 
 func (h statsHandler) handleStats(stats map[string]string) {
@@ -57,6 +60,19 @@ func (h statsHandler) handleStats(stats map[string]string) {
 
 	stats["statshouse_rpc_recv_calls_ok"] = strconv.FormatUint(h.receiverRPC.StatCallsTotalOK.Load(), 10)
 	stats["statshouse_rpc_recv_calls_err"] = strconv.FormatUint(h.receiverRPC.StatCallsTotalErr.Load(), 10)
+	if h.receiverOTLP != nil {
+		stats["statshouse_otlp_recv_requests_ok"] = strconv.FormatUint(h.receiverOTLP.StatRequestsTotalOK.Load(), 10)
+		stats["statshouse_otlp_recv_requests_err"] = strconv.FormatUint(h.receiverOTLP.StatRequestsTotalErr.Load(), 10)
+	}
+	if h.receiverStream != nil {
+		stats["statshouse_stream_recv_connections"] = strconv.FormatUint(h.receiverStream.StatConnectionsTotal.Load(), 10)
+		stats["statshouse_stream_recv_batches_ok"] = strconv.FormatUint(h.receiverStream.StatBatchesTotalOK.Load(), 10)
+		stats["statshouse_stream_recv_batches_err"] = strconv.FormatUint(h.receiverStream.StatBatchesTotalErr.Load(), 10)
+	}
+	if h.receiverPromRemoteWrite != nil {
+		stats["statshouse_prom_remote_write_recv_requests_ok"] = strconv.FormatUint(h.receiverPromRemoteWrite.StatRequestsTotalOK.Load(), 10)
+		stats["statshouse_prom_remote_write_recv_requests_err"] = strconv.FormatUint(h.receiverPromRemoteWrite.StatRequestsTotalErr.Load(), 10)
+	}
 
 	stats["statshouse_journal_version"] = strconv.FormatInt(h.metricsStorage.Version(), 10)
 	for i, s := range h.sh2.Shards {
@@ -717,3 +717,65 @@ func mainPublishTagDrafts() {
 		}
 	}
 }
+
+// recorded as author of changes in entity history
+const metadataSyncMetadata = `{"user_email":"metadata-sync"}`
+
+func mainMetadataSync() {
+	var (
+		metadataNet     string
+		metadataAddr    string
+		metadataActorID int64
+		schemaDir       string
+		apply           bool
+	)
+	flag.Int64Var(&metadataActorID, "metadata-actor-id", 0, "")
+	flag.StringVar(&metadataAddr, "metadata-addr", "127.0.0.1:2442", "")
+	flag.StringVar(&metadataNet, "metadata-net", "tcp4", "")
+	flag.StringVar(&argv.aesPwdFile, "aes-pwd-file", "", "path to AES password file, will try to read "+defaultPathToPwd+" if not set")
+	flag.StringVar(&schemaDir, "dir", ".", "directory with namespace, group, metric and dashboard YAML or JSON files")
+	flag.BoolVar(&apply, "apply", false, "apply changes, otherwise only print plan")
+	build.FlagParseShowVersionHelp()
+	flag.Parse()
+	schema, err := metajournal.LoadSchemaDir(schemaDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client := tlmetadata.Client{
+		Client: rpc.NewClient(
+			rpc.ClientWithCryptoKey(readAESPwd()),
+			rpc.ClientWithTrustedSubnetGroups(build.TrustedSubnetGroups())),
+		Network: metadataNet,
+		Address: metadataAddr,
+		ActorID: metadataActorID,
+	}
+	loader := metajournal.NewMetricMetaLoader(&client, metajournal.DefaultMetaTimeout)
+	storage := metajournal.MakeMetricsStorage("", nil, nil)
+	storage.Journal().Start(nil, nil, loader.LoadJournal)
+	_, version, err := loader.LoadJournal(context.Background(), 0, true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = storage.Journal().WaitVersion(context.Background(), version); err != nil {
+		log.Fatal(err)
+	}
+	plan, err := metajournal.PlanSchema(storage, schema)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(plan) == 0 {
+		fmt.Println("No changes")
+		return
+	}
+	for _, c := range plan {
+		fmt.Println(c.String())
+	}
+	if !apply {
+		fmt.Printf("%d change(s) planned, run with -apply to save them\n", len(plan))
+		return
+	}
+	if _, err = metajournal.ApplySchema(context.Background(), loader, storage, plan, metadataSyncMetadata); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%d change(s) applied\n", len(plan))
+}
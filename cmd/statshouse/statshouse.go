@@ -161,6 +161,9 @@ func runMain() int {
 		case "publish_tag_drafts", "-publish_tag_drafts", "--publish_tag_drafts":
 			mainPublishTagDrafts()
 			return 0
+		case "metadata_sync", "-metadata_sync", "--metadata_sync":
+			mainMetadataSync()
+			return 0
 		default:
 			_, _ = fmt.Fprintf(os.Stderr, "Unknown verb %q:\n", verb)
 			printVerbUsage()
@@ -402,10 +405,58 @@ func mainAgent(aesPwd string, dc *pcache.DiskCache) int {
 		go trustedNetworkServeHTTP(hijack)
 	}
 
+	// Run OpenTelemetry receiver
+	var receiverOTLP *receiver.OTLP
+	if argv.listenAddrOTLP != "" {
+		receiverOTLP = receiver.MakeOTLP(sh2, w)
+		ln := listen("tcp", argv.listenAddrOTLP)
+		defer func() { _ = ln.Close() }()
+		logOk.Printf("Listen OTLP addr %q", argv.listenAddrOTLP)
+		go func() {
+			if err := receiverOTLP.Serve(ln); err != nil {
+				logErr.Fatalf("OTLP server failed to serve on %s: %v", ln.Addr(), err)
+			}
+		}()
+	}
+
+	// Run stream receiver, shared between TCP and Unix listeners
+	var receiverStream *receiver.Stream
+	for _, addr := range [...]struct{ network, address string }{{"tcp", argv.listenAddrStream}, {"unix", argv.listenAddrStreamUnix}} {
+		if addr.address == "" {
+			continue
+		}
+		if receiverStream == nil {
+			receiverStream = receiver.MakeStream(sh2, w)
+		}
+		ln := listen(addr.network, addr.address)
+		defer func() { _ = ln.Close() }()
+		logOk.Printf("Listen stream %s addr %q", addr.network, addr.address)
+		go func() {
+			if err := receiverStream.Serve(ln); err != nil {
+				logErr.Fatalf("Stream server failed to serve on %s: %v", ln.Addr(), err)
+			}
+		}()
+	}
+
+	// Run Prometheus remote write receiver
+	var receiverPromRemoteWrite *receiver.PromRemoteWrite
+	if argv.listenAddrPromRemoteWrite != "" {
+		receiverPromRemoteWrite = receiver.MakePromRemoteWrite(sh2, w)
+		ln := listen("tcp", argv.listenAddrPromRemoteWrite)
+		defer func() { _ = ln.Close() }()
+		logOk.Printf("Listen Prometheus remote write addr %q", argv.listenAddrPromRemoteWrite)
+		go func() {
+			if err := receiverPromRemoteWrite.Serve(ln); err != nil {
+				logErr.Fatalf("Prometheus remote write server failed to serve on %s: %v", ln.Addr(), err)
+			}
+		}()
+	}
+
 	// Run RPC server
 	receiverRPC := receiver.MakeRPCReceiver(sh2, w)
 	handlerRPC := &tlstatshouse.Handler{
 		RawAddMetricsBatch: receiverRPC.RawAddMetricsBatch,
+		GetSamplingExplain: sh2.HandleGetSamplingExplain,
 	}
 	metrics := util.NewRPCServerMetrics("statshouse_agent")
 	options := []rpc.ServerOptionsFunc{
@@ -414,7 +465,7 @@ func mainAgent(aesPwd string, dc *pcache.DiskCache) int {
 		rpc.ServerWithCryptoKeys([]string{aesPwd}),
 		rpc.ServerWithTrustedSubnetGroups(build.TrustedSubnetGroups()),
 		rpc.ServerWithHandler(handlerRPC.Handle),
-		rpc.ServerWithStatsHandler(statsHandler{receiversUDP: receiversUDP, receiverRPC: receiverRPC, sh2: sh2, metricsStorage: metricStorage}.handleStats),
+		rpc.ServerWithStatsHandler(statsHandler{receiversUDP: receiversUDP, receiverRPC: receiverRPC, receiverOTLP: receiverOTLP, receiverPromRemoteWrite: receiverPromRemoteWrite, receiverStream: receiverStream, sh2: sh2, metricsStorage: metricStorage}.handleStats),
 		metrics.ServerWithMetrics,
 	}
 	if hijack != nil {
@@ -53,6 +53,9 @@ func (w *worker) HandleMetrics(args data_model.HandlerArgs) (h data_model.Mapped
 		w.logPackets("Parsed metric: %s\n", args.MetricBytes.String())
 	}
 	h, done = w.mapper.Map(args, w.metricStorage.GetMetaMetricByNameBytes(args.MetricBytes.Name))
+	if done && args.RetryOverload && data_model.IngestionStatusQueueOverload(h.IngestionStatus) {
+		return h, done
+	}
 	if done {
 		if w.logPackets != nil {
 			w.printMetric("cached", *args.MetricBytes, h)
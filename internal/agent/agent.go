@@ -7,6 +7,7 @@
 package agent
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/binary"
 	"fmt"
@@ -81,6 +82,8 @@ type Agent struct {
 	statLongWindowOverflow          *BuiltInItemValue
 	statDiskOverflow                *BuiltInItemValue
 
+	samplingExplain data_model.SamplerExplainLog
+
 	mu                          sync.Mutex
 	loadPromTargetsShardReplica *ShardReplica
 }
@@ -463,6 +466,12 @@ func (s *Agent) ApplyMetric(m tlstatshouse.MetricBytes, h data_model.MappedMetri
 			Keys:   [format.MaxTags]int32{h.Key.Keys[0], h.Key.Metric, format.TagValueIDSrcIngestionStatusWarnDeprecatedKeyName, h.LegacyCanonicalTagKey},
 		}, 1)
 	}
+	if h.MetricDeprecated {
+		s.AddCounter(data_model.Key{
+			Metric: format.BuiltinMetricIDIngestionStatus,
+			Keys:   [format.MaxTags]int32{h.Key.Keys[0], h.Key.Metric, format.TagValueIDSrcIngestionStatusWarnMetricDeprecated},
+		}, 1)
+	}
 
 	// We do not check fields mask in code below, only fields values, because
 	// often sending 0 instead of manipulating field mask is more convenient for many clients
@@ -644,3 +653,8 @@ func (s *Agent) HistoricBucketsDataSizeDiskSum() (total int64, unsent int64) {
 	}
 	return total, unsent
 }
+
+// HandleGetSamplingExplain is debug request, explains sample factors selected by shards of this agent
+func (s *Agent) HandleGetSamplingExplain(_ context.Context, args tlstatshouse.GetSamplingExplain) (string, error) {
+	return s.samplingExplain.HandleGetSamplingExplain(args, time.Now())
+}
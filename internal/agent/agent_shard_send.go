@@ -248,6 +248,7 @@ func (s *Shard) sampleBucket(bucket *data_model.MetricsBucket, rnd *rand.Rand) [
 		Meta:             s.agent.metricStorage,
 		Rand:             rnd,
 		DiscardF:         func(key data_model.Key, _ *data_model.MultiItem, _ uint32) { delete(bucket.MultiItems, key) }, // remove from map
+		Explain:          &s.agent.samplingExplain,
 	})
 	for k, item := range bucket.MultiItems {
 		whaleWeight := item.FinishStringTop(config.StringTopCountSend) // all excess items are baked into Tail
@@ -271,6 +272,7 @@ func (s *Shard) sampleBucket(bucket *data_model.MetricsBucket, rnd *rand.Rand) [
 			WhaleWeight: whaleWeight,
 			Size:        sz,
 			MetricID:    accountMetric,
+			BucketTs:    bucket.Time,
 		})
 	}
 	numShards := s.agent.NumShards()
@@ -303,6 +305,12 @@ func (s *Shard) sampleBucket(bucket *data_model.MetricsBucket, rnd *rand.Rand) [
 		key = data_model.Key{Metric: format.BuiltinMetricIDSrcSamplingSizeBytes, Keys: [16]int32{0, s.agent.componentTag, format.TagValueIDSamplingDecisionDiscard, k[0], k[1], k[2]}}
 		mi = data_model.MapKeyItemMultiItem(&bucket.MultiItems, key, config.StringTopCapacity, nil, nil)
 		mi.Tail.Value.Merge(&v.SumSizeDiscard)
+		// bytes kept by metric sampling policy
+		if v.SumSizeKeepPolicy.Counter != 0 {
+			key = data_model.Key{Metric: format.BuiltinMetricIDSrcSamplingSizeBytes, Keys: [16]int32{0, s.agent.componentTag, format.TagValueIDSamplingDecisionKeepPolicy, k[0], k[1], k[2]}}
+			mi = data_model.MapKeyItemMultiItem(&bucket.MultiItems, key, config.StringTopCapacity, nil, nil)
+			mi.Tail.Value.Merge(&v.SumSizeKeepPolicy)
+		}
 	}
 	// report budget used
 	budgetKey := data_model.Key{Metric: format.BuiltinMetricIDSrcSamplingBudget, Keys: [16]int32{0, s.agent.componentTag}}
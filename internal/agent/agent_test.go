@@ -187,7 +187,7 @@ func Benchmark_sampleFactorDeterministic(b *testing.B) {
 		k.Metric = int32(i & 2047)
 		k.Keys[14]++
 		k.Keys[0] = int32(i)
-		_, ok := data_model.SampleFactorDeterministic(sampleFactors, k, uint32(i))
+		_, ok := data_model.SampleFactorDeterministic(sampleFactors, k, uint32(i), nil)
 		if ok {
 			result++
 		}
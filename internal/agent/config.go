@@ -39,11 +39,6 @@ type Config struct {
 	Cluster                string
 	SkipShards             int // if cluster is extended, first shard might be almost full, so we can skip them for some time.
 
-	// "remote write" was never used (so never tested) and was dropped
-	RemoteWriteEnabled bool
-	RemoteWriteAddr    string
-	RemoteWritePath    string
-
 	AutoCreate           bool
 	DisableRemoteConfig  bool
 	DisableNoSampleAgent bool
@@ -65,9 +60,6 @@ func DefaultConfig() Config {
 		KeepAliveSuccessTimeout:          time.Second * 5, // aggregator puts keep-alive requests in a bucket most soon to be inserted, so this is larger than strictly required
 		SaveSecondsImmediately:           false,
 		StatsHouseEnv:                    "production",
-		RemoteWriteEnabled:               false,
-		RemoteWriteAddr:                  ":13380",
-		RemoteWritePath:                  "/write",
 		AutoCreate:                       true,
 		DisableRemoteConfig:              false,
 		DisableNoSampleAgent:             false,
@@ -90,10 +82,6 @@ func (c *Config) Bind(f *flag.FlagSet, d Config, legacyVerb bool) {
 	f.BoolVar(&c.SaveSecondsImmediately, "save-seconds-immediately", d.SaveSecondsImmediately, "Save data to disk as soon as second is ready. When false, data is saved after first unsuccessful send.")
 	f.StringVar(&c.StatsHouseEnv, "statshouse-env", d.StatsHouseEnv, "Fill key0 with this value in built-in statistics. Only 'production' and 'staging' values are allowed.")
 
-	f.BoolVar(&c.RemoteWriteEnabled, "remote-write-enabled", d.RemoteWriteEnabled, "Serve prometheus remote write endpoint (deprecated).")
-	f.StringVar(&c.RemoteWriteAddr, "remote-write-addr", d.RemoteWriteAddr, "Prometheus remote write listen address (deprecated).")
-	f.StringVar(&c.RemoteWritePath, "remote-write-path", d.RemoteWritePath, "Prometheus remote write path (deprecated).")
-
 	if !legacyVerb {
 		f.BoolVar(&c.AutoCreate, "auto-create", d.AutoCreate, "Enable metric auto-create.")
 		f.BoolVar(&c.DisableRemoteConfig, "disable-remote-config", d.DisableRemoteConfig, "Disable remote configuration.")
@@ -29,6 +29,7 @@ import (
 	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
 	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
 	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/mapping"
 	"github.com/vkcom/statshouse/internal/metajournal"
 	"github.com/vkcom/statshouse/internal/pcache"
 	"github.com/vkcom/statshouse/internal/util"
@@ -99,8 +100,12 @@ type (
 		testConnection *TestConnection
 		tagsMapper     *TagsMapper
 
-		scrape     *scrapeServer
-		autoCreate *autoCreate
+		scrape      *scrapeServer
+		autoCreate  *autoCreate
+		remoteWrite *remoteWrite // nil if mirroring is off
+
+		namespaceQuota  *data_model.NamespaceQuota
+		samplingExplain data_model.SamplerExplainLog
 	}
 	BuiltInStatRecord struct {
 		Key  data_model.Key
@@ -166,6 +171,13 @@ func RunAggregator(dc *pcache.DiskCache, storageDir string, listenAddr string, a
 	}
 	log.Printf("success autoconfiguration in cluster %q, localShard=%d localReplica=%d address list is (%q)", config.Cluster, shardKey, replicaKey, strings.Join(addresses, ","))
 
+	if config.InsertFileDir != "" {
+		if err := os.MkdirAll(config.InsertFileDir, os.ModePerm); err != nil {
+			return fmt.Errorf("failed to create --insert-file-dir %q: %v", config.InsertFileDir, err)
+		}
+		log.Printf("[warning] inserting into files in %q instead of clickhouse", config.InsertFileDir)
+	}
+
 	metadataClient := &tlmetadata.Client{
 		Client:  rpc.NewClient(rpc.ClientWithLogf(log.Printf), rpc.ClientWithCryptoKey(aesPwd), rpc.ClientWithTrustedSubnetGroups(build.TrustedSubnetGroups())),
 		Network: config.MetadataNet,
@@ -199,6 +211,7 @@ func RunAggregator(dc *pcache.DiskCache, storageDir string, listenAddr string, a
 		buildArchTag:                format.GetBuildArchKey(runtime.GOARCH),
 		addresses:                   addresses,
 		tagMappingBootstrapResponse: tagMappingBootstrapResponse,
+		namespaceQuota:              data_model.NewNamespaceQuota(len(addresses) / 3),
 	}
 	a.h = tlstatshouse.Handler{
 		GetConfig2: a.handleGetConfig2,
@@ -223,6 +236,9 @@ func RunAggregator(dc *pcache.DiskCache, storageDir string, listenAddr string, a
 		RawAutoCreate: func(ctx context.Context, hctx *rpc.HandlerContext) error {
 			return a.autoCreate.handleAutoCreate(ctx, hctx)
 		},
+		GetSamplingExplain: func(_ context.Context, args tlstatshouse.GetSamplingExplain) (string, error) {
+			return a.samplingExplain.HandleGetSamplingExplain(args, time.Now())
+		},
 	}
 	if len(a.hostName) == 0 {
 		return fmt.Errorf("failed configuration - aggregator machine must have valid non-empty host name")
@@ -278,6 +294,14 @@ func RunAggregator(dc *pcache.DiskCache, storageDir string, listenAddr string, a
 
 	a.aggregatorHost = a.tagsMapper.mapTagAtStartup(a.hostName, format.BuiltinMetricNameBudgetAggregatorHost)
 
+	if config.RemoteWriteURL != "" {
+		tagValueInverse := mapping.NewTagsInverseCache(metricMetaLoader.LoadTagMappingInverse, "_a_"+a.config.Cluster, dc)
+		if a.remoteWrite, err = newRemoteWrite(config, storageDir, a.metricStorage, tagValueInverse); err != nil {
+			return fmt.Errorf("failed to start remote write: %v", err)
+		}
+		a.remoteWrite.run()
+	}
+
 	a.estimator.Init(config.CardinalityWindow, a.config.MaxCardinality/len(addresses))
 
 	now := time.Now()
@@ -290,6 +314,9 @@ func RunAggregator(dc *pcache.DiskCache, storageDir string, listenAddr string, a
 		go a.goSend(i)
 	}
 	go a.goInternalLog()
+	if a.metricPurgeEnabled() {
+		go a.goPurgeDeletedMetrics(metricMetaLoader)
+	}
 
 	sh2.Run(a.aggregatorHost, a.shardKey, a.replicaKey)
 
@@ -504,9 +531,16 @@ func selectShardReplicaImpl(httpClient *http.Client, khAddr string, cluster stri
 	return 0, 0, nil, fmt.Errorf("HTTP get from clickhouse %q for cluster %q returned body with no local replicas - %q", khAddr, cluster, string(body))
 }
 
+func (a *Aggregator) newStorageWriter() storageWriter {
+	if a.config.InsertFileDir != "" {
+		return newFileWriter(a.config.InsertFileDir)
+	}
+	return newClickhouseWriter(a.config.KHAddr)
+}
+
 func (a *Aggregator) goSend(senderID int) {
 	rnd := rand.New()
-	httpClient := makeHTTPClient(data_model.ClickHouseTimeout)
+	storage := a.newStorageWriter() // each sender has its own connections
 
 	var aggBuckets []*aggregatorBucket
 	var bodyStorage []byte
@@ -531,6 +565,7 @@ func (a *Aggregator) goSend(senderID int) {
 
 		aggBuckets = append(aggBuckets, aggBucket) // first bucket is always recent
 		a.estimator.ReportHourCardinality(aggBucket.time, aggBucket.usedMetrics, &aggBucket.shards[0].multiItems, a.aggregatorHost, a.shardKey, a.replicaKey, len(a.addresses))
+		a.estimator.ReportHourTagCardinality(aggBucket.time, &aggBucket.shards[0].multiItems, a.aggregatorHost, a.shardKey, a.replicaKey)
 
 		recentContributors := aggBucket.contributorsOriginal.Counter + aggBucket.contributorsSpare.Counter
 		historicContributors := 0.0
@@ -580,10 +615,14 @@ func (a *Aggregator) goSend(senderID int) {
 				break
 			}
 		}
-		bodyStorage = a.RowDataMarshalAppendPositions(aggBuckets, rnd, bodyStorage[:0])
+		var remoteWriteBatch *remoteWriteBatch
+		if a.remoteWrite != nil {
+			remoteWriteBatch = a.remoteWrite.newBatch()
+		}
+		bodyStorage = a.RowDataMarshalAppendPositions(storage, aggBuckets, rnd, bodyStorage[:0], remoteWriteBatch)
 
 		// Never empty, because adds value stats
-		status, exception, dur, sendErr := sendToClickhouse(httpClient, a.config.KHAddr, getTableDesc(), bodyStorage)
+		status, exception, dur, sendErr := storage.insert(aggBuckets, bodyStorage)
 		a.mu.Lock()
 		if willInsertHistoric {
 			a.historicSenders--
@@ -601,6 +640,8 @@ func (a *Aggregator) goSend(senderID int) {
 				Code:        data_model.RPCErrorInsert,
 				Description: sendErr.Error(),
 			}
+		} else if remoteWriteBatch != nil {
+			a.remoteWrite.push(remoteWriteBatch)
 		}
 
 		for i, b := range aggBuckets {
@@ -113,7 +113,7 @@ func (p *metricIndexCache) skips(metricID int32) (skipMaxHost bool, skipMinHost
 	return false, false, false
 }
 
-func appendKeys(res []byte, k data_model.Key, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
+func appendKeys(res []byte, k data_model.Key, metricCache *metricIndexCache) []byte {
 	var tmp [4 + 4 + 1 + 4 + format.MaxTags*4]byte // metric, prekey, prekey_set, time
 	binary.LittleEndian.PutUint32(tmp[0:], uint32(k.Metric))
 	prekeyIndex, prekeyOnly := metricCache.getPrekeyIndex(k.Metric)
@@ -126,31 +126,34 @@ func appendKeys(res []byte, k data_model.Key, metricCache *metricIndexCache, use
 		}
 	}
 	binary.LittleEndian.PutUint32(tmp[9:], k.Timestamp)
-	if usedTimestamps != nil { // do not update map when writing map itself
-		usedTimestamps[k.Timestamp] = struct{}{} // TODO - optimize out bucket timestamp
-	}
 	for ki, key := range k.Keys {
 		binary.LittleEndian.PutUint32(tmp[13+ki*4:], uint32(key))
 	}
 	return append(res, tmp[:]...)
 }
 
+func useTimestamp(usedTimestamps map[uint32]struct{}, ts uint32) {
+	if usedTimestamps != nil { // do not update map when writing map itself
+		usedTimestamps[ts] = struct{}{} // TODO - optimize out bucket timestamp
+	}
+}
+
 // TODO - badges are badly designed for now. Should be redesigned some day.
 // We propose to move them inside metric with env=-1,-2,etc.
 // So we can select badges for free by adding || (env < 0) to requests, then filtering result rows
 // Also we must select both count and sum, then process them separately for each badge kind
 
-func appendMultiBadge(res []byte, k data_model.Key, v *data_model.MultiItem, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
+func appendMultiBadge(w storageWriter, res []byte, k data_model.Key, v *data_model.MultiItem, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
 	if k.Metric >= 0 { // fastpath
 		return res
 	}
 	for _, t := range v.Top {
-		res = appendBadge(res, k, t.Value, metricCache, usedTimestamps)
+		res = appendBadge(w, res, k, t.Value, metricCache, usedTimestamps)
 	}
-	return appendBadge(res, k, v.Tail.Value, metricCache, usedTimestamps)
+	return appendBadge(w, res, k, v.Tail.Value, metricCache, usedTimestamps)
 }
 
-func appendBadge(res []byte, k data_model.Key, v data_model.ItemValue, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
+func appendBadge(w storageWriter, res []byte, k data_model.Key, v data_model.ItemValue, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
 	if k.Metric >= 0 { // fastpath
 		return res
 	}
@@ -171,22 +174,23 @@ func appendBadge(res []byte, k data_model.Key, v data_model.ItemValue, metricCac
 			format.TagValueIDSrcIngestionStatusWarnDeprecatedStop,
 			format.TagValueIDSrcIngestionStatusWarnMapTagSetTwice,
 			format.TagValueIDSrcIngestionStatusWarnOldCounterSemantic,
-			format.TagValueIDSrcIngestionStatusWarnMapInvalidRawTagValue:
-			return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeIngestionWarnings, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
+			format.TagValueIDSrcIngestionStatusWarnMapInvalidRawTagValue,
+			format.TagValueIDSrcIngestionStatusWarnMetricDeprecated:
+			return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeIngestionWarnings, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
 		}
-		return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeIngestionErrors, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
+		return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeIngestionErrors, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
 	case format.BuiltinMetricIDAgentSamplingFactor:
-		return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAgentSamplingFactor, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
+		return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAgentSamplingFactor, k.Keys[1]}}, "", v, metricCache, usedTimestamps)
 	case format.BuiltinMetricIDAggSamplingFactor:
-		return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAggSamplingFactor, k.Keys[4]}}, "", v, metricCache, usedTimestamps)
+		return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAggSamplingFactor, k.Keys[4]}}, "", v, metricCache, usedTimestamps)
 	case format.BuiltinMetricIDAggMappingCreated:
 		if k.Keys[5] == format.TagValueIDAggMappingCreatedStatusOK ||
 			k.Keys[5] == format.TagValueIDAggMappingCreatedStatusCreated {
 			return res
 		}
-		return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAggMappingErrors, k.Keys[4]}}, "", v, metricCache, usedTimestamps)
+		return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeAggMappingErrors, k.Keys[4]}}, "", v, metricCache, usedTimestamps)
 	case format.BuiltinMetricIDAggBucketReceiveDelaySec:
-		return appendValueStat(res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeContributors, 0}}, "", v, metricCache, usedTimestamps)
+		return appendValueStat(w, res, data_model.Key{Timestamp: ts, Metric: format.BuiltinMetricIDBadges, Keys: [16]int32{0, format.TagValueIDBadgeContributors, 0}}, "", v, metricCache, usedTimestamps)
 	}
 	return res
 }
@@ -201,12 +205,26 @@ func appendAggregates(res []byte, c float64, mi float64, ma float64, su float64,
 	return append(res, tmp[:]...)
 }
 
-func appendValueStat(res []byte, key data_model.Key, skey string, v data_model.ItemValue, cache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
+func appendValueStat(w storageWriter, res []byte, key data_model.Key, skey string, v data_model.ItemValue, cache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
 	if v.Counter <= 0 { // We have lots of built-in  counters which are normally 0
 		return res
 	}
+	useTimestamp(usedTimestamps, key.Timestamp)
+	return w.appendValueStatRow(res, key, skey, v, cache)
+}
+
+func appendSimpleValueStat(w storageWriter, res []byte, key data_model.Key, v float64, count float64, hostTag int32, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
+	return appendValueStat(w, res, key, "", data_model.SimpleItemValue(v, count, hostTag), metricCache, usedTimestamps)
+}
+
+func (w *clickhouseWriter) appendRow(res []byte, k data_model.Key, skey string, value *data_model.MultiValue, sf float64, cache *metricIndexCache) []byte {
+	res = appendKeys(res, k, cache)
+	return multiValueMarshal(k.Metric, cache, res, value, skey, sf)
+}
+
+func (w *clickhouseWriter) appendValueStatRow(res []byte, key data_model.Key, skey string, v data_model.ItemValue, cache *metricIndexCache) []byte {
 	// for explanation of insert logic, see multiValueMarshal below
-	res = appendKeys(res, key, cache, usedTimestamps)
+	res = appendKeys(res, key, cache)
 	skipMaxHost, skipMinHost, skipSumSquare := cache.skips(key.Metric)
 	if v.ValueSet {
 		res = appendAggregates(res, v.Counter, v.ValueMin, v.ValueMax, v.ValueSum, zeroIfTrue(v.ValueSumSquare, skipSumSquare))
@@ -240,10 +258,6 @@ func appendValueStat(res []byte, key data_model.Key, skey string, v data_model.I
 	return res
 }
 
-func appendSimpleValueStat(res []byte, key data_model.Key, v float64, count float64, hostTag int32, metricCache *metricIndexCache, usedTimestamps map[uint32]struct{}) []byte {
-	return appendValueStat(res, key, "", data_model.SimpleItemValue(v, count, hostTag), metricCache, usedTimestamps)
-}
-
 func multiValueMarshal(metricID int32, cache *metricIndexCache, res []byte, value *data_model.MultiValue, skey string, sf float64) []byte {
 	skipMaxHost, skipMinHost, skipSumSquare := cache.skips(metricID)
 	counter := value.Value.Counter * sf
@@ -289,7 +303,35 @@ type insertSize struct {
 	builtin     int
 }
 
-func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket, rnd *rand.Rand, res []byte) []byte {
+// returns exceeded quota tag, or 0 if series must be inserted
+func (a *Aggregator) checkNamespaceQuota(batch *data_model.NamespaceQuotaBatch, k data_model.Key, item *data_model.MultiItem, size int, bucketTs uint32) int32 {
+	meta := a.metricStorage.GetMetaMetric(k.Metric)
+	if meta == nil {
+		return 0
+	}
+	ns := a.metricStorage.GetNamespace(meta.NamespaceID)
+	if ns == nil || !ns.HasQuota() {
+		return 0
+	}
+	return batch.Accept(ns, &k, insertRowCount(item), size, bucketTs)
+}
+
+// must match rows written by insertItem, empty tail and string top values are skipped there
+func insertRowCount(item *data_model.MultiItem) int {
+	rows := 0
+	if !item.Tail.Empty() {
+		rows++
+	}
+	for _, value := range item.Top {
+		if !value.Empty() {
+			rows++
+		}
+	}
+	return rows
+}
+
+// if rwBatch is not nil, kept items of mirrored metrics are collected there with sampling factors applied
+func (a *Aggregator) RowDataMarshalAppendPositions(w storageWriter, buckets []*aggregatorBucket, rnd *rand.Rand, res []byte, rwBatch *remoteWriteBatch) []byte {
 	startTime := time.Now()
 	// sanity check, nothing to marshal if there is no buckets
 	if len(buckets) < 1 {
@@ -321,9 +363,8 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 
 		resPos := len(res)
 		if !item.Tail.Empty() { // only tail
-			res = appendKeys(res, k, metricCache, usedTimestamps)
-
-			res = multiValueMarshal(k.Metric, metricCache, res, &item.Tail, "", sf)
+			useTimestamp(usedTimestamps, k.Timestamp)
+			res = w.appendRow(res, k, "", &item.Tail, sf, metricCache)
 
 			if k.Metric < 0 {
 				is.builtin += len(res) - resPos
@@ -346,8 +387,8 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 				continue
 			}
 			// We have no badges for string tops
-			res = appendKeys(res, k, metricCache, usedTimestamps)
-			res = multiValueMarshal(k.Metric, metricCache, res, value, skey, sf)
+			useTimestamp(usedTimestamps, k.Timestamp)
+			res = w.appendRow(res, k, skey, value, sf, metricCache)
 		}
 		if k.Metric < 0 {
 			is.builtin += len(res) - resPos
@@ -356,6 +397,9 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 			is.stringTops += len(res) - resPos
 		}
 		addSizes(bucketTs, is)
+		if rwBatch != nil {
+			rwBatch.add(k, item, sf)
+		}
 	}
 	var itemsCount int
 	for _, b := range buckets {
@@ -370,8 +414,11 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 		SampleKeys:       config.SampleKeys,
 		Rand:             rnd,
 		KeepF:            func(k data_model.Key, item *data_model.MultiItem, bt uint32) { insertItem(k, item, item.SF, bt) },
+		Explain:          &a.samplingExplain,
 	})
 	var samplerStat data_model.SamplerStatistics
+	quotaBatch := a.namespaceQuota.NewBatch()
+	quotaRejected := map[int32]float64{} // metric -> rows
 	// First, sample with global sampling factors, depending on cardinality. Collect relative sizes for 2nd stage sampling below.
 	// TODO - actual sampleFactors are empty due to code commented out in estimator.go
 	for _, b := range buckets {
@@ -381,7 +428,7 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 				whaleWeight := item.FinishStringTop(config.StringTopCountInsert) // all excess items are baked into Tail
 
 				resPos := len(res)
-				res = appendMultiBadge(res, k, item, metricCache, usedTimestamps)
+				res = appendMultiBadge(w, res, k, item, metricCache, usedTimestamps)
 				is.builtin += len(res) - resPos
 
 				accountMetric := k.Metric
@@ -408,6 +455,12 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 					}
 				}
 				sz := item.RowBinarySizeEstimate()
+				if k.Metric >= 0 {
+					if quota := a.checkNamespaceQuota(quotaBatch, k, item, sz, b.time); quota != 0 {
+						quotaRejected[k.Metric] += float64(insertRowCount(item))
+						continue
+					}
+				}
 				sampler.Add(data_model.SamplingMultiItemPair{
 					Key:         k,
 					Item:        item,
@@ -452,14 +505,20 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 		key = a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingSizeBytes, [16]int32{0, historicTag, format.TagValueIDSamplingDecisionDiscard, k[0], k[1], k[2]})
 		mi = data_model.MultiItem{Tail: data_model.MultiValue{Value: v.SumSizeDiscard}}
 		insertItem(key, &mi, 1, buckets[0].time)
+		// bytes kept by metric sampling policy
+		if v.SumSizeKeepPolicy.Counter != 0 {
+			key = a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingSizeBytes, [16]int32{0, historicTag, format.TagValueIDSamplingDecisionKeepPolicy, k[0], k[1], k[2]})
+			mi = data_model.MultiItem{Tail: data_model.MultiValue{Value: v.SumSizeKeepPolicy}}
+			insertItem(key, &mi, 1, buckets[0].time)
+		}
 	}
 
 	for _, s := range samplerStat.GetSampleFactors(nil) {
 		k := s.Metric
 		sf := float64(s.Value)
 		key := a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingFactor, [16]int32{0, 0, 0, 0, k, format.TagValueIDAggSamplingFactorReasonInsertSize})
-		res = appendBadge(res, key, data_model.SimpleItemValue(sf, 1, a.aggregatorHost), metricCache, usedTimestamps)
-		res = appendSimpleValueStat(res, key, sf, 1, a.aggregatorHost, metricCache, usedTimestamps)
+		res = appendBadge(w, res, key, data_model.SimpleItemValue(sf, 1, a.aggregatorHost), metricCache, usedTimestamps)
+		res = appendSimpleValueStat(w, res, key, sf, 1, a.aggregatorHost, metricCache, usedTimestamps)
 	}
 
 	// report budget used
@@ -473,55 +532,107 @@ func (a *Aggregator) RowDataMarshalAppendPositions(buckets []*aggregatorBucket,
 		item.Tail.Value.AddValue(v)
 		insertItem(key, &item, 1, buckets[0].time)
 	}
-	res = appendSimpleValueStat(res, a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingMetricCount, [16]int32{0, historicTag}),
+	res = appendSimpleValueStat(w, res, a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingMetricCount, [16]int32{0, historicTag}),
 		float64(len(samplerStat.Metrics)), 1, a.aggregatorHost, metricCache, usedTimestamps)
 
+	// report namespace quotas
+	for metric, rows := range quotaRejected {
+		key := a.aggKey(recentTime, format.BuiltinMetricIDIngestionStatus, [16]int32{0, metric, format.TagValueIDSrcIngestionStatusErrNamespaceQuota})
+		item := data_model.MultiItem{}
+		item.Tail.AddCounterHost(rows, a.aggregatorHost)
+		res = appendMultiBadge(w, res, key, &item, metricCache, usedTimestamps)
+		insertItem(key, &item, 1, buckets[0].time)
+	}
+	for k, v := range quotaBatch.Stat {
+		for _, s := range [...]struct {
+			decision int32
+			value    float64
+		}{{format.TagValueIDSamplingDecisionKeep, v.Keep}, {format.TagValueIDSamplingDecisionDiscard, v.Discard}} {
+			if s.value == 0 {
+				continue
+			}
+			key := a.aggKey(recentTime, format.BuiltinMetricIDAggNamespaceQuotaUsage, [16]int32{0, historicTag, k[0], k[1], s.decision})
+			item := data_model.MultiItem{}
+			item.Tail.Value.AddValue(s.value)
+			insertItem(key, &item, 1, buckets[0].time)
+		}
+	}
+
 	appendInsertSizeStats := func(time uint32, is insertSize, historicTag int32) int {
-		res = appendSimpleValueStat(res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeCounter}),
+		res = appendSimpleValueStat(w, res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeCounter}),
 			float64(is.counters), 1, a.aggregatorHost, metricCache, usedTimestamps)
-		res = appendSimpleValueStat(res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeValue}),
+		res = appendSimpleValueStat(w, res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeValue}),
 			float64(is.values), 1, a.aggregatorHost, metricCache, usedTimestamps)
-		res = appendSimpleValueStat(res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizePercentiles}),
+		res = appendSimpleValueStat(w, res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizePercentiles}),
 			float64(is.percentiles), 1, a.aggregatorHost, metricCache, usedTimestamps)
-		res = appendSimpleValueStat(res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeUnique}),
+		res = appendSimpleValueStat(w, res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeUnique}),
 			float64(is.uniques), 1, a.aggregatorHost, metricCache, usedTimestamps)
 		sizeBefore := len(res)
-		res = appendSimpleValueStat(res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeStringTop}),
+		res = appendSimpleValueStat(w, res, a.aggKey(time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, historicTag, format.TagValueIDSizeStringTop}),
 			float64(is.stringTops), 1, a.aggregatorHost, metricCache, usedTimestamps)
 		return len(res) - sizeBefore
 	}
 	// we assume that builtin size metric takes as much bytes as string top size
 	estimatedSize := appendInsertSizeStats(recentTime, insertSizes[buckets[0].time], format.TagValueIDConveyorRecent)
 
-	res = appendSimpleValueStat(res, a.aggKey(recentTime, format.BuiltinMetricIDAggContributors, [16]int32{}),
+	res = appendSimpleValueStat(w, res, a.aggKey(recentTime, format.BuiltinMetricIDAggContributors, [16]int32{}),
 		float64(numContributors), 1, a.aggregatorHost, metricCache, usedTimestamps)
 
 	insertTimeUnix := uint32(time.Now().Unix()) // same quality as timestamp from advanceBuckets, can be larger or smaller
 	for t := range usedTimestamps {
 		key := data_model.Key{Timestamp: insertTimeUnix, Metric: format.BuiltinMetricIDContributorsLog, Keys: [16]int32{0, int32(t)}}
-		res = appendSimpleValueStat(res, key, float64(insertTimeUnix)-float64(t), 1, a.aggregatorHost, metricCache, nil)
+		res = appendSimpleValueStat(w, res, key, float64(insertTimeUnix)-float64(t), 1, a.aggregatorHost, metricCache, nil)
 		key = data_model.Key{Timestamp: t, Metric: format.BuiltinMetricIDContributorsLogRev, Keys: [16]int32{0, int32(insertTimeUnix)}}
-		res = appendSimpleValueStat(res, key, float64(insertTimeUnix)-float64(t), 1, a.aggregatorHost, metricCache, nil)
+		res = appendSimpleValueStat(w, res, key, float64(insertTimeUnix)-float64(t), 1, a.aggregatorHost, metricCache, nil)
 	}
 	dur := time.Since(startTime)
-	res = appendSimpleValueStat(res, a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingTime, [16]int32{0, 0, 0, 0, historicTag}),
+	res = appendSimpleValueStat(w, res, a.aggKey(recentTime, format.BuiltinMetricIDAggSamplingTime, [16]int32{0, 0, 0, 0, historicTag}),
 		float64(dur.Seconds()), 1, a.aggregatorHost, metricCache, usedTimestamps)
 
 	var recentBuiltinSize int = insertSizes[buckets[0].time].builtin + len(res) - resPos + estimatedSize
-	res = appendSimpleValueStat(res, a.aggKey(recentTime, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, format.TagValueIDConveyorRecent, format.TagValueIDSizeBuiltIn}),
+	res = appendSimpleValueStat(w, res, a.aggKey(recentTime, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, format.TagValueIDConveyorRecent, format.TagValueIDSizeBuiltIn}),
 		float64(recentBuiltinSize), 1, a.aggregatorHost, metricCache, usedTimestamps)
 
 	for _, b := range buckets[1:] {
 		resPos = len(res)
 		appendInsertSizeStats(b.time, insertSizes[b.time], format.TagValueIDConveyorHistoric)
 		var historicBuiltinSize int = insertSizes[b.time].builtin + len(res) - resPos + estimatedSize
-		res = appendSimpleValueStat(res, a.aggKey(b.time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, format.TagValueIDConveyorHistoric, format.TagValueIDSizeBuiltIn}),
+		res = appendSimpleValueStat(w, res, a.aggKey(b.time, format.BuiltinMetricIDAggInsertSize, [16]int32{0, 0, 0, 0, format.TagValueIDConveyorHistoric, format.TagValueIDSizeBuiltIn}),
 			float64(historicBuiltinSize), 1, a.aggregatorHost, metricCache, usedTimestamps)
 	}
 
 	return res
 }
 
+// storageWriter marshals rows of finished buckets and inserts them into storage.
+// Sampling, badges and insert size accounting are the same for all storages,
+// so insert sizes reported by built-in metrics are sizes in storage format.
+type storageWriter interface {
+	appendRow(res []byte, k data_model.Key, skey string, value *data_model.MultiValue, sf float64, cache *metricIndexCache) []byte
+	appendValueStatRow(res []byte, k data_model.Key, skey string, v data_model.ItemValue, cache *metricIndexCache) []byte
+	// first bucket is recent, all other are historic
+	insert(buckets []*aggregatorBucket, body []byte) (status int, exception int, elapsed float64, err error)
+}
+
+// default storage, RowBinary over clickhouse HTTP interface
+type clickhouseWriter struct {
+	httpClient *http.Client
+	khAddr     string
+	table      string
+}
+
+func newClickhouseWriter(khAddr string) *clickhouseWriter {
+	return &clickhouseWriter{
+		httpClient: makeHTTPClient(data_model.ClickHouseTimeout),
+		khAddr:     khAddr,
+		table:      getTableDesc(),
+	}
+}
+
+func (w *clickhouseWriter) insert(_ []*aggregatorBucket, body []byte) (status int, exception int, elapsed float64, err error) {
+	return sendToClickhouse(w.httpClient, w.khAddr, w.table, body)
+}
+
 func makeHTTPClient(timeout time.Duration) *http.Client {
 	return &http.Client{
 		Transport: &http.Transport{
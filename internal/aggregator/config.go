@@ -30,7 +30,8 @@ type ConfigAggregator struct {
 	HistoricInserters  int
 	InsertHistoricWhen int
 
-	KHAddr string
+	KHAddr        string
+	InsertFileDir string // if set, inserts are written into files there instead of clickhouse
 
 	CardinalityWindow int
 	MaxCardinality    int
@@ -52,6 +53,10 @@ type ConfigAggregator struct {
 	AutoCreate                 bool
 	AutoCreateDefaultNamespace bool
 	DisableRemoteConfig        bool
+
+	RemoteWriteURL         string // Prometheus remote write endpoint, empty means mirroring is off
+	RemoteWriteNamespaces  string // comma-separated
+	RemoteWriteMaxDiskSize int64
 }
 
 func DefaultConfigAggregator() ConfigAggregator {
@@ -67,6 +72,8 @@ func DefaultConfigAggregator() ConfigAggregator {
 		MetadataNet:          "tcp4",
 		MetadataAddr:         "127.0.0.1:2442",
 
+		RemoteWriteMaxDiskSize: 1 << 30,
+
 		ConfigAggregatorRemote: ConfigAggregatorRemote{
 			InsertBudget:         400,
 			InsertBudget100:      2500,
@@ -118,10 +125,28 @@ func ValidateConfigAggregator(c ConfigAggregator) error {
 	if c.HistoricInserters > 4 { // Otherwise batching during historic inserts will become too small
 		return fmt.Errorf("--historic-inserters (%d) must be <= 4", c.HistoricInserters)
 	}
+	if c.RemoteWriteURL != "" {
+		if len(c.remoteWriteNamespaces()) == 0 {
+			return fmt.Errorf("--remote-write-namespaces must be set when --remote-write-url is set")
+		}
+		if c.RemoteWriteMaxDiskSize <= 0 {
+			return fmt.Errorf("--remote-write-max-disk-size (%d) must be > 0", c.RemoteWriteMaxDiskSize)
+		}
+	}
 
 	return c.ConfigAggregatorRemote.Validate()
 }
 
+func (c *ConfigAggregator) remoteWriteNamespaces() map[string]struct{} {
+	result := map[string]struct{}{}
+	for _, ns := range strings.Split(c.RemoteWriteNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			result[ns] = struct{}{}
+		}
+	}
+	return result
+}
+
 func (c *ConfigAggregatorRemote) Validate() error {
 	if c.InsertBudget < 1 {
 		return fmt.Errorf("insert-budget (%d) must be >= 1", c.InsertBudget)
@@ -0,0 +1,103 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+// Data of deleted metrics is deleted by the first replica of the first shard for all shards at once with distributed DDL.
+// Then metric is saved with PurgeTime set, so deletion is recorded in entity history.
+
+const metricPurgeMetadata = `{"user_email":"aggregator"}`
+
+var metricPurgeTables = []string{
+	"statshouse_value_1s", "statshouse_value_1s_prekey",
+	"statshouse_value_1m", "statshouse_value_1m_prekey",
+	"statshouse_value_1h", "statshouse_value_1h_prekey",
+}
+
+func (a *Aggregator) metricPurgeEnabled() bool {
+	return a.shardKey == 1 && a.replicaKey == 1 && a.config.KHAddr != "" && a.config.InsertFileDir == ""
+}
+
+func (a *Aggregator) goPurgeDeletedMetrics(loader *metajournal.MetricMetaLoader) {
+	httpClient := makeHTTPClient(data_model.ClickHouseTimeout)
+	for {
+		time.Sleep(data_model.MetricPurgeCheckInterval)
+		now := time.Now()
+		for _, m := range a.metricStorage.GetMetaMetricList(true) {
+			if !metricPurgeDue(m, now) {
+				continue
+			}
+			if err := a.purgeMetric(httpClient, loader, *m); err != nil {
+				a.appendInternalLog("metric_purge_error", "", strconv.Itoa(int(m.MetricID)), "", "", "", "", err.Error()) // will retry next time
+				log.Printf("[error] failed to delete data of metric %q: %v", m.Name, err)
+			}
+		}
+	}
+}
+
+// data is kept at least MetricPurgeDelay after metric was deleted, metric can be restored meanwhile,
+// other edits of deleted metric do not postpone deletion
+func metricPurgeDue(m *format.MetricMetaValue, now time.Time) bool {
+	return m.MetricID > 0 && m.State == format.MetricStateDeleted && m.PurgeTime == 0 && m.StateTime != 0 &&
+		now.Sub(time.Unix(int64(m.StateTime), 0)) >= data_model.MetricPurgeDelay
+}
+
+func (a *Aggregator) purgeMetric(httpClient *http.Client, loader *metajournal.MetricMetaLoader, m format.MetricMetaValue) error {
+	for _, table := range metricPurgeTables {
+		query := fmt.Sprintf("ALTER TABLE %s ON CLUSTER %s DELETE WHERE metric = %d", table, a.config.Cluster, m.MetricID)
+		if a.withoutCluster { // demo runs have no local tables
+			query = fmt.Sprintf("ALTER TABLE %s_dist DELETE WHERE metric = %d", table, m.MetricID)
+		}
+		if err := execClickhouse(httpClient, a.config.KHAddr, query); err != nil {
+			return err
+		}
+	}
+	ctx := context.Background()
+	if _, _, _, err := loader.ResetFlood(ctx, m.Name, 0); err != nil { // mapping budget is not needed anymore
+		return err
+	}
+	m.PurgeTime = uint32(time.Now().Unix())
+	if _, err := loader.SaveMetric(ctx, m, metricPurgeMetadata); err != nil {
+		return err
+	}
+	a.appendInternalLog("metric_purge", "", strconv.Itoa(int(m.MetricID)), "", "", "", "", m.Name)
+	return nil
+}
+
+func execClickhouse(httpClient *http.Client, khAddr string, query string) error {
+	URL := fmt.Sprintf("http://%s/?query=%s", khAddr, url.QueryEscape(query))
+	req, err := http.NewRequest("POST", URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var partialMessage [1024]byte
+	partialMessageLen, _ := io.ReadFull(resp.Body, partialMessage[:])
+	_, _ = io.Copy(io.Discard, resp.Body) // keepalive
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%q failed (HTTP code %d, X-ClickHouse-Exception-Code: %s): %s", query, resp.StatusCode, resp.Header.Get("X-ClickHouse-Exception-Code"), partialMessage[:partialMessageLen])
+	}
+	return nil
+}
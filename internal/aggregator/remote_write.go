@@ -0,0 +1,369 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package aggregator
+
+// Remote write mirrors data of selected namespaces to Prometheus-compatible TSDB.
+// Items kept by sampler are collected during marshal, and after successful insert into clickhouse
+// tags are resolved to strings, request is marshalled and put into on-disk queue, so that
+// neither receiver downtime nor aggregator restart loses data. Queue is sent strictly in order.
+// When queue is over disk limit, the oldest requests are thrown out.
+//
+// Each item becomes several samples with timestamp of its second. Values are per bucket, not cumulative,
+// so all samples are gauges (and sent with gauge metadata), names avoid _count/_sum/_total suffixes
+// and quantile label of Prometheus counters and summaries, so that rate() is not applied to them by mistake
+//   <metric>_count_delta                  count for this bucket, multiplied by sampling factor
+//   <metric>_sum_delta, _min, _max        for value metrics, sum is multiplied by sampling factor
+//   <metric>_percentile{percentile="0.5"} for metrics with percentiles
+//   <metric>_unique                       for unique metrics, estimated number of unique values
+// Tags become labels named as in metric description, or "keyN" for tags without name.
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/vkcom/statshouse/internal/agent"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+	"github.com/vkcom/statshouse/internal/pcache"
+	"github.com/vkcom/statshouse/internal/vkgo/build"
+)
+
+const (
+	remoteWriteSendTimeout = 30 * time.Second
+	remoteWriteMinBackoff  = time.Second
+	remoteWriteMaxBackoff  = time.Minute
+	remoteWriteMaxPending  = 64 // inserts waiting for tag resolution, if receiver of this channel is stuck, we throw out data
+)
+
+var remoteWriteQuantiles = []float64{0.5, 0.9, 0.99}
+
+type remoteWriteItem struct {
+	key       data_model.Key
+	skey      string
+	meta      *format.MetricMetaValue
+	value     data_model.ItemValue // sampling factor applied
+	quantiles []float64            // values for remoteWriteQuantiles
+	unique    float64
+}
+
+// collected by single inserter, then owned by remoteWrite
+type remoteWriteBatch struct {
+	rw    *remoteWrite
+	items []remoteWriteItem
+
+	lastMetricID int32
+	lastMeta     *format.MetricMetaValue // nil if metric is not mirrored
+}
+
+type remoteWrite struct {
+	url           string
+	namespaces    map[string]struct{}
+	maxDiskSize   int64
+	httpClient    *http.Client
+	metricStorage *metajournal.MetricsStorage
+	tagValue      *pcache.Cache // ID -> string
+
+	batches   chan *remoteWriteBatch
+	diskQueue *agent.DiskBucketStorage
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []uint32 // requests in disk queue, oldest first
+	nextID uint32
+}
+
+func newRemoteWrite(config ConfigAggregator, storageDir string, metricStorage *metajournal.MetricsStorage, tagValue *pcache.Cache) (*remoteWrite, error) {
+	dirPath := filepath.Join(storageDir, "remote_write")
+	if err := os.MkdirAll(dirPath, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create remote write queue dir %q: %w", dirPath, err)
+	}
+	diskQueue, err := agent.MakeDiskBucketStorage(dirPath, 1, log.Printf)
+	if err != nil {
+		return nil, err
+	}
+	rw := &remoteWrite{
+		url:           config.RemoteWriteURL,
+		namespaces:    config.remoteWriteNamespaces(),
+		maxDiskSize:   config.RemoteWriteMaxDiskSize,
+		httpClient:    makeHTTPClient(remoteWriteSendTimeout),
+		metricStorage: metricStorage,
+		tagValue:      tagValue,
+		batches:       make(chan *remoteWriteBatch, remoteWriteMaxPending),
+		diskQueue:     diskQueue,
+		nextID:        1,
+	}
+	rw.cond = sync.NewCond(&rw.mu)
+	// requests are read in order they were written before restart
+	for id, ok := diskQueue.ReadNextTailSecond(0); ok; id, ok = diskQueue.ReadNextTailSecond(0) {
+		rw.queue = append(rw.queue, id)
+		rw.nextID = id + 1
+	}
+	if len(rw.queue) != 0 {
+		log.Printf("remote write: %d requests loaded from disk queue", len(rw.queue))
+	}
+	return rw, nil
+}
+
+func (rw *remoteWrite) run() {
+	go rw.goMarshal()
+	go rw.goSend()
+}
+
+func (rw *remoteWrite) newBatch() *remoteWriteBatch {
+	return &remoteWriteBatch{rw: rw, lastMetricID: format.BuiltinMetricIDIngestionStatus} // never mirrored
+}
+
+// called after successful insert, must not block inserter
+func (rw *remoteWrite) push(b *remoteWriteBatch) {
+	if len(b.items) == 0 {
+		return
+	}
+	select {
+	case rw.batches <- b:
+	default:
+		log.Printf("remote write: too many inserts waiting for tag resolution, throwing out %d items", len(b.items))
+	}
+}
+
+func (b *remoteWriteBatch) mirrored(metricID int32) *format.MetricMetaValue {
+	if metricID == b.lastMetricID {
+		return b.lastMeta
+	}
+	b.lastMetricID = metricID
+	b.lastMeta = nil
+	if metricID < 0 { // built-in metrics are not mirrored
+		return nil
+	}
+	meta := b.rw.metricStorage.GetMetaMetric(metricID)
+	if meta == nil {
+		return nil
+	}
+	_, namespace := format.SplitNamespace(meta.Name)
+	if namespace == "" {
+		namespace = format.BuiltInNamespaceDefault[format.BuiltinNamespaceIDDefault].Name
+	}
+	if _, ok := b.rw.namespaces[namespace]; ok {
+		b.lastMeta = meta
+	}
+	return b.lastMeta
+}
+
+func (b *remoteWriteBatch) add(k data_model.Key, item *data_model.MultiItem, sf float64) {
+	meta := b.mirrored(k.Metric)
+	if meta == nil {
+		return
+	}
+	if !item.Tail.Empty() {
+		b.addValue(k, "", meta, &item.Tail, sf)
+	}
+	for skey, value := range item.Top {
+		if !value.Empty() {
+			b.addValue(k, skey, meta, value, sf)
+		}
+	}
+}
+
+func (b *remoteWriteBatch) addValue(k data_model.Key, skey string, meta *format.MetricMetaValue, value *data_model.MultiValue, sf float64) {
+	it := remoteWriteItem{key: k, skey: skey, meta: meta, value: value.Value}
+	it.value.Counter *= sf
+	it.value.ValueSum *= sf
+	if meta.HasPercentiles && value.ValueTDigest != nil {
+		for _, q := range remoteWriteQuantiles {
+			it.quantiles = append(it.quantiles, value.ValueTDigest.Quantile(q))
+		}
+	}
+	if value.HLL.ItemsCount() != 0 {
+		it.unique = float64(value.HLL.Size(false))
+	}
+	b.items = append(b.items, it)
+}
+
+func (rw *remoteWrite) goMarshal() {
+	var req prompb.WriteRequest
+	var buf []byte
+	families := map[string]struct{}{}
+	for b := range rw.batches {
+		req.Timeseries = req.Timeseries[:0]
+		req.Metadata = req.Metadata[:0]
+		for i := range b.items {
+			req.Timeseries = rw.appendSeries(req.Timeseries, families, &b.items[i])
+		}
+		for name := range families {
+			req.Metadata = append(req.Metadata, prompb.MetricMetadata{Type: prompb.MetricMetadata_GAUGE, MetricFamilyName: name})
+			delete(families, name)
+		}
+		var err error
+		if buf, err = req.Marshal(); err != nil {
+			log.Printf("remote write: failed to marshal request: %v", err)
+			continue
+		}
+		rw.enqueue(snappy.Encode(nil, buf))
+	}
+}
+
+func (rw *remoteWrite) enqueue(data []byte) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	id := rw.nextID
+	rw.nextID++
+	if err := rw.diskQueue.PutBucket(0, id, data); err != nil {
+		log.Printf("remote write: failed to put request into disk queue: %v", err)
+		return
+	}
+	rw.queue = append(rw.queue, id)
+	// unsent size shrinks as soon as request is erased, while total size only when the whole file is erased
+	for _, unsent := rw.diskQueue.TotalFileSize(0); unsent > rw.maxDiskSize && len(rw.queue) > 1; _, unsent = rw.diskQueue.TotalFileSize(0) {
+		log.Printf("remote write: disk queue size %d violates limit %d, throwing out the oldest request", unsent, rw.maxDiskSize)
+		rw.eraseLocked(rw.queue[0])
+	}
+	rw.cond.Signal()
+}
+
+func (rw *remoteWrite) eraseLocked(id uint32) {
+	if err := rw.diskQueue.EraseBucket(0, id); err != nil {
+		log.Printf("remote write: %v", err)
+	}
+	if len(rw.queue) != 0 && rw.queue[0] == id { // might be already thrown out while sending
+		rw.queue = rw.queue[1:]
+	}
+}
+
+func (rw *remoteWrite) goSend() {
+	var scratch []byte
+	backoff := remoteWriteMinBackoff
+	for {
+		rw.mu.Lock()
+		for len(rw.queue) == 0 {
+			rw.cond.Wait()
+		}
+		id := rw.queue[0]
+		rw.mu.Unlock()
+
+		data, err := rw.diskQueue.GetBucket(0, id, &scratch)
+		if err != nil { // corrupted request is erased by GetBucket
+			log.Printf("remote write: %v", err)
+		} else if retry, err := rw.send(data); err != nil {
+			if retry {
+				log.Printf("remote write: %v, will retry in %v", err, backoff)
+				time.Sleep(backoff)
+				backoff = min(backoff*2, remoteWriteMaxBackoff)
+				continue
+			}
+			log.Printf("remote write: %v, throwing out request", err)
+		}
+		backoff = remoteWriteMinBackoff
+		rw.mu.Lock()
+		rw.eraseLocked(id)
+		rw.mu.Unlock()
+	}
+}
+
+// as required by remote write spec, 5xx and 429 are retried, other errors are not
+func (rw *remoteWrite) send(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, rw.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "statshouse/"+build.Commit())
+	resp, err := rw.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	var partialMessage [256]byte
+	partialMessageLen, _ := io.ReadFull(resp.Body, partialMessage[:])
+	_, _ = io.Copy(io.Discard, resp.Body) // keepalive
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	err = fmt.Errorf("could not post to %s (HTTP code %d): %s", rw.url, resp.StatusCode, partialMessage[:partialMessageLen])
+	return resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+func (rw *remoteWrite) appendSeries(res []prompb.TimeSeries, families map[string]struct{}, it *remoteWriteItem) []prompb.TimeSeries {
+	labels := rw.labels(it)
+	ts := int64(it.key.Timestamp) * 1000
+	appendSample := func(name string, v float64, extra ...prompb.Label) {
+		l := make([]prompb.Label, 0, 1+len(labels)+len(extra))
+		l = append(l, prompb.Label{Name: "__name__", Value: name})
+		l = append(l, labels...)
+		l = append(l, extra...)
+		sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name }) // required by spec
+		res = append(res, prompb.TimeSeries{Labels: l, Samples: []prompb.Sample{{Value: v, Timestamp: ts}}})
+		families[name] = struct{}{}
+	}
+	name := it.meta.Name
+	appendSample(name+"_count_delta", it.value.Counter)
+	if it.value.ValueSet {
+		appendSample(name+"_sum_delta", it.value.ValueSum)
+		appendSample(name+"_min", it.value.ValueMin)
+		appendSample(name+"_max", it.value.ValueMax)
+	}
+	for i, q := range it.quantiles {
+		appendSample(name+"_percentile", q, prompb.Label{Name: "percentile", Value: strconv.FormatFloat(remoteWriteQuantiles[i], 'f', -1, 64)})
+	}
+	if it.unique != 0 {
+		appendSample(name+"_unique", it.unique)
+	}
+	return res
+}
+
+func (rw *remoteWrite) labels(it *remoteWriteItem) []prompb.Label {
+	var labels []prompb.Label
+	for i, v := range it.key.Keys {
+		if v == format.TagValueIDUnspecified {
+			continue
+		}
+		var tag format.MetricMetaTag
+		if i < len(it.meta.Tags) {
+			tag = it.meta.Tags[i]
+		}
+		name := tag.Name
+		if name == "" {
+			name = format.TagIDLegacy(i)
+		}
+		labels = append(labels, prompb.Label{Name: name, Value: rw.tagValueString(tag, v)})
+	}
+	if it.skey != "" {
+		name := it.meta.StringTopName
+		if name == "" {
+			name = format.LegacyStringTopTagID
+		}
+		labels = append(labels, prompb.Label{Name: name, Value: it.skey})
+	}
+	return labels
+}
+
+func (rw *remoteWrite) tagValueString(tag format.MetricMetaTag, v int32) string {
+	if tag.Raw {
+		return strconv.Itoa(int(v))
+	}
+	if v == format.TagValueIDMappingFlood {
+		return format.CodeTagValue(v)
+	}
+	r := rw.tagValue.GetOrLoad(time.Now(), strconv.Itoa(int(v)), nil)
+	if r.Err != nil {
+		return format.CodeTagValue(v)
+	}
+	return pcache.ValueToString(r.Value)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package aggregator
+
+// File storage writes rows as newline-delimited JSON instead of inserting into clickhouse,
+// so aggregator can run in test environments and inserts can be replayed later.
+// Each row is appended to file named after its own second, for example 1700000000.ndjson,
+// so historic buckets inserted together with recent one go to their own files.
+// Rows have the same content as clickhouse rows, with sampling factor already applied.
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+const fileWriterExt = ".ndjson"
+
+type fileRowCentroid struct {
+	Mean   float64 `json:"mean"`
+	Weight float64 `json:"weight"`
+}
+
+type fileRowArgMinMax struct {
+	Host  int32   `json:"host"`
+	Value float64 `json:"value"`
+}
+
+type fileRow struct {
+	Metric      int32                 `json:"metric"`
+	Time        uint32                `json:"time"`
+	Keys        [format.MaxTags]int32 `json:"keys"`
+	SKey        string                `json:"skey,omitempty"`
+	Count       float64               `json:"count"`
+	Min         float64               `json:"min"`
+	Max         float64               `json:"max"`
+	Sum         float64               `json:"sum"`
+	SumSquare   float64               `json:"sumsquare"`
+	Percentiles []fileRowCentroid     `json:"percentiles,omitempty"`
+	UniqState   []byte                `json:"uniq_state,omitempty"` // clickhouse uniq aggregate state
+	MinHost     *fileRowArgMinMax     `json:"min_host,omitempty"`
+	MaxHost     *fileRowArgMinMax     `json:"max_host,omitempty"`
+}
+
+type fileWriter struct {
+	dir string
+}
+
+func newFileWriter(dir string) *fileWriter {
+	return &fileWriter{dir: dir}
+}
+
+// same logic as in multiValueMarshal
+func (w *fileWriter) appendRow(res []byte, k data_model.Key, skey string, value *data_model.MultiValue, sf float64, cache *metricIndexCache) []byte {
+	skipMaxHost, skipMinHost, skipSumSquare := cache.skips(k.Metric)
+	row := fileRow{Metric: k.Metric, Time: k.Timestamp, Keys: k.Keys, SKey: skey, Count: value.Value.Counter * sf}
+	if value.Value.ValueSet {
+		row.Min = value.Value.ValueMin
+		row.Max = value.Value.ValueMax
+		row.Sum = value.Value.ValueSum * sf
+		row.SumSquare = zeroIfTrue(value.Value.ValueSumSquare*sf, skipSumSquare)
+		if !skipMinHost {
+			row.MinHost = &fileRowArgMinMax{Host: value.Value.MinHostTag, Value: value.Value.ValueMin}
+		}
+		if !skipMaxHost {
+			row.MaxHost = &fileRowArgMinMax{Host: value.Value.MaxHostTag, Value: value.Value.ValueMax}
+		}
+	} else {
+		row.Max = row.Count
+		if !skipMaxHost {
+			row.MaxHost = &fileRowArgMinMax{Host: value.Value.MaxCounterHostTag, Value: row.Count}
+		}
+	}
+	if value.ValueTDigest != nil {
+		for _, c := range value.ValueTDigest.Centroids() {
+			row.Percentiles = append(row.Percentiles, fileRowCentroid{Mean: c.Mean, Weight: c.Weight * sf})
+		}
+	}
+	if value.HLL.ItemsCount() != 0 {
+		row.UniqState = value.HLL.MarshallAppend(nil)
+	}
+	data, err := json.Marshal(row)
+	if err != nil { // must be never, all floats are finite
+		return res
+	}
+	res = append(res, data...)
+	return append(res, '\n')
+}
+
+func (w *fileWriter) appendValueStatRow(res []byte, k data_model.Key, skey string, v data_model.ItemValue, cache *metricIndexCache) []byte {
+	return w.appendRow(res, k, skey, &data_model.MultiValue{Value: v}, 1, cache)
+}
+
+// rows of all buckets are mixed by sampler, so we split body by row time
+func (w *fileWriter) insert(_ []*aggregatorBucket, body []byte) (status int, exception int, elapsed float64, err error) {
+	start := time.Now()
+	files := map[uint32][]byte{}
+	var times []uint32 // keep order of files stable
+	for len(body) != 0 {
+		line := body
+		if i := bytes.IndexByte(body, '\n'); i >= 0 {
+			line, body = body[:i+1], body[i+1:]
+		} else {
+			body = nil
+		}
+		var row struct {
+			Time uint32 `json:"time"`
+		}
+		if err := json.Unmarshal(line, &row); err != nil { // must be never, we marshalled it
+			return 0, 0, time.Since(start).Seconds(), fmt.Errorf("could not parse row time: %w", err)
+		}
+		if _, ok := files[row.Time]; !ok {
+			times = append(times, row.Time)
+		}
+		files[row.Time] = append(files[row.Time], line...)
+	}
+	for _, t := range times {
+		if err := w.appendFile(t, files[t]); err != nil {
+			return 0, 0, time.Since(start).Seconds(), err
+		}
+	}
+	return 0, 0, time.Since(start).Seconds(), nil
+}
+
+func (w *fileWriter) appendFile(t uint32, data []byte) error {
+	fileName := filepath.Join(w.dir, strconv.FormatUint(uint64(t), 10)+fileWriterExt)
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return fmt.Errorf("could not write to %q: %w", fileName, err)
+	}
+	return nil
+}
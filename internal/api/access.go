@@ -9,6 +9,7 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/vkcom/statshouse/internal/data_model"
@@ -30,6 +31,15 @@ type accessInfo struct {
 	bitViewMetric        map[string]bool
 	bitEditMetric        map[string]bool
 	skipBadgesValidation bool
+	apiToken             string      // name of API token used instead of JWT
+	readOnly             bool        // set for read-only API tokens
+	roles                roleStorage // nil if roles are not checked
+}
+
+// namespaces and groups with roles granted to users
+type roleStorage interface {
+	GetNamespaceByName(name string) *format.NamespaceMeta
+	GetGroupByMetricName(name string) *format.MetricsGroup
 }
 
 func parseAccessToken(jwtHelper *vkuth.JWTHelper,
@@ -98,15 +108,60 @@ func parseAccessToken(jwtHelper *vkuth.JWTHelper,
 }
 
 func (ai *accessInfo) toMetadata() string {
+	return ai.toMetadataWithGrants(nil)
+}
+
+func (ai *accessInfo) toMetadataWithGrants(grants []roleGrant) string {
 	m := metadata{
 		UserEmail: ai.user,
 		UserName:  "",
 		UserRef:   "",
+		Grants:    grants,
 	}
 	res, _ := json.Marshal(&m)
 	return string(res)
 }
 
+// changes from old to new roles, ordered by user
+func roleGrants(old, new_ map[string]string) []roleGrant {
+	var res []roleGrant
+	for user, role := range new_ {
+		if old[user] != role {
+			res = append(res, roleGrant{User: user, Role: role})
+		}
+	}
+	for user := range old {
+		if _, ok := new_[user]; !ok {
+			res = append(res, roleGrant{User: user})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].User < res[j].User })
+	return res
+}
+
+// highest role of user in namespace and group of metric (or dashboard) with given name
+func (ai *accessInfo) roleLevel(name string) int {
+	if ai.roles == nil || ai.user == "" {
+		return 0
+	}
+	var res int
+	if _, namespace := format.SplitNamespace(name); namespace != "" {
+		if ns := ai.roles.GetNamespaceByName(namespace); ns != nil {
+			res = format.RoleLevel(ns.Roles[ai.user])
+		}
+	}
+	if g := ai.roles.GetGroupByMetricName(name); g != nil {
+		if v := format.RoleLevel(g.Roles[ai.user]); res < v {
+			res = v
+		}
+	}
+	return res
+}
+
+func (ai *accessInfo) hasRole(name string, role string) bool {
+	return ai.roleLevel(name) >= format.RoleLevel(role)
+}
+
 func (ai *accessInfo) protectedMetric(name string) bool {
 	for _, p := range ai.protectedPrefixes {
 		if strings.HasPrefix(name, p) {
@@ -126,9 +181,10 @@ func (ai *accessInfo) CanViewMetricName(name string) bool {
 	if data_model.RemoteConfigMetric(name) && !ai.bitAdmin {
 		return false // remote config can only be viewed by administrators
 	}
+	// protected metrics are viewed only with access bits set, like with default access
 	return ai.bitViewMetric[name] ||
 		hasPrefixAccess(ai.bitViewPrefix, name) ||
-		(ai.bitViewDefault && !ai.protectedMetric(name))
+		(!ai.protectedMetric(name) && (ai.bitViewDefault || ai.hasRole(name, format.RoleViewer)))
 }
 
 func (ai *accessInfo) CanViewMetric(metric format.MetricMetaValue) bool {
@@ -148,10 +204,76 @@ func (ai *accessInfo) canChangeMetricByName(create bool, old format.MetricMetaVa
 	}
 	return ai.bitEditMetric[oldName] && ai.bitEditMetric[newName] ||
 		(hasPrefixAccess(ai.bitEditPrefix, oldName) && hasPrefixAccess(ai.bitEditPrefix, newName)) ||
-		(ai.bitEditDefault && !ai.protectedMetric(oldName) && !ai.protectedMetric(newName))
+		(!ai.protectedMetric(oldName) && !ai.protectedMetric(newName) &&
+			(ai.bitEditDefault || (ai.hasRole(oldName, format.RoleEditor) && ai.hasRole(newName, format.RoleEditor))))
+}
+
+// dashboards of namespace with roles granted can only be edited by namespace editors,
+// other dashboards are editable by everyone
+func (ai *accessInfo) CanEditDashboard(oldName string, newName string) bool {
+	if ai.readOnly {
+		return false
+	}
+	if ai.insecureMode || ai.bitAdmin {
+		return true
+	}
+	return ai.canEditDashboardName(oldName) && ai.canEditDashboardName(newName)
+}
+
+func (ai *accessInfo) canEditDashboardName(name string) bool {
+	if ai.apiToken != "" { // tokens have no roles, they edit dashboards of their own namespaces only
+		return ai.bitEditDefault || hasPrefixAccess(ai.bitEditPrefix, name)
+	}
+	_, namespace := format.SplitNamespace(name)
+	if namespace == "" || ai.roles == nil {
+		return true
+	}
+	ns := ai.roles.GetNamespaceByName(namespace)
+	if ns == nil || len(ns.Roles) == 0 {
+		return true
+	}
+	return hasPrefixAccess(ai.bitEditPrefix, name) || format.RoleLevel(ns.Roles[ai.user]) >= format.RoleLevel(format.RoleEditor)
+}
+
+// alert rules and annotations of namespace can be changed by namespace editors,
+// those without namespace only by administrators
+func (ai *accessInfo) canEditNamespaceOf(name string) bool {
+	_, namespace := format.SplitNamespace(name)
+	return ai.canEditNamespace(namespace)
+}
+
+func (ai *accessInfo) canEditNamespace(namespace string) bool {
+	if ai.readOnly {
+		return false
+	}
+	if ai.isAdmin() {
+		return true
+	}
+	if namespace == "" {
+		return false
+	}
+	if ai.bitEditPrefix[namespace+format.NamespaceSeparator] {
+		return true
+	}
+	if ai.roles == nil || ai.user == "" {
+		return false
+	}
+	ns := ai.roles.GetNamespaceByName(namespace)
+	return ns != nil && format.RoleLevel(ns.Roles[ai.user]) >= format.RoleLevel(format.RoleEditor)
+}
+
+// namespace or group owners can change their roles, everything else only administrators can
+func (ai *accessInfo) canGrantRoles(roles map[string]string) bool {
+	if ai.isAdmin() {
+		return true
+	}
+	return ai.user != "" && roles[ai.user] == format.RoleOwner
 }
 
 func (ai *accessInfo) CanEditMetric(create bool, old format.MetricMetaValue, new_ format.MetricMetaValue) bool {
+	if ai.readOnly {
+		return false
+	}
 	if ai.insecureMode {
 		return true
 	}
@@ -171,6 +293,12 @@ func (ai *accessInfo) CanEditMetric(create bool, old format.MetricMetaValue, new
 		if skips(old) != skips(new_) {
 			return false
 		}
+		if !sameSamplingPolicy(old, new_) { // takes budget from other metrics
+			return false
+		}
+		if old.State != new_.State && new_.State == format.MetricStateDeleted { // data will be deleted
+			return false
+		}
 
 		return true
 	}
@@ -196,6 +324,20 @@ func skips(m format.MetricMetaValue) [3]bool {
 	return [3]bool{m.SkipMaxHost, m.SkipMinHost, m.SkipSumSquare}
 }
 
+func sameSamplingPolicy(a format.MetricMetaValue, b format.MetricMetaValue) bool {
+	if a.SampleKeepTagID != b.SampleKeepTagID || len(a.SampleKeepValues) != len(b.SampleKeepValues) {
+		return false
+	}
+	for i := range a.SampleKeepValues {
+		if a.SampleKeepValues[i] != b.SampleKeepValues[i] {
+			return false
+		}
+	}
+	return a.SampleStratifyTagID == b.SampleStratifyTagID &&
+		a.SampleStratifyMin == b.SampleStratifyMin &&
+		a.SampleMinBudget == b.SampleMinBudget
+}
+
 func hasPrefixAccess(m map[string]bool, metric string) bool {
 	for prefix := range m {
 		if strings.HasPrefix(metric, prefix) {
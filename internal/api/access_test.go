@@ -128,6 +128,123 @@ func TestAccessInfo(t *testing.T) {
 			require.True(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "abc"}, format.MetricMetaValue{Name: "foo_bar"}))
 			require.True(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "foo_bar"}, format.MetricMetaValue{Name: "abc"}))
 		})
+		t.Run("lifecycle", func(t *testing.T) {
+			ai := accessInfo{
+				bitEditMetric: map[string]bool{"foo_bar": true},
+			}
+			active := format.MetricMetaValue{Name: "foo_bar"}
+			disabled := format.MetricMetaValue{Name: "foo_bar", State: format.MetricStateDisabled}
+			deleted := format.MetricMetaValue{Name: "foo_bar", State: format.MetricStateDeleted}
+			require.True(t, ai.CanEditMetric(false, active, disabled))
+			require.False(t, ai.CanEditMetric(false, disabled, deleted))
+			require.True(t, ai.CanEditMetric(false, deleted, disabled))
+			ai.bitAdmin = true
+			require.True(t, ai.CanEditMetric(false, disabled, deleted))
+
+			require.NoError(t, checkMetricStateChange(active, disabled))
+			require.NoError(t, checkMetricStateChange(disabled, deleted))
+			require.NoError(t, checkMetricStateChange(deleted, active))
+			require.Error(t, checkMetricStateChange(active, deleted))
+			require.Error(t, checkMetricStateChange(active, format.MetricMetaValue{Name: "foo_bar", State: "removed"}))
+			deleted.PurgeTime = 1
+			require.Error(t, checkMetricStateChange(deleted, active))
+		})
+	})
+}
+
+type testRoleStorage struct {
+	namespaces map[string]*format.NamespaceMeta
+	groups     []*format.MetricsGroup
+}
+
+func (s *testRoleStorage) GetNamespaceByName(name string) *format.NamespaceMeta {
+	return s.namespaces[name]
+}
+
+func (s *testRoleStorage) GetGroupByMetricName(name string) *format.MetricsGroup {
+	for _, g := range s.groups {
+		if g.MetricIn(&format.MetricMetaValue{Name: name}) {
+			return g
+		}
+	}
+	return nil
+}
+
+func TestAccessRoles(t *testing.T) {
+	roles := &testRoleStorage{
+		namespaces: map[string]*format.NamespaceMeta{
+			"team": {Name: "team", Roles: map[string]string{"viewer@": format.RoleViewer, "editor@": format.RoleEditor, "owner@": format.RoleOwner}},
+			"open": {Name: "open"},
+		},
+		groups: []*format.MetricsGroup{
+			{Name: "api_", Roles: map[string]string{"viewer@": format.RoleEditor}},
+		},
+	}
+	user := func(name string) accessInfo { return accessInfo{user: name, roles: roles} }
+	t.Run("view", func(t *testing.T) {
+		ai := user("viewer@")
+		require.True(t, canViewMetricNamespaced(&ai, "foo", "team"))
+		require.False(t, canViewMetricNamespaced(&ai, "foo", "open"))
+		require.True(t, canViewMetric(&ai, "api_requests"))
+		require.False(t, canViewMetric(&ai, "foo"))
+		ai = user("stranger@")
+		require.False(t, canViewMetricNamespaced(&ai, "foo", "team"))
+		// role does not grant access to protected metrics, access bits do
+		ai = user("viewer@")
+		ai.protectedPrefixes = []string{"team:secret_"}
+		require.True(t, canViewMetricNamespaced(&ai, "foo", "team"))
+		require.False(t, canViewMetricNamespaced(&ai, "secret_foo", "team"))
+		ai.bitViewPrefix = map[string]bool{"team:secret_": true}
+		require.True(t, canViewMetricNamespaced(&ai, "secret_foo", "team"))
+	})
+	t.Run("edit", func(t *testing.T) {
+		ai := user("viewer@")
+		require.False(t, canBasicEdit(&ai, "team:foo", false))
+		require.True(t, canBasicEdit(&ai, "api_requests", true)) // group role is higher
+		ai = user("editor@")
+		require.True(t, canBasicEdit(&ai, "team:foo", true))
+		require.False(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "team:foo"}, format.MetricMetaValue{Name: "open:foo"}))
+		require.False(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "team:foo"}, format.MetricMetaValue{Name: "team:foo", Weight: 5}))
+		require.False(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "team:foo"}, format.MetricMetaValue{Name: "team:foo", SampleMinBudget: 1 << 20}))
+		require.False(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "team:foo"}, format.MetricMetaValue{Name: "team:foo", SampleKeepTagID: "1", SampleKeepValues: []string{"error"}}))
+		require.True(t, ai.CanEditMetric(false, format.MetricMetaValue{Name: "team:foo", SampleStratifyTagID: "1", SampleStratifyMin: 2}, format.MetricMetaValue{Name: "team:foo", SampleStratifyTagID: "1", SampleStratifyMin: 2, Description: "x"}))
+		ai = user("owner@")
+		require.True(t, canBasicEdit(&ai, "team:foo", false))
+		ai.protectedPrefixes = []string{"team:secret_"}
+		require.False(t, canBasicEdit(&ai, "team:secret_foo", false))
+	})
+	t.Run("dashboard", func(t *testing.T) {
+		ai := user("viewer@")
+		require.False(t, ai.CanEditDashboard("team:overview", "team:overview"))
+		require.True(t, ai.CanEditDashboard("open:overview", "open:overview"))
+		require.True(t, ai.CanEditDashboard("overview", "overview"))
+		ai = user("editor@")
+		require.True(t, ai.CanEditDashboard("team:overview", "team:overview"))
+		ai = user("stranger@")
+		require.False(t, ai.CanEditDashboard("overview", "team:overview"))
+		ai.bitAdmin = true
+		require.True(t, ai.CanEditDashboard("overview", "team:overview"))
+	})
+	t.Run("namespace", func(t *testing.T) {
+		ai := user("editor@")
+		require.True(t, ai.canEditNamespaceOf("team:errors"))
+		require.False(t, ai.canEditNamespaceOf("open:errors"))
+		require.False(t, ai.canEditNamespaceOf("errors")) // no namespace, admin only
+		ai = user("viewer@")
+		require.False(t, ai.canEditNamespaceOf("team:errors"))
+		ai.bitEditPrefix = map[string]bool{"team:": true}
+		require.True(t, ai.canEditNamespaceOf("team:errors"))
+		ai = user("stranger@")
+		ai.bitAdmin = true
+		require.True(t, ai.canEditNamespaceOf("errors"))
+	})
+	t.Run("grant", func(t *testing.T) {
+		ai := user("owner@")
+		require.True(t, ai.canGrantRoles(roles.namespaces["team"].Roles))
+		ai = user("editor@")
+		require.False(t, ai.canGrantRoles(roles.namespaces["team"].Roles))
+		require.Equal(t, []roleGrant{{User: "a@", Role: format.RoleOwner}, {User: "b@"}, {User: "c@", Role: format.RoleViewer}},
+			roleGrants(map[string]string{"a@": format.RoleEditor, "b@": format.RoleViewer, "d@": format.RoleOwner}, map[string]string{"a@": format.RoleOwner, "c@": format.RoleViewer, "d@": format.RoleOwner}))
 	})
 }
 
@@ -0,0 +1,391 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Every series returned by rule expression is an alert, identified by its labels.
+// Alert is pending while expression returns it for less than rule 'for' seconds, then it is firing.
+// Firing and resolved alerts are posted to rule webhooks in Alertmanager API v2 format,
+// firing alerts are reposted every alertResendDelay, so receivers do not expire them.
+// Alert state is kept in memory only, after restart alerts start from pending state again.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/vkcom/statshouse-go"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+//go:generate easyjson -no_std_marshalers alerting.go
+
+const (
+	alertResendDelay    = time.Minute
+	alertWebhookTimeout = 10 * time.Second
+	alertNameLabel      = "alertname"
+)
+
+type (
+	//easyjson:json
+	AlertRuleInfo struct {
+		AlertRule format.AlertRule `json:"alert_rule"`
+		Delete    bool             `json:"delete_mark"`
+	}
+
+	//easyjson:json
+	GetAlertRuleListResp struct {
+		AlertRules []alertRuleShortInfo `json:"alert_rules"`
+	}
+
+	alertRuleShortInfo struct {
+		Id      int32  `json:"id"`
+		Name    string `json:"name"`
+		Disable bool   `json:"disable"`
+	}
+)
+
+type alertRuleEvaluator struct {
+	m      *ruleManager
+	alerts map[string]*alert // by labels
+}
+
+type alert struct {
+	labels      map[string]string
+	annotations map[string]string
+	value       float64
+	state       int32 // format.TagValueIDAlertState*
+	activeAt    time.Time
+	firedAt     time.Time
+	resolvedAt  time.Time
+	sentAt      time.Time
+}
+
+// Alertmanager API v2 postableAlert
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+func (e *alertRuleEvaluator) interval(rule *format.AlertRule) time.Duration {
+	return time.Duration(rule.EffectiveInterval()) * time.Second
+}
+
+func (e *alertRuleEvaluator) eval(rule *format.AlertRule, now time.Time) {
+	samples, err := e.m.query(context.Background(), rule.Expr, now)
+	if err != nil {
+		log.Printf("[error] failed to evaluate alert rule %q: %v", rule.Name, err)
+		return // keep alerts as is, so that query errors do not resolve them
+	}
+	e.m.notify(rule, e.update(rule, samples, now))
+}
+
+// resolves all alerts
+func (e *alertRuleEvaluator) close(rule *format.AlertRule) {
+	e.m.notify(rule, e.update(rule, nil, time.Now()))
+}
+
+// advances alert states, returns alerts which must be sent to webhooks
+func (e *alertRuleEvaluator) update(rule *format.AlertRule, samples []ruleSample, now time.Time) []*alert {
+	var res []*alert
+	seen := make(map[string]bool, len(samples))
+	for _, s := range samples {
+		lbs := alertLabels(rule, s.labels)
+		key := alertLabelsKey(lbs)
+		if seen[key] {
+			continue // series differing only by dropped labels
+		}
+		seen[key] = true
+		a := e.alerts[key]
+		if a == nil {
+			a = &alert{labels: lbs, state: format.TagValueIDAlertStateInactive, activeAt: now}
+			e.alerts[key] = a
+		}
+		a.value = s.value
+		a.annotations = expandAlertAnnotations(rule.Annotations, lbs, s.value)
+		state := int32(format.TagValueIDAlertStatePending)
+		if now.Sub(a.activeAt) >= time.Duration(rule.For)*time.Second {
+			state = format.TagValueIDAlertStateFiring
+		}
+		if a.state != state {
+			reportAlertState(rule, a.state, state)
+			a.state = state
+			if state == format.TagValueIDAlertStateFiring {
+				a.firedAt = now
+			}
+		}
+		if a.state == format.TagValueIDAlertStateFiring && now.Sub(a.sentAt) >= alertResendDelay {
+			a.sentAt = now
+			res = append(res, a)
+		}
+	}
+	for key, a := range e.alerts {
+		if seen[key] {
+			continue
+		}
+		reportAlertState(rule, a.state, format.TagValueIDAlertStateInactive)
+		if a.state == format.TagValueIDAlertStateFiring {
+			a.resolvedAt = now
+			res = append(res, a)
+		}
+		a.state = format.TagValueIDAlertStateInactive
+		delete(e.alerts, key)
+	}
+	return res
+}
+
+func (m *ruleManager) notify(rule *format.AlertRule, alerts []*alert) {
+	if len(alerts) == 0 || len(rule.Webhooks) == 0 {
+		return
+	}
+	validFor := time.Duration(rule.EffectiveInterval()) * time.Second
+	if validFor < alertResendDelay {
+		validFor = alertResendDelay
+	}
+	payload := make([]alertmanagerAlert, 0, len(alerts))
+	for _, a := range alerts {
+		endsAt := a.resolvedAt
+		if a.state == format.TagValueIDAlertStateFiring {
+			endsAt = a.sentAt.Add(4 * validFor) // receiver resolves alert itself if we stop sending
+		}
+		payload = append(payload, alertmanagerAlert{
+			Labels:      a.labels,
+			Annotations: a.annotations,
+			StartsAt:    a.firedAt,
+			EndsAt:      endsAt,
+		})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[error] failed to serialize alerts of rule %q: %v", rule.Name, err)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, url := range rule.Webhooks {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			if err := m.post(url, body); err != nil {
+				log.Printf("[error] failed to send alerts of rule %q to %q: %v", rule.Name, url, err)
+			}
+		}(url)
+	}
+	wg.Wait()
+}
+
+func (m *ruleManager) post(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), alertWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func alertLabels(rule *format.AlertRule, series map[string]string) map[string]string {
+	res := make(map[string]string, len(series)+len(rule.Labels)+1)
+	for k, v := range series {
+		res[k] = v
+	}
+	for k, v := range rule.Labels {
+		res[k] = v
+	}
+	res[alertNameLabel] = rule.Name
+	return res
+}
+
+func alertLabelsKey(lbs map[string]string) string {
+	keys := make([]string, 0, len(lbs))
+	for k := range lbs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(strconv.Quote(k))
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(lbs[k]))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+// same template variables as in Prometheus, {{ $labels.name }} and {{ $value }}
+func expandAlertAnnotations(annotations map[string]string, lbs map[string]string, value float64) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	res := make(map[string]string, len(annotations))
+	data := struct {
+		Labels map[string]string
+		Value  float64
+	}{lbs, value}
+	for k, text := range annotations {
+		res[k] = text
+		t, err := template.New(k).Option("missingkey=zero").Parse("{{$labels := .Labels}}{{$value := .Value}}" + text)
+		if err != nil {
+			continue // send as is, user will see the problem
+		}
+		var sb strings.Builder
+		if err = t.Execute(&sb, data); err == nil {
+			res[k] = sb.String()
+		}
+	}
+	return res
+}
+
+func reportAlertState(rule *format.AlertRule, from int32, to int32) {
+	statshouse.Metric(
+		format.BuiltinMetricNameAlertRuleState,
+		statshouse.Tags{
+			1: strconv.Itoa(int(rule.ID)),
+			2: strconv.Itoa(int(from)),
+			3: strconv.Itoa(int(to)),
+		},
+	).Count(1)
+}
+
+// webhooks receive labels of series user might be not allowed to view, so only configured hosts are allowed
+func (h *Handler) checkAlertWebhooks(webhooks []string) error {
+	for _, w := range webhooks {
+		u, err := url.Parse(w)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return httpErr(http.StatusBadRequest, fmt.Errorf("alert rule webhook %q must be http or https URL", w))
+		}
+		allowed := false
+		for _, host := range h.alertWebhookHosts {
+			if u.Host == host || u.Hostname() == host {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return httpErr(http.StatusForbidden, fmt.Errorf("alert rule webhook host %q is not in --alert-webhook-hosts", u.Host))
+		}
+	}
+	return nil
+}
+
+func (h *Handler) handleGetAlertRule(ctx context.Context, ai accessInfo, id int32, version int64) (*AlertRuleInfo, time.Duration, error) {
+	var rule format.AlertRule
+	if version == 0 {
+		r := h.metricsStorage.GetAlertRule(id)
+		if r == nil {
+			return nil, 0, httpErr(http.StatusNotFound, fmt.Errorf("alert rule %d not found", id))
+		}
+		rule = *r
+	} else {
+		var err error
+		if rule, err = h.metadataLoader.GetAlertRule(ctx, int64(id), version); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := h.checkRuleExpr(ai, rule.Expr); err != nil {
+		return nil, 0, err
+	}
+	return &AlertRuleInfo{AlertRule: rule}, defaultCacheTTL, nil
+}
+
+func (h *Handler) handleGetAlertRuleList(ai accessInfo, showInvisible bool) (*GetAlertRuleListResp, time.Duration, error) {
+	resp := &GetAlertRuleListResp{}
+	rules := h.metricsStorage.GetAlertRuleList()
+	ids := make(map[int32]bool, len(rules))
+	for _, rule := range rules {
+		ids[rule.ID] = true
+		if rule.Disable && !showInvisible {
+			continue
+		}
+		matchers, err := h.alertRuleMatchers.get(rule.ID, rule.Version, rule.Expr)
+		if err != nil || h.checkRuleMatchers(ai, matchers) != nil {
+			continue
+		}
+		resp.AlertRules = append(resp.AlertRules, alertRuleShortInfo{
+			Id:      rule.ID,
+			Name:    rule.Name,
+			Disable: rule.Disable,
+		})
+	}
+	h.alertRuleMatchers.retain(ids)
+	return resp, defaultCacheTTL, nil
+}
+
+func (h *Handler) handlePostAlertRule(ctx context.Context, ai accessInfo, rule format.AlertRule, create, delete bool) (*AlertRuleInfo, error) {
+	if !create {
+		old := h.metricsStorage.GetAlertRule(rule.ID)
+		if old == nil {
+			return &AlertRuleInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("alert rule %d not found", rule.ID))
+		}
+		if !ai.canEditNamespaceOf(old.Name) {
+			return &AlertRuleInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit alert rule %q", old.Name))
+		}
+	}
+	if !ai.canEditNamespaceOf(rule.Name) {
+		return &AlertRuleInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit alert rule %q, namespace editor rights required", rule.Name))
+	}
+	if err := h.checkRuleExpr(ai, rule.Expr); err != nil {
+		return &AlertRuleInfo{}, err
+	}
+	if err := h.checkAlertWebhooks(rule.Webhooks); err != nil {
+		return &AlertRuleInfo{}, err
+	}
+	rule, err := h.metadataLoader.SaveAlertRule(ctx, rule, create, delete, ai.toMetadata())
+	if err != nil {
+		s := "edit"
+		if create {
+			s = "create"
+		}
+		if metajournal.IsUserRequestError(err) {
+			return &AlertRuleInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("can't %s alert rule: %w", s, err))
+		}
+		return &AlertRuleInfo{}, fmt.Errorf("can't %s alert rule: %w", s, err)
+	}
+	return &AlertRuleInfo{AlertRule: rule}, nil
+}
+
+func (h *Handler) HandleGetAlertRule(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntity(w, r, h, EndpointAlertRule, h.handleGetAlertRule)
+}
+
+func (h *Handler) HandleGetAlertRuleList(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntityList(w, r, h, EndpointAlertRuleList, h.handleGetAlertRuleList)
+}
+
+func (h *Handler) HandlePutPostAlertRule(w http.ResponseWriter, r *http.Request) {
+	var ruleInfo AlertRuleInfo
+	handlePostEntity(h, w, r, EndpointAlertRule, &ruleInfo, func(ctx context.Context, ai accessInfo, entity *AlertRuleInfo, create bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handlePostAlertRule(ctx, ai, entity.AlertRule, create, entity.Delete)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.AlertRule.Version, nil
+	})
+}
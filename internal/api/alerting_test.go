@@ -0,0 +1,99 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/promql"
+)
+
+func TestAlertRuleStates(t *testing.T) {
+	rule := &format.AlertRule{
+		ID:          1,
+		Name:        "HighErrorRate",
+		Expr:        "errors > 10",
+		For:         60,
+		Labels:      map[string]string{"severity": "page"},
+		Annotations: map[string]string{"summary": "{{ $labels.host }} has {{ $value }} errors"},
+	}
+	e := &alertRuleEvaluator{alerts: map[string]*alert{}}
+	samples := []ruleSample{{labels: map[string]string{"host": "a"}, value: 11}}
+	start := time.Unix(1700000000, 0)
+
+	require.Empty(t, e.update(rule, samples, start))
+	require.Len(t, e.alerts, 1)
+	for _, a := range e.alerts {
+		require.Equal(t, int32(format.TagValueIDAlertStatePending), a.state)
+		require.Equal(t, map[string]string{"host": "a", "severity": "page", alertNameLabel: "HighErrorRate"}, a.labels)
+		require.Equal(t, map[string]string{"summary": "a has 11 errors"}, a.annotations)
+	}
+
+	res := e.update(rule, samples, start.Add(time.Minute))
+	require.Len(t, res, 1)
+	require.Equal(t, int32(format.TagValueIDAlertStateFiring), res[0].state)
+	require.Equal(t, start.Add(time.Minute), res[0].firedAt)
+
+	require.Empty(t, e.update(rule, samples, start.Add(90*time.Second))) // not resent before alertResendDelay
+	require.Len(t, e.update(rule, samples, start.Add(2*time.Minute)), 1)
+
+	res = e.update(rule, nil, start.Add(3*time.Minute))
+	require.Len(t, res, 1)
+	require.Equal(t, int32(format.TagValueIDAlertStateInactive), res[0].state)
+	require.Equal(t, start.Add(3*time.Minute), res[0].resolvedAt)
+	require.Empty(t, e.alerts)
+
+	// pending alert is dropped silently
+	require.Empty(t, e.update(rule, samples, start.Add(4*time.Minute)))
+	require.Empty(t, e.update(rule, nil, start.Add(5*time.Minute)))
+	require.Empty(t, e.alerts)
+}
+
+func TestRuleSampleValue(t *testing.T) {
+	v, ok := ruleSampleValue([]float64{1, 2, promql.NilValue})
+	require.True(t, ok)
+	require.Equal(t, 2.0, v)
+	_, ok = ruleSampleValue([]float64{1, math.NaN()})
+	require.False(t, ok)
+	_, ok = ruleSampleValue([]float64{promql.NilValue})
+	require.False(t, ok)
+}
+
+func TestAlertWebhooks(t *testing.T) {
+	h := &Handler{HandlerOptions: HandlerOptions{alertWebhookHosts: []string{"alertmanager", "hooks.example.com:9093"}}}
+	require.NoError(t, h.checkAlertWebhooks([]string{"http://alertmanager:9093/api/v2/alerts", "https://hooks.example.com:9093/x"}))
+	require.Error(t, h.checkAlertWebhooks([]string{"https://hooks.example.com/x"}))
+	require.Error(t, h.checkAlertWebhooks([]string{"http://169.254.169.254/latest"}))
+	require.Error(t, h.checkAlertWebhooks([]string{"file:///etc/passwd"}))
+	require.Error(t, (&Handler{}).checkAlertWebhooks([]string{"http://alertmanager/"}))
+}
+
+func TestAlertRuleMatcherCache(t *testing.T) {
+	c := newRuleMatcherCache()
+	m, err := c.get(1, 10, `rate(requests[1m]) > 5`)
+	require.NoError(t, err)
+	require.Len(t, m, 1)
+	require.Equal(t, "requests", m[0].Value)
+	// same version is not parsed again, even if expression passed differs
+	m, err = c.get(1, 10, `errors > 0`)
+	require.NoError(t, err)
+	require.Equal(t, "requests", m[0].Value)
+	m, err = c.get(1, 11, `errors > 0`)
+	require.NoError(t, err)
+	require.Equal(t, "errors", m[0].Value)
+	_, err = c.get(2, 12, `rate(`)
+	require.Error(t, err)
+
+	c.retain(map[int32]bool{2: true})
+	require.Len(t, c.rules, 1)
+	require.Contains(t, c.rules, int32(2))
+}
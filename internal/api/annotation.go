@@ -0,0 +1,169 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Annotation marks time range (deploy, incident, maintenance) on graphs. Annotation without
+// namespace, metric and tag filters is shown on every graph, filtered one only on graphs of
+// matching metrics. Annotations are kept in memory by metric storage, like dashboards.
+// Annotations of namespace are changed by namespace editors, global ones only by administrators.
+// Annotations ended more than format.AnnotationMaxAgeOfEnd ago are hidden, like deleted ones.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+//go:generate easyjson -no_std_marshalers annotation.go
+
+const maxSeriesAnnotations = 1000
+
+type (
+	//easyjson:json
+	AnnotationInfo struct {
+		Annotation format.Annotation `json:"annotation"`
+		Delete     bool              `json:"delete_mark"`
+	}
+
+	//easyjson:json
+	GetAnnotationListResp struct {
+		Annotations []format.Annotation `json:"annotations"`
+	}
+)
+
+func (ai *accessInfo) canViewAnnotation(a *format.Annotation) bool {
+	return a.Metric == "" || ai.CanViewMetricName(a.Metric)
+}
+
+// annotations overlapping [from, to) shown on metric graph, ordered by start time
+func (h *Handler) getAnnotations(ai accessInfo, metric *format.MetricMetaValue, from, to int64, filterIn map[string][]string) []format.Annotation {
+	var res []format.Annotation
+	for _, a := range h.metricsStorage.GetAnnotationList() {
+		if a.Matches(metric, from, to, filterIn) && ai.canViewAnnotation(a) {
+			res = append(res, *a)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].From != res[j].From {
+			return res[i].From < res[j].From
+		}
+		return res[i].ID < res[j].ID
+	})
+	if len(res) > maxSeriesAnnotations {
+		res = res[len(res)-maxSeriesAnnotations:] // most recent
+	}
+	return res
+}
+
+func (h *Handler) handleGetAnnotation(ctx context.Context, ai accessInfo, id int32, version int64) (*AnnotationInfo, time.Duration, error) {
+	var a format.Annotation
+	if version == 0 {
+		p := h.metricsStorage.GetAnnotation(id)
+		if p == nil {
+			return nil, 0, httpErr(http.StatusNotFound, fmt.Errorf("annotation %d not found", id))
+		}
+		a = *p
+	} else {
+		var err error
+		if a, err = h.metadataLoader.GetAnnotation(ctx, int64(id), version); err != nil {
+			return nil, 0, err
+		}
+	}
+	if !ai.canViewAnnotation(&a) {
+		return nil, 0, httpErr(http.StatusForbidden, fmt.Errorf("can't view metric %q", a.Metric))
+	}
+	return &AnnotationInfo{Annotation: a}, defaultCacheTTL, nil
+}
+
+func (h *Handler) handleGetAnnotationList(ai accessInfo, metricName string, from, to int64) (*GetAnnotationListResp, time.Duration, error) {
+	resp := &GetAnnotationListResp{Annotations: []format.Annotation{}}
+	for _, a := range h.metricsStorage.GetAnnotationList() {
+		if a.To < from || a.From >= to || !ai.canViewAnnotation(a) {
+			continue
+		}
+		if metricName != "" && a.Metric != metricName {
+			continue
+		}
+		resp.Annotations = append(resp.Annotations, *a)
+	}
+	sort.Slice(resp.Annotations, func(i, j int) bool {
+		return resp.Annotations[i].From < resp.Annotations[j].From
+	})
+	return resp, defaultCacheTTL, nil
+}
+
+func (h *Handler) handlePostAnnotation(ctx context.Context, ai accessInfo, a format.Annotation, create, delete bool) (*AnnotationInfo, error) {
+	if !create {
+		old := h.metricsStorage.GetAnnotation(a.ID)
+		if old == nil {
+			return &AnnotationInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("annotation %d not found", a.ID))
+		}
+		if !ai.canViewAnnotation(old) || !ai.canEditNamespace(old.EffectiveNamespace()) {
+			return &AnnotationInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit annotation %d", a.ID))
+		}
+	}
+	if a.Metric != "" && h.metricsStorage.GetMetaMetricByName(a.Metric) == nil {
+		return &AnnotationInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("annotation metric %q not found", a.Metric))
+	}
+	if !ai.canViewAnnotation(&a) {
+		return &AnnotationInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't view metric %q", a.Metric))
+	}
+	if ns := a.EffectiveNamespace(); !ai.canEditNamespace(ns) {
+		if ns == "" {
+			return &AnnotationInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("only administrators can edit annotations without namespace"))
+		}
+		return &AnnotationInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit annotations of namespace %q", ns))
+	}
+	a, err := h.metadataLoader.SaveAnnotation(ctx, a, create, delete, ai.toMetadata())
+	if err != nil {
+		s := "edit"
+		if create {
+			s = "create"
+		}
+		if metajournal.IsUserRequestError(err) {
+			return &AnnotationInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("can't %s annotation: %w", s, err))
+		}
+		return &AnnotationInfo{}, fmt.Errorf("can't %s annotation: %w", s, err)
+	}
+	return &AnnotationInfo{Annotation: a}, nil
+}
+
+func (h *Handler) HandleGetAnnotation(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntity(w, r, h, EndpointAnnotation, h.handleGetAnnotation)
+}
+
+func (h *Handler) HandleGetAnnotationList(w http.ResponseWriter, r *http.Request) {
+	sl := newEndpointStatHTTP(EndpointAnnotationList, r.Method, 0, "", r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	from, to, err := parseFromTo(r.FormValue(ParamFromTime), r.FormValue(ParamToTime))
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	resp, cache, err := h.handleGetAnnotationList(ai, r.FormValue(ParamMetric), from.Unix(), to.Unix())
+	respondJSON(w, resp, cache, 0, err, h.verbose, ai.user, sl)
+}
+
+func (h *Handler) HandlePutPostAnnotation(w http.ResponseWriter, r *http.Request) {
+	var annotationInfo AnnotationInfo
+	handlePostEntity(h, w, r, EndpointAnnotation, &annotationInfo, func(ctx context.Context, ai accessInfo, entity *AnnotationInfo, create bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handlePostAnnotation(ctx, ai, entity.Annotation, create, entity.Delete)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.Annotation.Version, nil
+	})
+}
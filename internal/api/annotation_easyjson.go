@@ -0,0 +1,307 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package api
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	format "github.com/vkcom/statshouse/internal/format"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8b883f07DecodeGithubComVkcomStatshouseInternalApi(in *jlexer.Lexer, out *GetAnnotationListResp) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "annotations":
+			if in.IsNull() {
+				in.Skip()
+				out.Annotations = nil
+			} else {
+				in.Delim('[')
+				if out.Annotations == nil {
+					if !in.IsDelim(']') {
+						out.Annotations = make([]format.Annotation, 0, 0)
+					} else {
+						out.Annotations = []format.Annotation{}
+					}
+				} else {
+					out.Annotations = (out.Annotations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 format.Annotation
+					easyjson8b883f07DecodeGithubComVkcomStatshouseInternalFormat(in, &v1)
+					out.Annotations = append(out.Annotations, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b883f07EncodeGithubComVkcomStatshouseInternalApi(out *jwriter.Writer, in GetAnnotationListResp) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"annotations\":"
+		out.RawString(prefix[1:])
+		if in.Annotations == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Annotations {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8b883f07EncodeGithubComVkcomStatshouseInternalFormat(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v GetAnnotationListResp) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8b883f07EncodeGithubComVkcomStatshouseInternalApi(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *GetAnnotationListResp) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8b883f07DecodeGithubComVkcomStatshouseInternalApi(l, v)
+}
+func easyjson8b883f07DecodeGithubComVkcomStatshouseInternalFormat(in *jlexer.Lexer, out *format.Annotation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "annotation_id":
+			out.ID = int32(in.Int32())
+		case "name":
+			out.Name = string(in.String())
+		case "version":
+			out.Version = int64(in.Int64())
+		case "update_time":
+			out.UpdateTime = uint32(in.Uint32())
+		case "delete_time":
+			out.DeleteTime = uint32(in.Uint32())
+		case "from":
+			out.From = int64(in.Int64())
+		case "to":
+			out.To = int64(in.Int64())
+		case "text":
+			out.Text = string(in.String())
+		case "namespace":
+			out.Namespace = string(in.String())
+		case "metric":
+			out.Metric = string(in.String())
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Tags = make(map[string]string)
+				} else {
+					out.Tags = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v4 string
+					v4 = string(in.String())
+					(out.Tags)[key] = v4
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b883f07EncodeGithubComVkcomStatshouseInternalFormat(out *jwriter.Writer, in format.Annotation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"annotation_id\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	if in.Version != 0 {
+		const prefix string = ",\"version\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Version))
+	}
+	{
+		const prefix string = ",\"update_time\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.UpdateTime))
+	}
+	{
+		const prefix string = ",\"delete_time\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.DeleteTime))
+	}
+	{
+		const prefix string = ",\"from\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.From))
+	}
+	{
+		const prefix string = ",\"to\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.To))
+	}
+	if in.Text != "" {
+		const prefix string = ",\"text\":"
+		out.RawString(prefix)
+		out.String(string(in.Text))
+	}
+	if in.Namespace != "" {
+		const prefix string = ",\"namespace\":"
+		out.RawString(prefix)
+		out.String(string(in.Namespace))
+	}
+	if in.Metric != "" {
+		const prefix string = ",\"metric\":"
+		out.RawString(prefix)
+		out.String(string(in.Metric))
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v5First := true
+			for v5Name, v5Value := range in.Tags {
+				if v5First {
+					v5First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v5Name))
+				out.RawByte(':')
+				out.String(string(v5Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson8b883f07DecodeGithubComVkcomStatshouseInternalApi1(in *jlexer.Lexer, out *AnnotationInfo) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "annotation":
+			easyjson8b883f07DecodeGithubComVkcomStatshouseInternalFormat(in, &out.Annotation)
+		case "delete_mark":
+			out.Delete = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8b883f07EncodeGithubComVkcomStatshouseInternalApi1(out *jwriter.Writer, in AnnotationInfo) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"annotation\":"
+		out.RawString(prefix[1:])
+		easyjson8b883f07EncodeGithubComVkcomStatshouseInternalFormat(out, in.Annotation)
+	}
+	{
+		const prefix string = ",\"delete_mark\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Delete))
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v AnnotationInfo) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8b883f07EncodeGithubComVkcomStatshouseInternalApi1(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *AnnotationInfo) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8b883f07DecodeGithubComVkcomStatshouseInternalApi1(l, v)
+}
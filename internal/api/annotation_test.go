@@ -0,0 +1,71 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestHandlePostAnnotation(t *testing.T) {
+	h := newTestMetadataHandler(t)
+	ctx := context.Background()
+	admin := accessInfo{user: "admin@", bitAdmin: true}
+	save := func(ai accessInfo, a format.Annotation, create, delete bool) format.Annotation {
+		res, err := h.handlePostAnnotation(ctx, ai, a, create, delete)
+		require.NoError(t, err)
+		require.NoError(t, h.waitVersionUpdate(ctx, res.Annotation.Version))
+		return res.Annotation
+	}
+	list := func(from, to int64) []format.Annotation {
+		res, _, err := h.handleGetAnnotationList(admin, "", from, to)
+		require.NoError(t, err)
+		return res.Annotations
+	}
+	now := time.Now().Unix()
+
+	deploy := save(admin, format.Annotation{Name: "deploy", From: now - 60, To: now}, true, false)
+	require.NotZero(t, deploy.ID)
+	incident := save(admin, format.Annotation{Name: "incident", From: now - 3600, To: now - 1800, Namespace: "ns"}, true, false)
+	require.NotEqual(t, deploy.ID, incident.ID)
+
+	// query by time range, ordered by start time
+	require.Equal(t, []string{"incident", "deploy"}, annotationNames(list(now-7200, now+1)))
+	require.Equal(t, []string{"deploy"}, annotationNames(list(now-600, now+1)))
+	info, _, err := h.handleGetAnnotation(ctx, admin, incident.ID, 0)
+	require.NoError(t, err)
+	require.Equal(t, "ns", info.Annotation.Namespace)
+
+	// global annotations are edited by administrators only
+	_, err = h.handlePostAnnotation(ctx, accessInfo{user: "user@"}, format.Annotation{Name: "global", From: now, To: now}, true, false)
+	require.Equal(t, http.StatusForbidden, httpCode(err))
+	_, err = h.handlePostAnnotation(ctx, admin, format.Annotation{Name: "bad", From: now, To: now, Namespace: "1ns"}, true, false)
+	require.Equal(t, http.StatusBadRequest, httpCode(err))
+
+	// expired and deleted annotations are hidden, their entities are not reused
+	old := save(admin, format.Annotation{Name: "old", From: 100, To: now - format.AnnotationMaxAgeOfEnd - 60}, true, false)
+	require.NotContains(t, annotationNames(list(0, now+1)), "old")
+	save(admin, deploy, false, true)
+	require.Equal(t, []string{"incident"}, annotationNames(list(0, now+1)))
+	created := save(admin, format.Annotation{Name: "release", From: now, To: now}, true, false)
+	require.NotContains(t, []int32{deploy.ID, incident.ID, old.ID}, created.ID)
+	require.Equal(t, []string{"incident", "release"}, annotationNames(list(0, now+1)))
+}
+
+func annotationNames(s []format.Annotation) []string {
+	res := make([]string, 0, len(s))
+	for _, a := range s {
+		res = append(res, a.Name)
+	}
+	return res
+}
@@ -0,0 +1,195 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// API token is long-lived secret for machine access, passed instead of JWT in the same header
+// or RPC "access_token" field. Token is shown once on creation, only its hash is stored.
+// Token grants view (and edit unless read-only) access to its namespaces until expire time,
+// revoked tokens are deleted. Tokens are managed by administrators only.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+//go:generate easyjson -no_std_marshalers api_token.go
+
+const (
+	apiTokenPrefix     = "sht_"
+	apiTokenSecretSize = 32
+	apiTokenUserPrefix = "api-token:"
+)
+
+type (
+	//easyjson:json
+	APITokenInfo struct {
+		APIToken format.APIToken `json:"api_token"`
+		Token    string          `json:"token,omitempty"` // set only in response to creation
+		Delete   bool            `json:"delete_mark"`
+	}
+
+	//easyjson:json
+	GetAPITokenListResp struct {
+		APITokens []format.APIToken `json:"api_tokens"`
+	}
+)
+
+type apiTokenStorage interface {
+	GetAPITokenByHash(hash string) *format.APIToken
+}
+
+func isAPIToken(token string) bool {
+	return strings.HasPrefix(token, apiTokenPrefix)
+}
+
+func apiTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newAPIToken() (string, error) {
+	var b [apiTokenSecretSize]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return apiTokenPrefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func parseAPIToken(tokens apiTokenStorage, token string, protectedPrefixes []string, now time.Time) (accessInfo, error) {
+	t := tokens.GetAPITokenByHash(apiTokenHash(token))
+	if t == nil {
+		return accessInfo{}, httpErr(http.StatusUnauthorized, fmt.Errorf("invalid API token"))
+	}
+	if t.Expired(now) {
+		return accessInfo{}, httpErr(http.StatusUnauthorized, fmt.Errorf("API token %q expired", t.Name))
+	}
+	ai := accessInfo{
+		user:              apiTokenUserPrefix + t.Name,
+		service:           true,
+		apiToken:          t.Name,
+		readOnly:          t.ReadOnly,
+		protectedPrefixes: protectedPrefixes,
+		bitViewPrefix:     map[string]bool{},
+		bitEditPrefix:     map[string]bool{},
+		bitViewMetric:     map[string]bool{},
+		bitEditMetric:     map[string]bool{},
+	}
+	if len(t.Namespaces) == 0 {
+		ai.bitViewDefault = true
+		ai.bitEditDefault = !t.ReadOnly
+	}
+	for _, ns := range t.Namespaces {
+		ai.bitViewPrefix[ns+format.NamespaceSeparator] = true
+		if !t.ReadOnly {
+			ai.bitEditPrefix[ns+format.NamespaceSeparator] = true
+		}
+	}
+	return ai, nil
+}
+
+func (h *Handler) handleGetAPIToken(ctx context.Context, ai accessInfo, id int32, version int64) (*APITokenInfo, time.Duration, error) {
+	if !ai.isAdmin() {
+		return nil, 0, httpErr(http.StatusForbidden, fmt.Errorf("admin access required"))
+	}
+	var t format.APIToken
+	if version == 0 {
+		p := h.metricsStorage.GetAPIToken(id)
+		if p == nil {
+			return nil, 0, httpErr(http.StatusNotFound, fmt.Errorf("API token %d not found", id))
+		}
+		t = *p
+	} else {
+		var err error
+		if t, err = h.metadataLoader.GetAPIToken(ctx, int64(id), version); err != nil {
+			return nil, 0, err
+		}
+	}
+	t.Hash = ""
+	return &APITokenInfo{APIToken: t}, defaultCacheTTL, nil
+}
+
+func (h *Handler) handleGetAPITokenList(ai accessInfo, _ bool) (*GetAPITokenListResp, time.Duration, error) {
+	if !ai.isAdmin() {
+		return nil, 0, httpErr(http.StatusForbidden, fmt.Errorf("admin access required"))
+	}
+	resp := &GetAPITokenListResp{APITokens: []format.APIToken{}}
+	for _, t := range h.metricsStorage.GetAPITokenList() {
+		v := *t
+		v.Hash = ""
+		resp.APITokens = append(resp.APITokens, v)
+	}
+	sort.Slice(resp.APITokens, func(i, j int) bool {
+		return resp.APITokens[i].Name < resp.APITokens[j].Name
+	})
+	return resp, defaultCacheTTL, nil
+}
+
+func (h *Handler) handlePostAPIToken(ctx context.Context, ai accessInfo, t format.APIToken, create, delete bool) (*APITokenInfo, error) {
+	if !ai.isAdmin() {
+		return &APITokenInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("admin access required"))
+	}
+	var token string
+	if create {
+		if t.Expired(time.Now()) {
+			return &APITokenInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("API token expire time must be in the future"))
+		}
+		var err error
+		if token, err = newAPIToken(); err != nil {
+			return &APITokenInfo{}, fmt.Errorf("can't generate API token: %w", err)
+		}
+		t.Hash = apiTokenHash(token)
+	} else {
+		old := h.metricsStorage.GetAPIToken(t.ID)
+		if old == nil {
+			return &APITokenInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("API token %d not found", t.ID))
+		}
+		t.Hash = old.Hash // token itself can not be changed
+	}
+	t, err := h.metadataLoader.SaveAPIToken(ctx, t, create, delete, ai.toMetadata())
+	if err != nil {
+		s := "edit"
+		if create {
+			s = "create"
+		}
+		if metajournal.IsUserRequestError(err) {
+			return &APITokenInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("can't %s API token: %w", s, err))
+		}
+		return &APITokenInfo{}, fmt.Errorf("can't %s API token: %w", s, err)
+	}
+	t.Hash = ""
+	return &APITokenInfo{APIToken: t, Token: token}, nil
+}
+
+func (h *Handler) HandleGetAPIToken(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntity(w, r, h, EndpointAPIToken, h.handleGetAPIToken)
+}
+
+func (h *Handler) HandleGetAPITokenList(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntityList(w, r, h, EndpointAPITokenList, h.handleGetAPITokenList)
+}
+
+func (h *Handler) HandlePutPostAPIToken(w http.ResponseWriter, r *http.Request) {
+	var tokenInfo APITokenInfo
+	handlePostEntity(h, w, r, EndpointAPIToken, &tokenInfo, func(ctx context.Context, ai accessInfo, entity *APITokenInfo, create bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handlePostAPIToken(ctx, ai, entity.APIToken, create, entity.Delete)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.APIToken.Version, nil
+	})
+}
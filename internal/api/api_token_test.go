@@ -0,0 +1,78 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+type testAPITokenStorage map[string]*format.APIToken
+
+func (s testAPITokenStorage) GetAPITokenByHash(hash string) *format.APIToken {
+	return s[hash]
+}
+
+func TestParseAPIToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := testAPITokenStorage{}
+	add := func(v format.APIToken) string {
+		token, err := newAPIToken()
+		require.NoError(t, err)
+		require.True(t, isAPIToken(token))
+		v.Hash = apiTokenHash(token)
+		v.ExpireTime = uint32(now.Unix() + 3600)
+		require.NoError(t, v.Validate())
+		tokens[v.Hash] = &v
+		return token
+	}
+	all := add(format.APIToken{Name: "ci"})
+	scoped := add(format.APIToken{Name: "exporter", ReadOnly: true, Namespaces: []string{"team"}})
+
+	ai, err := parseAPIToken(tokens, all, []string{"secret_"}, now)
+	require.NoError(t, err)
+	require.Equal(t, "ci", ai.apiToken)
+	require.True(t, canViewMetric(&ai, "foo"))
+	require.False(t, canViewMetric(&ai, "secret_foo"))
+	require.True(t, canBasicEdit(&ai, "foo", false))
+	require.False(t, ai.isAdmin())
+
+	ai, err = parseAPIToken(tokens, scoped, nil, now)
+	require.NoError(t, err)
+	require.True(t, canViewMetricNamespaced(&ai, "foo", "team"))
+	require.False(t, canViewMetricNamespaced(&ai, "foo", "other"))
+	require.False(t, canViewMetric(&ai, "foo"))
+	require.False(t, canBasicEdit(&ai, "team:foo", false))
+	require.False(t, ai.CanEditDashboard("overview", "overview"))
+
+	es := newEndpointStatRPC(EndpointQuery, "")
+	es.setAccessInfo(ai)
+	require.Equal(t, "exporter", es.tokenName)
+
+	// token of one namespace must not edit dashboards of another
+	teamA := add(format.APIToken{Name: "team-a", Namespaces: []string{"a"}})
+	ai, err = parseAPIToken(tokens, teamA, nil, now)
+	require.NoError(t, err)
+	require.True(t, ai.CanEditDashboard("a:overview", "a:overview"))
+	require.False(t, ai.CanEditDashboard("b:overview", "b:overview"))
+	require.False(t, ai.CanEditDashboard("a:overview", "b:overview"))
+	require.False(t, ai.CanEditDashboard("overview", "overview"))
+	ai.roles = &testRoleStorage{namespaces: map[string]*format.NamespaceMeta{"b": {Name: "b"}}} // namespace without roles
+	require.False(t, ai.CanEditDashboard("b:overview", "b:overview"))
+	ai, err = parseAPIToken(tokens, all, nil, now)
+	require.NoError(t, err)
+	require.True(t, ai.CanEditDashboard("b:overview", "b:overview"))
+
+	_, err = parseAPIToken(tokens, all, nil, now.Add(time.Hour))
+	require.Error(t, err)
+	_, err = parseAPIToken(tokens, apiTokenPrefix+"unknown", nil, now)
+	require.Error(t, err)
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Cardinality explorer answers which tag is exploding metric cardinality.
+// Aggregators write per-tag estimates into "__agg_hour_tag_cardinality" and "__agg_hour_tag_top_values",
+// here we combine them over time range and aggregator shards.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ClickHouse/ch-go"
+	"github.com/ClickHouse/ch-go/proto"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/util"
+)
+
+//go:generate easyjson -no_std_marshalers cardinality.go
+
+const (
+	cardinalityMaxRange1m     = 6 * time.Hour // minute table is used for shorter ranges
+	cardinalityTopValuesLimit = 5             // aggregators report not more
+)
+
+type (
+	//easyjson:json
+	CardinalityResp struct {
+		Metric      string           `json:"metric"`
+		Cardinality float64          `json:"cardinality"` // unique series per hour, as avg() of "__agg_hour_cardinality"
+		Tags        []CardinalityTag `json:"tags"`        // sorted by distinct_upper descending
+	}
+
+	CardinalityTag struct {
+		ID            string                `json:"id"`
+		Name          string                `json:"name,omitempty"`
+		DistinctLower float64               `json:"distinct_lower"` // unique values per hour, reached if all aggregator shards receive the same values
+		DistinctUpper float64               `json:"distinct_upper"` // reached if aggregator shards receive different values
+		TopValues     []CardinalityTagValue `json:"top_values,omitempty"`
+	}
+
+	CardinalityTagValue struct {
+		Value string  `json:"value"`
+		Share float64 `json:"share"` // of new series, from 0 to 1
+	}
+
+	cardinalityRow struct {
+		tag   int32
+		key   int32 // aggregator shard or tag value
+		sum   float64
+		count float64
+	}
+)
+
+// selects avg() of built-in metric with metric prekey, grouped by key2 and another key
+func (h *Handler) selectCardinality(ctx context.Context, ai accessInfo, builtinMetricID int32, metricID int32, groupKey string, from time.Time, to time.Time) ([]cardinalityRow, error) {
+	table := _1mTableSH2
+	if to.Sub(from) > cardinalityMaxRange1m {
+		table = _1hTableSH2
+	}
+	// no need to escape anything as long as table and column names are fixed
+	query, err := util.BindQuery(fmt.Sprintf(`
+SELECT
+  toInt32(key2) AS _tag, toInt32(%s) AS _key, toFloat64(sum(sum)) AS _sum, toFloat64(sum(count)) AS _count
+FROM
+  %s
+WHERE
+  metric = ? AND prekey = ? AND time >= ? AND time < ?
+GROUP BY
+  key2, %s
+`, groupKey, preKeyTableNames[table], groupKey), builtinMetricID, metricID, from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tag   proto.ColInt32
+		key   proto.ColInt32
+		sum   proto.ColFloat64
+		count proto.ColFloat64
+		res   []cardinalityRow
+	)
+	err = h.doSelect(ctx, util.QueryMetaInto{
+		IsFast:  to.Sub(from) <= fastQueryTimeInterval*time.Second,
+		IsLight: true,
+		User:    ai.user,
+		Metric:  builtinMetricID,
+		Table:   table,
+		Kind:    "cardinality",
+	}, Version2, ch.Query{
+		Body: query,
+		Result: proto.Results{
+			{Name: "_tag", Data: &tag},
+			{Name: "_key", Data: &key},
+			{Name: "_sum", Data: &sum},
+			{Name: "_count", Data: &count},
+		},
+		OnResult: func(_ context.Context, b proto.Block) error {
+			for i := 0; i < b.Rows; i++ {
+				res = append(res, cardinalityRow{tag: tag[i], key: key[i], sum: sum[i], count: count[i]})
+			}
+			return nil
+		}})
+	return res, err
+}
+
+func (h *Handler) handleGetCardinality(ctx context.Context, ai accessInfo, metricWithNamespace string, fromTS string, toTS string) (*CardinalityResp, error) {
+	metricMeta, err := h.getMetricMeta(ai, metricWithNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if metricMeta.MetricID < 0 {
+		return nil, httpErr(http.StatusBadRequest, fmt.Errorf("cardinality of built-in metric %q is not tracked", metricMeta.Name))
+	}
+	from, to, err := parseFromTo(fromTS, toTS)
+	if err != nil {
+		return nil, err
+	}
+	// "__agg_hour_cardinality" has metric in key4, so we select it with the same query, key2 is always 0
+	total, err := h.selectCardinality(ctx, ai, format.BuiltinMetricIDAggHourCardinality, metricMeta.MetricID, "key0", from, to)
+	if err != nil {
+		return nil, err
+	}
+	distinct, err := h.selectCardinality(ctx, ai, format.BuiltinMetricIDAggHourTagCardinality, metricMeta.MetricID, fmt.Sprintf("key%d", format.AggShardTag), from, to)
+	if err != nil {
+		return nil, err
+	}
+	top, err := h.selectCardinality(ctx, ai, format.BuiltinMetricIDAggHourTagTopValues, metricMeta.MetricID, "key3", from, to)
+	if err != nil {
+		return nil, err
+	}
+	return combineCardinality(metricMeta, total, distinct, top, func(tagID string, value int32) string {
+		return h.getRichTagValue(metricMeta, Version2, tagID, value)
+	}), nil
+}
+
+func combineCardinality(metricMeta *format.MetricMetaValue, total []cardinalityRow, distinct []cardinalityRow, top []cardinalityRow, tagValue func(tagID string, value int32) string) *CardinalityResp {
+	res := &CardinalityResp{Metric: metricMeta.Name, Tags: []CardinalityTag{}}
+	var totalSum, totalCount float64
+	for _, r := range total {
+		totalSum += r.sum
+		totalCount += r.count
+	}
+	if totalCount != 0 {
+		res.Cardinality = totalSum / totalCount
+	}
+	tags := map[int32]*CardinalityTag{}
+	reports := map[int32]float64{} // top values are written only for large tags, so missing value means 0 share
+	for _, r := range distinct {
+		if r.tag < 0 || r.tag >= format.MaxTags || r.count == 0 {
+			continue
+		}
+		t := tags[r.tag]
+		if t == nil {
+			t = &CardinalityTag{ID: format.TagID(int(r.tag))}
+			if int(r.tag) < len(metricMeta.Tags) {
+				t.Name = metricMeta.Tags[r.tag].Name
+			}
+			tags[r.tag] = t
+		}
+		avg := r.sum / r.count
+		t.DistinctLower = max(t.DistinctLower, avg)
+		t.DistinctUpper += avg
+		reports[r.tag] += r.count
+	}
+	for _, r := range top {
+		t := tags[r.tag]
+		if t == nil || reports[r.tag] == 0 {
+			continue
+		}
+		t.TopValues = append(t.TopValues, CardinalityTagValue{Value: tagValue(t.ID, r.key), Share: r.sum / reports[r.tag]})
+	}
+	for _, t := range tags {
+		sort.Slice(t.TopValues, func(i, j int) bool {
+			if t.TopValues[i].Share != t.TopValues[j].Share {
+				return t.TopValues[i].Share > t.TopValues[j].Share
+			}
+			return t.TopValues[i].Value < t.TopValues[j].Value
+		})
+		if len(t.TopValues) > cardinalityTopValuesLimit {
+			t.TopValues = t.TopValues[:cardinalityTopValuesLimit]
+		}
+		res.Tags = append(res.Tags, *t)
+	}
+	sort.Slice(res.Tags, func(i, j int) bool {
+		if res.Tags[i].DistinctUpper != res.Tags[j].DistinctUpper {
+			return res.Tags[i].DistinctUpper > res.Tags[j].DistinctUpper
+		}
+		return res.Tags[i].ID < res.Tags[j].ID
+	})
+	return res
+}
+
+func (h *Handler) HandleGetCardinality(w http.ResponseWriter, r *http.Request) {
+	sl := newEndpointStatHTTP(EndpointCardinality, r.Method, 0, "", r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.querySelectTimeout)
+	defer cancel()
+	resp, err := h.handleGetCardinality(ctx, ai, formValueParamMetric(r), r.FormValue(ParamFromTime), r.FormValue(ParamToTime))
+	respondJSON(w, resp, 0, 0, err, h.verbose, ai.user, sl)
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestCombineCardinality(t *testing.T) {
+	meta := &format.MetricMetaValue{Name: "api_requests", Tags: []format.MetricMetaTag{{Name: "env"}, {Name: "user_id"}}}
+	total := []cardinalityRow{{sum: 3000, count: 2}}
+	distinct := []cardinalityRow{
+		{tag: 0, key: 1, sum: 4, count: 2}, // 2 values on both shards
+		{tag: 0, key: 2, sum: 4, count: 2},
+		{tag: 1, key: 1, sum: 1000, count: 2},
+		{tag: 1, key: 2, sum: 600, count: 2},
+	}
+	top := []cardinalityRow{
+		{tag: 1, key: 5, sum: 0.4},
+		{tag: 1, key: 6, sum: 2},
+	}
+	resp := combineCardinality(meta, total, distinct, top, func(tagID string, value int32) string {
+		return tagID + ":" + strconv.Itoa(int(value))
+	})
+	require.Equal(t, &CardinalityResp{
+		Metric:      "api_requests",
+		Cardinality: 1500,
+		Tags: []CardinalityTag{{
+			ID:            "1",
+			Name:          "user_id",
+			DistinctLower: 500,
+			DistinctUpper: 800,
+			TopValues:     []CardinalityTagValue{{Value: "1:6", Share: 0.5}, {Value: "1:5", Share: 0.1}},
+		}, {
+			ID:            "0",
+			Name:          "env",
+			DistinctLower: 2,
+			DistinctUpper: 4,
+		}},
+	}, resp)
+}
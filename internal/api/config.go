@@ -44,6 +44,10 @@ type HandlerOptions struct {
 	weekStartAt             int
 	location                *time.Location
 	utcOffset               int64
+	alerting                bool
+	recordingRules          bool
+	samplingExplainAddrs    []string
+	alertWebhookHosts       []string
 }
 
 func (argv *HandlerOptions) Bind(pflag *pflag.FlagSet) {
@@ -56,6 +60,10 @@ func (argv *HandlerOptions) Bind(pflag *pflag.FlagSet) {
 	pflag.StringSliceVar(&argv.protectedMetricPrefixes, "protected-metric-prefixes", nil, "comma-separated list of metric prefixes that require access bits set")
 	pflag.StringVar(&argv.timezone, "timezone", "Europe/Moscow", "location of the desired timezone")
 	pflag.IntVar(&argv.weekStartAt, "week-start", int(time.Monday), "week day of beginning of the week (from sunday=0 to saturday=6)")
+	pflag.BoolVar(&argv.alerting, "alerting", false, "evaluate alert rules and send notifications, enable on single API instance only")
+	pflag.BoolVar(&argv.recordingRules, "recording-rules", false, "evaluate recording rules and write results as metrics, enable on single API instance only")
+	pflag.StringSliceVar(&argv.alertWebhookHosts, "alert-webhook-hosts", nil, "comma-separated list of hosts (host or host:port) alert rule webhooks are allowed to post to")
+	pflag.StringSliceVar(&argv.samplingExplainAddrs, "sampling-explain-addr", nil, "comma-separated list of agent and aggregator RPC addresses asked by sampling explain endpoint")
 }
 
 func (argv *HandlerOptions) LoadLocation() error {
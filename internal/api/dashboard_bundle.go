@@ -0,0 +1,270 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Dashboard bundle moves dashboard between clusters together with metrics, groups and namespaces it references.
+// Entities are matched by name on import, existing ones are left intact, missing ones are created with new IDs.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/promql/parser"
+)
+
+//go:generate easyjson -no_std_marshalers dashboard_bundle.go
+
+const dashboardSearchParamsField = "searchParams" // UI stores dashboard as URL search params
+
+// plot parameters are not prefixed for the first plot and prefixed with "t<N>." for others
+var dashboardPlotParamRe = regexp.MustCompile(`^(t\d+\.)?(s|q)$`)
+
+type (
+	//easyjson:json
+	DashboardBundle struct {
+		Dashboard  DashboardMetaInfo        `json:"dashboard"`
+		Metrics    []format.MetricMetaValue `json:"metrics"`
+		Groups     []format.MetricsGroup    `json:"groups"`
+		Namespaces []format.NamespaceMeta   `json:"namespaces"`
+	}
+
+	//easyjson:json
+	DashboardImportResp struct {
+		Dashboard  DashboardMetaInfo `json:"dashboard"`
+		Metrics    []string          `json:"metrics"`    // created, existing are not changed
+		Groups     []string          `json:"groups"`     // created, existing are not changed
+		Namespaces []string          `json:"namespaces"` // created, existing are not changed
+		Warnings   []string          `json:"warnings,omitempty"`
+	}
+)
+
+func dashboardSearchParams(data map[string]interface{}) [][2]string {
+	params, _ := data[dashboardSearchParamsField].([]interface{})
+	res := make([][2]string, 0, len(params))
+	for _, p := range params {
+		kv, ok := p.([]interface{})
+		if !ok || len(kv) != 2 {
+			continue
+		}
+		k, ok1 := kv[0].(string)
+		v, ok2 := kv[1].(string)
+		if ok1 && ok2 {
+			res = append(res, [2]string{k, v})
+		}
+	}
+	return res
+}
+
+func setDashboardSearchParams(data map[string]interface{}, params [][2]string) {
+	res := make([]interface{}, 0, len(params))
+	for _, kv := range params {
+		res = append(res, []interface{}{kv[0], kv[1]})
+	}
+	data[dashboardSearchParamsField] = res
+}
+
+// names of metrics used by dashboard plots directly or in PromQL queries
+func dashboardMetricNames(data map[string]interface{}) []string {
+	var names []string
+	add := func(metric string, promQL string) {
+		if metric != "" {
+			names = append(names, metric)
+		}
+		if promQL == "" {
+			return
+		}
+		expr, err := parser.ParseExpr(promQL)
+		if err != nil {
+			return
+		}
+		parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+			if s, ok := node.(*parser.VectorSelector); ok && s.Name != "" {
+				names = append(names, s.Name)
+			}
+			return nil
+		})
+	}
+	if params := dashboardSearchParams(data); len(params) != 0 {
+		for _, kv := range params {
+			if m := dashboardPlotParamRe.FindStringSubmatch(kv[0]); m != nil {
+				if m[2] == ParamMetric {
+					add(kv[1], "")
+				} else {
+					add("", kv[1])
+				}
+			}
+		}
+	} else {
+		plots, _ := data["plots"].([]interface{}) // dashboards saved by old UI
+		for _, p := range plots {
+			if plot, ok := p.(map[string]interface{}); ok {
+				metric, _ := plot["metricName"].(string)
+				promQL, _ := plot["promQL"].(string)
+				add(metric, promQL)
+			}
+		}
+	}
+	sort.Strings(names)
+	res := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+func (h *Handler) handleExportDashboard(ctx context.Context, ai accessInfo, id int32, version int64) (*DashboardBundle, time.Duration, error) {
+	dash, cache, err := h.handleGetDashboard(ctx, ai, id, version)
+	if err != nil {
+		return nil, 0, err
+	}
+	res := &DashboardBundle{
+		Dashboard:  dash.Dashboard,
+		Metrics:    []format.MetricMetaValue{},
+		Groups:     []format.MetricsGroup{},
+		Namespaces: []format.NamespaceMeta{},
+	}
+	groups := map[int32]bool{}
+	namespaces := map[int32]bool{}
+	for _, name := range dashboardMetricNames(dash.Dashboard.JSONData) {
+		meta := h.metricsStorage.GetMetaMetricByName(name)
+		if meta == nil || meta.MetricID < 0 || !ai.CanViewMetric(*meta) {
+			continue // builtin metrics exist everywhere
+		}
+		res.Metrics = append(res.Metrics, *meta)
+		if g := meta.Group; g != nil && g.ID > 0 && !groups[g.ID] {
+			groups[g.ID] = true
+			res.Groups = append(res.Groups, *g)
+		}
+		if ns := meta.Namespace; ns != nil && ns.ID > 0 && !namespaces[ns.ID] {
+			namespaces[ns.ID] = true
+			res.Namespaces = append(res.Namespaces, *ns)
+		}
+	}
+	for _, g := range res.Groups {
+		if ns := h.metricsStorage.GetNamespace(g.NamespaceID); ns != nil && ns.ID > 0 && !namespaces[ns.ID] {
+			namespaces[ns.ID] = true
+			res.Namespaces = append(res.Namespaces, *ns)
+		}
+	}
+	return res, cache, nil
+}
+
+func (h *Handler) handleImportDashboard(ctx context.Context, ai accessInfo, b DashboardBundle) (*DashboardImportResp, error) {
+	res := &DashboardImportResp{Metrics: []string{}, Groups: []string{}, Namespaces: []string{}}
+	namespaceNames := map[int32]string{} // source cluster namespace ID -> name
+	for _, ns := range b.Namespaces {
+		namespaceNames[ns.ID] = ns.Name
+		if ns.ID < 0 || h.metricsStorage.GetNamespaceByName(ns.Name) != nil {
+			continue
+		}
+		ns.ID = 0
+		ns.Version = 0
+		info, err := h.handlePostNamespace(ctx, ai, ns, true)
+		if err != nil {
+			return nil, err
+		}
+		if err = h.waitVersionUpdate(ctx, info.Namespace.Version); err != nil {
+			return nil, err
+		}
+		res.Namespaces = append(res.Namespaces, ns.Name)
+	}
+	namespaceID := func(sourceID int32) (int32, error) {
+		if sourceID <= 0 {
+			return sourceID, nil // builtin namespace IDs are the same everywhere
+		}
+		name, ok := namespaceNames[sourceID]
+		if !ok {
+			return 0, httpErr(http.StatusBadRequest, fmt.Errorf("namespace %d is not in bundle", sourceID))
+		}
+		ns := h.metricsStorage.GetNamespaceByName(name)
+		if ns == nil {
+			return 0, httpErr(http.StatusBadRequest, fmt.Errorf("namespace %q not found", name))
+		}
+		return ns.ID, nil
+	}
+	for _, g := range b.Groups {
+		if g.ID < 0 || h.metricsStorage.GetGroupByName(g.Name) != nil {
+			continue
+		}
+		var err error
+		if g.NamespaceID, err = namespaceID(g.NamespaceID); err != nil {
+			return nil, err
+		}
+		g.ID = 0
+		g.Version = 0
+		if _, err = h.handlePostGroup(ctx, ai, g, true); err != nil {
+			return nil, err
+		}
+		res.Groups = append(res.Groups, g.Name)
+	}
+	for _, m := range b.Metrics {
+		if m.MetricID < 0 || h.metricsStorage.GetMetaMetricByName(m.Name) != nil {
+			continue
+		}
+		var err error
+		if m.NamespaceID, err = namespaceID(m.NamespaceID); err != nil {
+			return nil, err
+		}
+		m.MetricID = 0
+		m.Version = 0
+		created, err := h.handlePostMetric(ctx, ai, m.Name, m)
+		if err != nil {
+			return nil, err
+		}
+		if err = h.waitVersionUpdate(ctx, created.Version); err != nil {
+			return nil, err
+		}
+		res.Metrics = append(res.Metrics, m.Name)
+	}
+	dash := b.Dashboard
+	dash.DashboardID = 0
+	dash.Version = 0
+	if dash.JSONData != nil {
+		// dashboard ID is saved by UI, it is meaningless in another cluster
+		params := dashboardSearchParams(dash.JSONData)
+		kept := params[:0]
+		for _, kv := range params {
+			if kv[0] != paramDashboardID {
+				kept = append(kept, kv)
+			}
+		}
+		if _, ok := dash.JSONData[dashboardSearchParamsField]; ok {
+			setDashboardSearchParams(dash.JSONData, kept)
+		}
+		if d, ok := dash.JSONData["dashboard"].(map[string]interface{}); ok {
+			delete(d, "dashboard_id")
+		}
+	}
+	info, err := h.handlePostDashboard(ctx, ai, dash, true, false)
+	if err != nil {
+		return nil, err
+	}
+	res.Dashboard = info.Dashboard
+	return res, nil
+}
+
+func (h *Handler) HandleGetDashboardExport(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntity(w, r, h, EndpointDashboardExport, h.handleExportDashboard)
+}
+
+func (h *Handler) HandlePostDashboardImport(w http.ResponseWriter, r *http.Request) {
+	var bundle DashboardBundle
+	handlePostEntity(h, w, r, EndpointDashboardImport, &bundle, func(ctx context.Context, ai accessInfo, entity *DashboardBundle, _ bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handleImportDashboard(ctx, ai, *entity)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.Dashboard.Version, nil
+	})
+}
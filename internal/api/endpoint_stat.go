@@ -21,28 +21,47 @@ import (
 )
 
 const (
-	RoutePrefix                 = "/api/"
-	EndpointMetric              = "metric"
-	EndpointMetricList          = "metrics-list"
-	EndpointMetricTagValues     = "metric-tag-values"
-	EndpointQuery               = "query"
-	EndpointTable               = "table"
-	EndpointPoint               = "point"
-	EndpointRender              = "render"
-	EndpointResetFlood          = "reset-flood"
-	EndpointLegacyRedirect      = "legacy-redirect"
-	EndpointDashboard           = "dashboard"
-	EndpointDashboardList       = "dashboards-list"
-	EndpointGroup               = "group"
-	EndpointNamespace           = "namespace"
-	EndpointNamespaceList       = "namespace-list"
-	EndpointGroupList           = "group-list"
-	EndpointPrometheus          = "prometheus"
-	EndpointPrometheusGenerated = "prometheus-generated"
-	EndpointKnownTags           = "known-tags"
-	EndpointStatistics          = "stat"
-	endpointChunk               = "chunk"
-	EndpointHistory             = "history"
+	RoutePrefix                    = "/api/"
+	EndpointMetric                 = "metric"
+	EndpointMetricList             = "metrics-list"
+	EndpointMetricTagValues        = "metric-tag-values"
+	EndpointQuery                  = "query"
+	EndpointQueryLive              = "query-live"
+	EndpointTable                  = "table"
+	EndpointPoint                  = "point"
+	EndpointRender                 = "render"
+	EndpointResetFlood             = "reset-flood"
+	EndpointLegacyRedirect         = "legacy-redirect"
+	EndpointDashboard              = "dashboard"
+	EndpointDashboardList          = "dashboards-list"
+	EndpointDashboardExport        = "dashboard-export"
+	EndpointDashboardImport        = "dashboard-import"
+	EndpointDashboardImportGrafana = "dashboard-import-grafana"
+	EndpointGroup                  = "group"
+	EndpointNamespace              = "namespace"
+	EndpointNamespaceList          = "namespace-list"
+	EndpointGroupList              = "group-list"
+	EndpointPrometheus             = "prometheus"
+	EndpointPrometheusGenerated    = "prometheus-generated"
+	EndpointKnownTags              = "known-tags"
+	EndpointStatistics             = "stat"
+	endpointChunk                  = "chunk"
+	EndpointHistory                = "history"
+	EndpointHistoryDiff            = "history-diff"
+	EndpointHistoryRollback        = "history-rollback"
+	EndpointAlertRule              = "alert-rule"
+	EndpointAlertRuleList          = "alert-rules-list"
+	EndpointRecordingRule          = "recording-rule"
+	EndpointRecordingRuleList      = "recording-rules-list"
+	EndpointAnnotation             = "annotation"
+	EndpointAnnotationList         = "annotations-list"
+	EndpointAPIToken               = "api-token"
+	EndpointAPITokenList           = "api-tokens-list"
+	EndpointMetadataSync           = "metadata-sync"
+	EndpointMappingSearch          = "mapping-search"
+	EndpointMappingExport          = "mapping-export"
+	EndpointCardinality            = "cardinality"
+	EndpointSamplingExplain        = "sampling-explain"
 
 	userTokenName = "user"
 )
@@ -114,6 +133,9 @@ func (es *endpointStat) reportServiceTime(code int, err error) {
 func (es *endpointStat) setAccessInfo(ai accessInfo) {
 	es.user = ai.user
 	es.tokenName = getStatTokenName(ai.user)
+	if ai.apiToken != "" {
+		es.tokenName = ai.apiToken
+	}
 }
 
 func (es *endpointStat) setMetricMeta(metricMeta *format.MetricMetaValue) {
@@ -0,0 +1,285 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Rollback re-saves older version of metric, dashboard, group or namespace as the new version,
+// so it goes through the same access checks as edit and is itself visible in history.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+//go:generate easyjson -no_std_marshalers entity_history.go
+
+const (
+	paramFromEntityVersion = "from_ver"
+	paramToEntityVersion   = "to_ver" // current version if not set
+)
+
+type (
+	//easyjson:json
+	EntityRollbackReq struct {
+		ID      int64 `json:"id"`
+		Version int64 `json:"version"` // to restore
+	}
+
+	//easyjson:json
+	EntityRollbackResp struct {
+		ID      int64  `json:"id"`
+		Kind    string `json:"kind"`
+		Version int64  `json:"version"` // new version with restored content
+	}
+
+	//easyjson:json
+	EntityDiffResp struct {
+		ID      int64               `json:"id"`
+		Kind    string              `json:"kind"`
+		From    int64               `json:"from"`
+		To      int64               `json:"to"`
+		Changes []EntityFieldChange `json:"changes"`
+	}
+
+	EntityFieldChange struct {
+		Path string      `json:"path"` // dot separated, for example "tags.1.description"
+		Old  interface{} `json:"old"`  // null if field was added
+		New  interface{} `json:"new"`  // null if field was removed
+	}
+)
+
+func (h *Handler) currentEntityVersion(ai accessInfo, eventType int32, id int64) (int64, error) {
+	switch eventType {
+	case format.MetricEvent:
+		if m := h.metricsStorage.GetMetaMetric(int32(id)); m != nil && ai.CanViewMetric(*m) {
+			return m.Version, nil
+		}
+	case format.DashboardEvent:
+		if d := h.metricsStorage.GetDashboardMeta(int32(id)); d != nil {
+			return d.Version, nil
+		}
+	case format.MetricsGroupEvent:
+		if g := h.metricsStorage.GetGroup(int32(id)); g != nil {
+			return g.Version, nil
+		}
+	case format.NamespaceEvent:
+		if ns := h.metricsStorage.GetNamespace(int32(id)); ns != nil {
+			return ns.Version, nil
+		}
+	default:
+		return 0, httpErr(http.StatusBadRequest, fmt.Errorf("%s history is not supported", format.EventTypeToName(eventType)))
+	}
+	return 0, httpErr(http.StatusNotFound, fmt.Errorf("%s %d not found", format.EventTypeToName(eventType), id))
+}
+
+func (h *Handler) handleRollbackEntity(ctx context.Context, ai accessInfo, id int64, version int64) (*EntityRollbackResp, error) {
+	event, err := h.metadataLoader.GetEntity(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	current, err := h.currentEntityVersion(ai, event.EventType, id)
+	if err != nil {
+		return nil, err
+	}
+	if version >= current {
+		return nil, httpErr(http.StatusBadRequest, fmt.Errorf("version %d is not older than current version %d", version, current))
+	}
+	res := &EntityRollbackResp{ID: id, Kind: format.EventTypeToName(event.EventType)}
+	switch event.EventType {
+	case format.MetricEvent:
+		m, err := h.metadataLoader.GetMetric(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		m.Version = current
+		if m, err = h.handlePostMetric(ctx, ai, m.Name, m); err != nil {
+			return nil, err
+		}
+		res.Version = m.Version
+	case format.DashboardEvent:
+		d, err := h.metadataLoader.GetDashboard(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		dash := getDashboardMetaInfo(&d)
+		dash.Version = current
+		info, err := h.handlePostDashboard(ctx, ai, dash, false, false)
+		if err != nil {
+			return nil, err
+		}
+		res.Version = info.Dashboard.Version
+	case format.MetricsGroupEvent:
+		g, err := h.metadataLoader.GetMetricsGroup(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		g.Version = current
+		info, err := h.handlePostGroup(ctx, ai, g, false)
+		if err != nil {
+			return nil, err
+		}
+		res.Version = info.Group.Version
+	case format.NamespaceEvent:
+		ns, err := h.metadataLoader.GetNamespace(ctx, id, version)
+		if err != nil {
+			return nil, err
+		}
+		ns.Version = current
+		info, err := h.handlePostNamespace(ctx, ai, ns, false)
+		if err != nil {
+			return nil, err
+		}
+		res.Version = info.Namespace.Version
+	}
+	return res, nil
+}
+
+func (h *Handler) handleGetEntityDiff(ctx context.Context, ai accessInfo, id int64, from int64, to int64) (*EntityDiffResp, error) {
+	a, err := h.metadataLoader.GetEntity(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	current, err := h.currentEntityVersion(ai, a.EventType, id)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = current
+	}
+	b, err := h.metadataLoader.GetEntity(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	if a.EventType != b.EventType {
+		return nil, httpErr(http.StatusBadRequest, fmt.Errorf("versions %d and %d are different entities", from, to))
+	}
+	if a.EventType == format.MetricEvent && !(ai.CanViewMetricName(a.Name) && ai.CanViewMetricName(b.Name)) {
+		return nil, httpErr(http.StatusForbidden, fmt.Errorf("metric %d is not visible", id))
+	}
+	docA, err := entityHistoryDoc(a)
+	if err != nil {
+		return nil, err
+	}
+	docB, err := entityHistoryDoc(b)
+	if err != nil {
+		return nil, err
+	}
+	return &EntityDiffResp{
+		ID:      id,
+		Kind:    format.EventTypeToName(a.EventType),
+		From:    from,
+		To:      to,
+		Changes: entityDiff("", docA, docB, []EntityFieldChange{}),
+	}, nil
+}
+
+// entity data as stored in journal, name is kept separately
+func entityHistoryDoc(e tlmetadata.Event) (map[string]interface{}, error) {
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(e.Data), &doc); err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s version %d: %w", format.EventTypeToName(e.EventType), e.Version, err)
+	}
+	doc["name"] = e.Name
+	delete(doc, "version") // differ always
+	delete(doc, "update_time")
+	return doc, nil
+}
+
+func entityDiff(path string, a, b interface{}, res []EntityFieldChange) []EntityFieldChange {
+	join := func(k string) string {
+		if path == "" {
+			return k
+		}
+		return path + "." + k
+	}
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(x)+len(y))
+			for k := range x {
+				keys = append(keys, k)
+			}
+			for k := range y {
+				if _, ok := x[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				res = entityDiff(join(k), x[k], y[k], res)
+			}
+			return res
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			for i := 0; i < len(x) || i < len(y); i++ {
+				var u, v interface{}
+				if i < len(x) {
+					u = x[i]
+				}
+				if i < len(y) {
+					v = y[i]
+				}
+				res = entityDiff(join(strconv.Itoa(i)), u, v, res)
+			}
+			return res
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		res = append(res, EntityFieldChange{Path: path, Old: a, New: b})
+	}
+	return res
+}
+
+func (h *Handler) HandleGetHistoryDiff(w http.ResponseWriter, r *http.Request) {
+	sl := newEndpointStatHTTP(EndpointHistoryDiff, r.Method, 0, "", r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	var id, from, to int64
+	for _, p := range []struct {
+		name     string
+		v        *int64
+		optional bool
+	}{{ParamID, &id, false}, {paramFromEntityVersion, &from, false}, {paramToEntityVersion, &to, true}} {
+		s := r.FormValue(p.name)
+		if s == "" && p.optional {
+			continue
+		}
+		if *p.v, err = strconv.ParseInt(s, 10, 64); err != nil {
+			respondJSON(w, nil, 0, 0, httpErr(http.StatusBadRequest, fmt.Errorf("can't parse %s: %w", p.name, err)), h.verbose, ai.user, sl)
+			return
+		}
+	}
+	resp, err := h.handleGetEntityDiff(r.Context(), ai, id, from, to)
+	var cache time.Duration
+	if err == nil && r.FormValue(paramToEntityVersion) != "" {
+		cache = defaultCacheTTL // versions are immutable
+	}
+	respondJSON(w, resp, cache, 0, err, h.verbose, ai.user, sl)
+}
+
+func (h *Handler) HandlePostHistoryRollback(w http.ResponseWriter, r *http.Request) {
+	var req EntityRollbackReq
+	handlePostEntity(h, w, r, EndpointHistoryRollback, &req, func(ctx context.Context, ai accessInfo, entity *EntityRollbackReq, _ bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handleRollbackEntity(ctx, ai, entity.ID, entity.Version)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.Version, nil
+	})
+}
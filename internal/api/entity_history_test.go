@@ -0,0 +1,40 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestEntityDiff(t *testing.T) {
+	a, err := entityHistoryDoc(tlmetadata.Event{
+		Name:      "api_requests",
+		EventType: format.MetricEvent,
+		Version:   10,
+		Data:      `{"kind":"counter","version":10,"update_time":1,"tags":[{"description":"environment"},{"name":"method"}]}`,
+	})
+	require.NoError(t, err)
+	b, err := entityHistoryDoc(tlmetadata.Event{
+		Name:      "api_requests",
+		EventType: format.MetricEvent,
+		Version:   12,
+		Data:      `{"kind":"value","version":12,"update_time":2,"description":"requests","tags":[{"description":"environment"},{"name":"handler"},{"name":"status"}]}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []EntityFieldChange{
+		{Path: "description", Old: nil, New: "requests"},
+		{Path: "kind", Old: "counter", New: "value"},
+		{Path: "tags.1.name", Old: "method", New: "handler"},
+		{Path: "tags.2", Old: nil, New: map[string]interface{}{"name": "status"}},
+	}, entityDiff("", a, b, []EntityFieldChange{}))
+	require.Empty(t, entityDiff("", a, a, []EntityFieldChange{}))
+}
@@ -0,0 +1,393 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Query results are exported in columnar formats as flat table: time, time shift, one string column
+// per tag and one value column per "what", missing values are nulls. Table pages are written and
+// flushed as soon as they are read. Series are exported from complete series response, so they are
+// held in memory like for JSON, only conversion is done in batches of exportBatchRows rows.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/util/parquet"
+)
+
+const exportBatchRows = 65536
+
+type exportSchema struct {
+	tagKeys []string // keys of SeriesMetaTag maps
+	tags    []string // column names
+	values  []string // column names
+}
+
+type exportBatch struct {
+	time      []int64 // unix seconds
+	timeShift []int64 // seconds
+	tags      [][]string
+	values    [][]float64 // NaN is exported as null
+}
+
+type exportWriter interface {
+	writeBatch(b *exportBatch) error
+	close() error
+}
+
+func isExportDataFormat(dataFormat string) bool {
+	return dataFormat == dataFormatArrow || dataFormat == dataFormatParquet
+}
+
+func newExportSchema(metric *format.MetricMetaValue, tagKeys []string, values []string) exportSchema {
+	index := func(key string) int {
+		if metric != nil {
+			if tag, ok, _ := metric.APICompatGetTag(key); ok && tag.Index >= 0 {
+				return tag.Index
+			}
+		}
+		return format.MaxTags // string top and unknown tags go last
+	}
+	sort.SliceStable(tagKeys, func(i, j int) bool {
+		if a, b := index(tagKeys[i]), index(tagKeys[j]); a != b {
+			return a < b
+		}
+		return tagKeys[i] < tagKeys[j]
+	})
+	s := exportSchema{tagKeys: tagKeys, values: values}
+	for _, key := range tagKeys {
+		name := key
+		if metric != nil {
+			if tag, ok, _ := metric.APICompatGetTag(key); ok && tag.Name != "" {
+				name = tag.Name
+			}
+		}
+		s.tags = append(s.tags, name)
+	}
+	return s
+}
+
+func (s *exportSchema) newBatch() *exportBatch {
+	return &exportBatch{
+		tags:   make([][]string, len(s.tags)),
+		values: make([][]float64, len(s.values)),
+	}
+}
+
+func (b *exportBatch) len() int {
+	return len(b.time)
+}
+
+func (b *exportBatch) reset() {
+	b.time = b.time[:0]
+	b.timeShift = b.timeShift[:0]
+	for i := range b.tags {
+		b.tags[i] = b.tags[i][:0]
+	}
+	for i := range b.values {
+		b.values[i] = b.values[i][:0]
+	}
+}
+
+func (b *exportBatch) appendRow(s *exportSchema, t int64, timeShift int64, tags map[string]SeriesMetaTag) {
+	b.time = append(b.time, t)
+	b.timeShift = append(b.timeShift, timeShift)
+	for i, key := range s.tagKeys {
+		b.tags[i] = append(b.tags[i], tags[key].Value)
+	}
+}
+
+func newExportWriter(w io.Writer, dataFormat string, s exportSchema) (exportWriter, error) {
+	switch dataFormat {
+	case dataFormatArrow:
+		return newArrowExportWriter(w, s), nil
+	case dataFormatParquet:
+		return newParquetExportWriter(w, s)
+	default:
+		return nil, fmt.Errorf("unsupported export data format %q", dataFormat)
+	}
+}
+
+type arrowExportWriter struct {
+	w *ipc.Writer
+	b *array.RecordBuilder
+}
+
+func newArrowExportWriter(w io.Writer, s exportSchema) *arrowExportWriter {
+	fields := []arrow.Field{
+		{Name: "time", Type: arrow.FixedWidthTypes.Timestamp_s},
+		{Name: "time_shift", Type: arrow.PrimitiveTypes.Int64},
+	}
+	for _, name := range s.tags {
+		fields = append(fields, arrow.Field{Name: name, Type: arrow.BinaryTypes.String})
+	}
+	for _, name := range s.values {
+		fields = append(fields, arrow.Field{Name: name, Type: arrow.PrimitiveTypes.Float64, Nullable: true})
+	}
+	schema := arrow.NewSchema(fields, nil)
+	return &arrowExportWriter{
+		w: ipc.NewWriter(w, ipc.WithSchema(schema)),
+		b: array.NewRecordBuilder(memory.DefaultAllocator, schema),
+	}
+}
+
+func (w *arrowExportWriter) writeBatch(b *exportBatch) error {
+	t := w.b.Field(0).(*array.TimestampBuilder)
+	for _, v := range b.time {
+		t.Append(arrow.Timestamp(v))
+	}
+	w.b.Field(1).(*array.Int64Builder).AppendValues(b.timeShift, nil)
+	for i, tags := range b.tags {
+		w.b.Field(2+i).(*array.StringBuilder).AppendValues(tags, nil)
+	}
+	for i, values := range b.values {
+		f := w.b.Field(2 + len(b.tags) + i).(*array.Float64Builder)
+		for _, v := range values {
+			if math.IsNaN(v) {
+				f.AppendNull()
+			} else {
+				f.Append(v)
+			}
+		}
+	}
+	rec := w.b.NewRecord()
+	defer rec.Release()
+	return w.w.Write(rec)
+}
+
+func (w *arrowExportWriter) close() error {
+	w.b.Release()
+	return w.w.Close()
+}
+
+type parquetExportWriter struct {
+	w      *parquet.Writer
+	millis []int64
+}
+
+func newParquetExportWriter(w io.Writer, s exportSchema) (*parquetExportWriter, error) {
+	columns := []parquet.Column{
+		{Name: "time", Type: parquet.TimestampMillis},
+		{Name: "time_shift", Type: parquet.Int64},
+	}
+	for _, name := range s.tags {
+		columns = append(columns, parquet.Column{Name: name, Type: parquet.String})
+	}
+	for _, name := range s.values {
+		columns = append(columns, parquet.Column{Name: name, Type: parquet.Double})
+	}
+	pw, err := parquet.NewWriter(w, columns)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetExportWriter{w: pw}, nil
+}
+
+func (w *parquetExportWriter) writeBatch(b *exportBatch) error {
+	w.millis = w.millis[:0]
+	for _, t := range b.time {
+		w.millis = append(w.millis, t*1000)
+	}
+	values := make([]interface{}, 0, 2+len(b.tags)+len(b.values))
+	values = append(values, w.millis, b.timeShift)
+	for _, v := range b.tags {
+		values = append(values, v)
+	}
+	for _, v := range b.values {
+		values = append(values, v)
+	}
+	return w.w.WriteRowGroup(values)
+}
+
+func (w *parquetExportWriter) close() error {
+	return w.w.Close()
+}
+
+// writes response headers, response code is reported before streaming starts
+func startExport(w http.ResponseWriter, metric string, dataFormat string, es *endpointStat) {
+	es.reportServiceTime(http.StatusOK, nil)
+	contentType := "application/vnd.apache.arrow.stream"
+	if dataFormat == dataFormatParquet {
+		contentType = "application/vnd.apache.parquet"
+	}
+	w.Header().Set(
+		"Content-Disposition",
+		fmt.Sprintf("attachment; filename=%s-%s.%s", strings.ReplaceAll(metric, format.NamespaceSeparator, "_"), time.Now().Format("2006-01-02"), dataFormat),
+	)
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+}
+
+func flushExport(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// response is fully built before export starts, batches only limit memory used by format writers
+func exportSeries(w http.ResponseWriter, resp *SeriesResponse, metric string, dataFormat string, es *endpointStat) {
+	// series with the same tags and time shift become rows of the same values
+	type group struct {
+		timeShift int64
+		tags      map[string]SeriesMetaTag
+		data      []*[]float64
+	}
+	var groups []*group
+	groupByKey := map[string]*group{}
+	tagKeySet := map[string]bool{}
+	var whats []string
+	whatIndex := map[string]int{}
+	for i, meta := range resp.Series.SeriesMeta {
+		if _, ok := whatIndex[meta.What]; !ok {
+			whatIndex[meta.What] = len(whats)
+			whats = append(whats, meta.What)
+		}
+		keys := make([]string, 0, len(meta.Tags))
+		for k, v := range meta.Tags {
+			tagKeySet[k] = true
+			keys = append(keys, k+"="+v.Value)
+		}
+		sort.Strings(keys)
+		key := fmt.Sprint(meta.TimeShift, keys)
+		g := groupByKey[key]
+		if g == nil {
+			g = &group{timeShift: meta.TimeShift, tags: meta.Tags}
+			groupByKey[key] = g
+			groups = append(groups, g)
+		}
+		for len(g.data) < len(whats) {
+			g.data = append(g.data, nil)
+		}
+		g.data[whatIndex[meta.What]] = resp.Series.SeriesData[i]
+	}
+	tagKeys := make([]string, 0, len(tagKeySet))
+	for k := range tagKeySet {
+		tagKeys = append(tagKeys, k)
+	}
+	s := newExportSchema(resp.MetricMeta, tagKeys, whats)
+	startExport(w, metric, dataFormat, es)
+	defer es.reportResponseTime(http.StatusOK)
+	out, err := newExportWriter(w, dataFormat, s)
+	if err != nil {
+		log.Printf("[error] failed to start %s export: %v", dataFormat, err)
+		return
+	}
+	b := s.newBatch()
+	for _, g := range groups {
+		for ti, t := range resp.Series.Time {
+			empty := true
+			for _, d := range g.data {
+				if d != nil && !math.IsNaN((*d)[ti]) {
+					empty = false
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+			b.appendRow(&s, t, g.timeShift, g.tags)
+			for i := range b.values {
+				v := math.NaN()
+				if i < len(g.data) && g.data[i] != nil {
+					v = (*g.data[i])[ti]
+				}
+				b.values[i] = append(b.values[i], v)
+			}
+			if b.len() == exportBatchRows {
+				if err = out.writeBatch(b); err != nil {
+					log.Printf("[error] failed to write %s export: %v", dataFormat, err)
+					return
+				}
+				flushExport(w)
+				b.reset()
+			}
+		}
+	}
+	if b.len() != 0 {
+		err = out.writeBatch(b)
+	}
+	if err == nil {
+		err = out.close()
+	}
+	if err != nil {
+		log.Printf("[error] failed to write %s export: %v", dataFormat, err)
+	}
+}
+
+// table is read page by page, each page is written as soon as it is received
+func (h *Handler) exportTable(ctx context.Context, w http.ResponseWriter, req seriesRequest, dataFormat string, es *endpointStat) {
+	resp, _, err := h.handleGetTable(ctx, req.ai, false, req)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, req.ai.user, es)
+		return
+	}
+	// schema must not depend on the first page, rows carry exactly the grouped by tags
+	var tagKeys []string
+	for i := 0; i < format.MaxTags; i++ {
+		if containsString(req.by, format.TagID(i)) {
+			tagKeys = append(tagKeys, format.TagIDLegacy(i))
+		}
+	}
+	if containsString(req.by, format.StringTopTagID) {
+		tagKeys = append(tagKeys, format.LegacyStringTopTagID)
+	}
+	whats := make([]string, 0, len(resp.What))
+	for _, what := range resp.What {
+		whats = append(whats, what.Name)
+	}
+	s := newExportSchema(h.metricsStorage.GetMetaMetricByName(req.metricWithNamespace), tagKeys, whats)
+	startExport(w, req.metricWithNamespace, dataFormat, es)
+	defer es.reportResponseTime(http.StatusOK)
+	out, err := newExportWriter(w, dataFormat, s)
+	if err != nil {
+		log.Printf("[error] failed to start %s export: %v", dataFormat, err)
+		return
+	}
+	b := s.newBatch()
+	for {
+		for _, row := range resp.Rows {
+			b.appendRow(&s, row.Time, 0, row.Tags)
+			for i := range b.values {
+				v := math.NaN()
+				if i < len(row.Data) {
+					v = row.Data[i]
+				}
+				b.values[i] = append(b.values[i], v)
+			}
+		}
+		if b.len() != 0 {
+			if err = out.writeBatch(b); err != nil {
+				break
+			}
+			flushExport(w)
+			b.reset()
+		}
+		if !resp.More || len(resp.Rows) == 0 {
+			err = out.close()
+			break
+		}
+		req.fromRow = resp.Rows[len(resp.Rows)-1].rowRepr
+		if resp, _, err = h.handleGetTable(ctx, req.ai, false, req); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("[error] failed to write %s export: %v", dataFormat, err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"bytes"
+	"math"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/util/parquet"
+)
+
+func testExportSeriesResponse() *SeriesResponse {
+	nan := math.NaN()
+	return &SeriesResponse{
+		Series: querySeries{
+			Time: []int64{60, 120, 180},
+			SeriesMeta: []QuerySeriesMetaV2{
+				{Tags: map[string]SeriesMetaTag{"key1": {Value: "production"}}, What: "count"},
+				{Tags: map[string]SeriesMetaTag{"key1": {Value: "production"}}, What: "avg"},
+				{Tags: map[string]SeriesMetaTag{"key1": {Value: "staging"}}, What: "count"},
+			},
+			SeriesData: []*[]float64{
+				{1, 2, nan},
+				{10, nan, nan},
+				{nan, 3, nan},
+			},
+		},
+	}
+}
+
+func TestExportSeriesArrow(t *testing.T) {
+	w := httptest.NewRecorder()
+	exportSeries(w, testExportSeriesResponse(), "ns:metric", dataFormatArrow, newEndpointStatHTTP(EndpointQuery, "GET", 0, dataFormatArrow, ""))
+	require.Equal(t, "application/vnd.apache.arrow.stream", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), "ns_metric-")
+
+	r, err := ipc.NewReader(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	defer r.Release()
+	var names []string
+	for _, f := range r.Schema().Fields() {
+		names = append(names, f.Name)
+	}
+	require.Equal(t, []string{"time", "time_shift", "key1", "count", "avg"}, names)
+	require.True(t, r.Next())
+	rec := r.Record()
+	require.Equal(t, int64(3), rec.NumRows()) // row at 180 has no values
+	require.Equal(t, []arrow.Timestamp{60, 120, 120}, rec.Column(0).(*array.Timestamp).TimestampValues())
+	tags := rec.Column(2).(*array.String)
+	require.Equal(t, "production", tags.Value(0))
+	require.Equal(t, "staging", tags.Value(2))
+	count := rec.Column(3).(*array.Float64)
+	require.Equal(t, []float64{1, 2, 3}, count.Float64Values())
+	avg := rec.Column(4).(*array.Float64)
+	require.Equal(t, 10.0, avg.Value(0))
+	require.True(t, avg.IsNull(1))
+	require.True(t, avg.IsNull(2))
+	require.False(t, r.Next())
+}
+
+func TestExportSeriesParquet(t *testing.T) {
+	w := httptest.NewRecorder()
+	exportSeries(w, testExportSeriesResponse(), "metric", dataFormatParquet, newEndpointStatHTTP(EndpointQuery, "GET", 0, dataFormatParquet, ""))
+	require.Equal(t, "application/vnd.apache.parquet", w.Header().Get("Content-Type"))
+	b := w.Body.Bytes()
+	require.Equal(t, "PAR1", string(b[:4]))
+	require.Equal(t, "PAR1", string(b[len(b)-4:]))
+
+	// row at 180 has no values, writer output is checked against golden file by its own tests
+	var want bytes.Buffer
+	pw, err := parquet.NewWriter(&want, []parquet.Column{
+		{Name: "time", Type: parquet.TimestampMillis},
+		{Name: "time_shift", Type: parquet.Int64},
+		{Name: "key1", Type: parquet.String},
+		{Name: "count", Type: parquet.Double},
+		{Name: "avg", Type: parquet.Double},
+	})
+	require.NoError(t, err)
+	require.NoError(t, pw.WriteRowGroup([]interface{}{
+		[]int64{60000, 120000, 120000},
+		[]int64{0, 0, 0},
+		[]string{"production", "production", "staging"},
+		[]float64{1, 2, 3},
+		[]float64{10, math.NaN(), math.NaN()},
+	}))
+	require.NoError(t, pw.Close())
+	require.Equal(t, want.Bytes(), b)
+}
@@ -0,0 +1,450 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Grafana dashboard is converted into native one, every query of panel using StatsHouse datasource
+// or PromQL becomes separate plot, rows become plot groups. Other panels are skipped with warning.
+// Dashboard is written both as URL search params and as plot objects, for new and old UI.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mailru/easyjson/jlexer"
+)
+
+const (
+	grafanaStatsHouseDatasource = "vk-statshouse" // plugin ID, see grafana-plugin-ui
+	grafanaPrometheusDatasource = "prometheus"
+
+	grafanaDefaultNumSeries = 5 // same as plugin
+)
+
+type (
+	grafanaDashboard struct {
+		Title       string         `json:"title"`
+		Description string         `json:"description"`
+		Panels      []grafanaPanel `json:"panels"`
+		Time        struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"time"`
+	}
+
+	grafanaPanel struct {
+		Type        string          `json:"type"`
+		Title       string          `json:"title"`
+		Description string          `json:"description"`
+		Datasource  json.RawMessage `json:"datasource"` // name in old versions, reference object in new ones
+		Targets     []grafanaTarget `json:"targets"`
+		Panels      []grafanaPanel  `json:"panels"` // of collapsed row
+	}
+
+	grafanaTarget struct {
+		RefID      string          `json:"refId"`
+		Hide       bool            `json:"hide"`
+		Datasource json.RawMessage `json:"datasource"`
+		Expr       string          `json:"expr"` // Prometheus datasource
+		// StatsHouse datasource, see queryModel in internal/plugin
+		MetricName string   `json:"metricName"`
+		Function   string   `json:"func"`
+		What       []string `json:"what"`
+		Keys       map[string]struct {
+			Values  []string `json:"values"`
+			GroupBy bool     `json:"groupBy"`
+			NotIn   bool     `json:"notIn"`
+		} `json:"keys"`
+		TopN   int64   `json:"topN"`
+		Shifts []int64 `json:"shifts"`
+		Mode   string  `json:"mode"`
+		URL    string  `json:"url"`
+		Alias  string  `json:"alias"`
+	}
+
+	// plot as stored by old UI
+	grafanaPlot struct {
+		MetricName        string              `json:"metricName"`
+		CustomName        string              `json:"customName"`
+		CustomDescription string              `json:"customDescription"`
+		What              []string            `json:"what"`
+		CustomAgg         int                 `json:"customAgg"`
+		GroupBy           []string            `json:"groupBy"`
+		FilterIn          map[string][]string `json:"filterIn"`
+		FilterNotIn       map[string][]string `json:"filterNotIn"`
+		NumSeries         int64               `json:"numSeries"`
+		UseV2             bool                `json:"useV2"`
+		YLock             struct {
+			Min float64 `json:"min"`
+			Max float64 `json:"max"`
+		} `json:"yLock"`
+		MaxHost     bool     `json:"maxHost"`
+		PromQL      string   `json:"promQL"`
+		Type        int      `json:"type"`
+		Events      []int    `json:"events"`
+		EventsBy    []string `json:"eventsBy"`
+		EventsHide  []string `json:"eventsHide"`
+		TotalLine   bool     `json:"totalLine"`
+		FilledGraph bool     `json:"filledGraph"`
+		timeShifts  []int64
+	}
+
+	// group as stored by old UI
+	grafanaGroup struct {
+		Name        string `json:"name"`
+		Show        bool   `json:"show"`
+		Count       int    `json:"count"`
+		Size        string `json:"size"`
+		Description string `json:"description"`
+	}
+
+	// raw request body, parsed with encoding/json because of polymorphic fields
+	grafanaDashboardJSON []byte
+)
+
+func (d *grafanaDashboardJSON) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*d = append((*d)[:0], l.Raw()...)
+}
+
+func grafanaDatasourceType(raw json.RawMessage) string {
+	var ref struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(raw, &ref) == nil {
+		return ref.Type
+	}
+	return "" // datasource name or variable, type is unknown
+}
+
+// tag IDs are stored by UI without "key" prefix
+func grafanaTagID(tagID string) string {
+	return strings.Replace(strings.Replace(tagID, "skey", "_s", 1), "key", "", 1)
+}
+
+func newGrafanaPlot(name string, description string) grafanaPlot {
+	return grafanaPlot{
+		CustomName:        name,
+		CustomDescription: description,
+		What:              []string{},
+		GroupBy:           []string{},
+		FilterIn:          map[string][]string{},
+		FilterNotIn:       map[string][]string{},
+		NumSeries:         grafanaDefaultNumSeries,
+		UseV2:             true,
+		Events:            []int{},
+		EventsBy:          []string{},
+		EventsHide:        []string{},
+		FilledGraph:       true,
+	}
+}
+
+func (p *grafanaPlot) addFilter(tagID string, value string, notIn bool) {
+	if notIn {
+		p.FilterNotIn[tagID] = append(p.FilterNotIn[tagID], value)
+	} else {
+		p.FilterIn[tagID] = append(p.FilterIn[tagID], value)
+	}
+}
+
+func (p *grafanaPlot) setStatsHouseQuery(t grafanaTarget) error {
+	if t.Mode == "url" {
+		q, err := url.ParseQuery(t.URL)
+		if err != nil || q.Get(ParamMetric) == "" {
+			u, err := url.Parse(t.URL)
+			if err != nil {
+				return err
+			}
+			q = u.Query()
+		}
+		p.MetricName = q.Get(ParamMetric)
+		p.What = append(p.What, q[ParamQueryWhat]...)
+		for _, by := range q[ParamQueryBy] {
+			p.GroupBy = append(p.GroupBy, grafanaTagID(by))
+		}
+		for _, f := range q[ParamQueryFilter] {
+			if i := strings.IndexAny(f, queryFilterInSep+queryFilterNotInSep); i > 0 {
+				p.addFilter(grafanaTagID(f[:i]), f[i+1:], f[i:i+1] == queryFilterNotInSep)
+			}
+		}
+		if n, err := strconv.ParseInt(q.Get(ParamNumResults), 10, 64); err == nil {
+			p.NumSeries = n
+		}
+		for _, ts := range q[ParamTimeShift] {
+			if shift, err := strconv.ParseInt(ts, 10, 64); err == nil {
+				p.timeShifts = append(p.timeShifts, shift)
+			}
+		}
+	} else {
+		p.MetricName = t.MetricName
+		p.What = append(p.What, t.What...)
+		if len(p.What) == 0 && t.Function != "" {
+			p.What = append(p.What, t.Function)
+		}
+		tagIDs := make([]string, 0, len(t.Keys))
+		for tagID := range t.Keys {
+			tagIDs = append(tagIDs, tagID)
+		}
+		sort.Strings(tagIDs)
+		for _, tagID := range tagIDs {
+			key := t.Keys[tagID]
+			if key.GroupBy {
+				p.GroupBy = append(p.GroupBy, grafanaTagID(tagID))
+			}
+			for _, v := range key.Values {
+				p.addFilter(grafanaTagID(tagID), v, key.NotIn)
+			}
+		}
+		if t.TopN > 0 {
+			p.NumSeries = t.TopN
+		}
+		p.timeShifts = t.Shifts
+	}
+	if p.MetricName == "" {
+		return fmt.Errorf("metric not set")
+	}
+	if len(p.What) == 0 {
+		p.What = append(p.What, ParamQueryFnCountNorm)
+	}
+	return nil
+}
+
+// "now-6h" is converted into relative -21600 seconds
+func grafanaRelativeTime(s string) (int64, bool) {
+	if s == "now" {
+		return 0, true
+	}
+	if !strings.HasPrefix(s, "now-") {
+		return 0, false
+	}
+	s = s[len("now-"):]
+	var mul time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"):
+		mul = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		mul = 7 * 24 * time.Hour
+	case strings.HasSuffix(s, "M"):
+		mul = 30 * 24 * time.Hour
+	case strings.HasSuffix(s, "y"):
+		mul = 365 * 24 * time.Hour
+	}
+	if mul != 0 {
+		n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+		return -n * int64(mul/time.Second), err == nil
+	}
+	d, err := time.ParseDuration(s)
+	return -int64(d / time.Second), err == nil
+}
+
+func convertGrafanaDashboard(data []byte) (DashboardMetaInfo, []string, error) {
+	var wrapper struct {
+		Dashboard *grafanaDashboard `json:"dashboard"` // dashboard API response
+	}
+	var d grafanaDashboard
+	if err := json.Unmarshal(data, &wrapper); err == nil && wrapper.Dashboard != nil {
+		d = *wrapper.Dashboard
+	} else if err = json.Unmarshal(data, &d); err != nil {
+		return DashboardMetaInfo{}, nil, httpErr(http.StatusBadRequest, fmt.Errorf("invalid Grafana dashboard: %w", err))
+	}
+	if d.Title == "" {
+		return DashboardMetaInfo{}, nil, httpErr(http.StatusBadRequest, fmt.Errorf("Grafana dashboard has no title"))
+	}
+	var (
+		plots    []grafanaPlot
+		groups   []grafanaGroup
+		warnings []string
+	)
+	addPanel := func(panel grafanaPanel) {
+		panelDatasource := grafanaDatasourceType(panel.Datasource)
+		for _, t := range panel.Targets {
+			if t.Hide {
+				continue
+			}
+			datasource := grafanaDatasourceType(t.Datasource)
+			if datasource == "" {
+				datasource = panelDatasource
+			}
+			if datasource == "" { // guess by query fields
+				switch {
+				case t.Expr != "":
+					datasource = grafanaPrometheusDatasource
+				case t.MetricName != "" || t.URL != "":
+					datasource = grafanaStatsHouseDatasource
+				}
+			}
+			name := panel.Title
+			if t.Alias != "" {
+				name = t.Alias
+			} else if len(panel.Targets) > 1 && t.RefID != "" {
+				name = panel.Title + " " + t.RefID
+			}
+			p := newGrafanaPlot(name, panel.Description)
+			switch datasource {
+			case grafanaStatsHouseDatasource:
+				if err := p.setStatsHouseQuery(t); err != nil {
+					warnings = append(warnings, fmt.Sprintf("panel %q query %s skipped: %v", panel.Title, t.RefID, err))
+					continue
+				}
+			case grafanaPrometheusDatasource:
+				if t.Expr == "" {
+					continue
+				}
+				p.PromQL = t.Expr
+				if strings.Contains(t.Expr, "$") {
+					warnings = append(warnings, fmt.Sprintf("panel %q query %s uses Grafana variables, edit it manually", panel.Title, t.RefID))
+				}
+			default:
+				warnings = append(warnings, fmt.Sprintf("panel %q query %s skipped: unsupported datasource %q", panel.Title, t.RefID, datasource))
+				continue
+			}
+			plots = append(plots, p)
+			if len(groups) != 0 {
+				groups[len(groups)-1].Count++
+			}
+		}
+		if len(panel.Targets) == 0 {
+			warnings = append(warnings, fmt.Sprintf("panel %q of type %q skipped", panel.Title, panel.Type))
+		}
+	}
+	for _, panel := range d.Panels {
+		if panel.Type == "row" && len(groups) == 0 && len(plots) != 0 {
+			// panels before first row
+			groups = append(groups, grafanaGroup{Show: true, Size: "2", Count: len(plots)})
+		}
+		if panel.Type != "row" {
+			addPanel(panel)
+			continue
+		}
+		groups = append(groups, grafanaGroup{Name: panel.Title, Show: len(panel.Panels) == 0, Size: "2"})
+		for _, p := range panel.Panels {
+			addPanel(p)
+		}
+	}
+	if len(plots) == 0 {
+		return DashboardMetaInfo{}, warnings, httpErr(http.StatusBadRequest, fmt.Errorf("Grafana dashboard %q has no supported panels", d.Title))
+	}
+
+	var params [][2]string
+	legacy := map[string]interface{}{
+		"plots": plots,
+		"dashboard": map[string]interface{}{
+			"name":        d.Title,
+			"description": d.Description,
+			"groupInfo":   groups,
+		},
+	}
+	if d.Time.From != "" {
+		from, okFrom := grafanaRelativeTime(d.Time.From)
+		to, okTo := grafanaRelativeTime(d.Time.To)
+		if okFrom && okTo && to == 0 {
+			params = append(params, [2]string{ParamFromTime, strconv.FormatInt(from, 10)})
+			legacy["timeRange"] = map[string]interface{}{"to": 0, "from": from}
+		} else {
+			warnings = append(warnings, fmt.Sprintf("time range %s..%s is not supported", d.Time.From, d.Time.To))
+		}
+	}
+	for i, p := range plots {
+		prefix := ""
+		if i != 0 {
+			prefix = "t" + strconv.Itoa(i) + "."
+		}
+		if p.PromQL != "" {
+			params = append(params, [2]string{prefix + paramPromQuery, p.PromQL})
+		} else {
+			params = append(params, [2]string{prefix + ParamMetric, p.MetricName})
+		}
+		if p.CustomName != "" {
+			params = append(params, [2]string{prefix + "cn", p.CustomName})
+		}
+		if p.CustomDescription != "" {
+			params = append(params, [2]string{prefix + "cd", p.CustomDescription})
+		}
+		for _, w := range p.What {
+			params = append(params, [2]string{prefix + ParamQueryWhat, w})
+		}
+		for _, by := range p.GroupBy {
+			params = append(params, [2]string{prefix + ParamQueryBy, by})
+		}
+		for _, f := range []struct {
+			filter map[string][]string
+			sep    string
+		}{{p.FilterIn, queryFilterInSep}, {p.FilterNotIn, queryFilterNotInSep}} {
+			tagIDs := make([]string, 0, len(f.filter))
+			for tagID := range f.filter {
+				tagIDs = append(tagIDs, tagID)
+			}
+			sort.Strings(tagIDs)
+			for _, tagID := range tagIDs {
+				for _, v := range f.filter[tagID] {
+					params = append(params, [2]string{prefix + ParamQueryFilter, tagID + f.sep + v})
+				}
+			}
+		}
+		params = append(params, [2]string{prefix + ParamNumResults, strconv.FormatInt(p.NumSeries, 10)})
+		for _, ts := range p.timeShifts {
+			params = append(params, [2]string{prefix + "lts", strconv.FormatInt(ts, 10)})
+		}
+	}
+	for i, g := range groups {
+		prefix := "g" + strconv.Itoa(i) + "."
+		params = append(params, [2]string{prefix + "t", g.Name}, [2]string{prefix + "n", strconv.Itoa(g.Count)})
+		if !g.Show {
+			params = append(params, [2]string{prefix + "v", "0"})
+		}
+	}
+
+	// old UI reads plain JSON objects, round trip converts structs into them
+	var jsonData map[string]interface{}
+	b, err := json.Marshal(legacy)
+	if err == nil {
+		err = json.Unmarshal(b, &jsonData)
+	}
+	if err != nil {
+		return DashboardMetaInfo{}, warnings, err
+	}
+	setDashboardSearchParams(jsonData, params)
+	return DashboardMetaInfo{
+		Name:        d.Title,
+		Description: d.Description,
+		JSONData:    jsonData,
+	}, warnings, nil
+}
+
+func (h *Handler) handleImportGrafanaDashboard(ctx context.Context, ai accessInfo, data []byte) (*DashboardImportResp, error) {
+	dash, warnings, err := convertGrafanaDashboard(data)
+	if err != nil {
+		return nil, err
+	}
+	info, err := h.handlePostDashboard(ctx, ai, dash, true, false)
+	if err != nil {
+		return nil, err
+	}
+	return &DashboardImportResp{
+		Dashboard:  info.Dashboard,
+		Metrics:    []string{},
+		Groups:     []string{},
+		Namespaces: []string{},
+		Warnings:   warnings,
+	}, nil
+}
+
+func (h *Handler) HandlePostDashboardImportGrafana(w http.ResponseWriter, r *http.Request) {
+	var data grafanaDashboardJSON
+	handlePostEntity(h, w, r, EndpointDashboardImportGrafana, &data, func(ctx context.Context, ai accessInfo, entity *grafanaDashboardJSON, _ bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handleImportGrafanaDashboard(ctx, ai, *entity)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.Dashboard.Version, nil
+	})
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGrafanaDashboard = `{
+  "title": "Service",
+  "description": "imported",
+  "time": {"from": "now-6h", "to": "now"},
+  "panels": [
+    {"type": "text", "title": "Readme"},
+    {
+      "type": "timeseries",
+      "title": "Requests",
+      "datasource": {"type": "vk-statshouse", "uid": "sh"},
+      "targets": [{
+        "refId": "A",
+        "metricName": "api_requests",
+        "what": ["count_norm"],
+        "keys": {"key1": {"values": ["production"], "groupBy": false}, "key2": {"values": [], "groupBy": true}},
+        "topN": 10
+      }]
+    },
+    {"type": "row", "title": "Details", "collapsed": true, "panels": [
+      {
+        "type": "timeseries",
+        "title": "Latency",
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "targets": [{"refId": "A", "expr": "histogram_quantile(0.99, api_latency_bucket)"}]
+      },
+      {
+        "type": "timeseries",
+        "title": "Errors",
+        "targets": [{"refId": "A", "mode": "url", "url": "s=api_errors&qw=count&qf=skey~ok&n=3"}]
+      }
+    ]},
+    {
+      "type": "timeseries",
+      "title": "Elastic",
+      "datasource": {"type": "elasticsearch", "uid": "es"},
+      "targets": [{"refId": "A", "query": "*"}]
+    }
+  ]
+}`
+
+func TestConvertGrafanaDashboard(t *testing.T) {
+	dash, warnings, err := convertGrafanaDashboard([]byte(testGrafanaDashboard))
+	require.NoError(t, err)
+	require.Equal(t, "Service", dash.Name)
+	require.Equal(t, "imported", dash.Description)
+	require.Len(t, warnings, 2) // text panel and Elasticsearch datasource
+	require.Equal(t, [][2]string{
+		{"f", "-21600"},
+		{"s", "api_requests"},
+		{"cn", "Requests"},
+		{"qw", "count_norm"},
+		{"qb", "2"},
+		{"qf", "1-production"},
+		{"n", "10"},
+		{"t1.q", "histogram_quantile(0.99, api_latency_bucket)"},
+		{"t1.cn", "Latency"},
+		{"t1.n", "5"},
+		{"t2.s", "api_errors"},
+		{"t2.cn", "Errors"},
+		{"t2.qw", "count"},
+		{"t2.qf", "_s~ok"},
+		{"t2.n", "3"},
+		{"g0.t", ""},
+		{"g0.n", "1"},
+		{"g1.t", "Details"},
+		{"g1.n", "2"},
+		{"g1.v", "0"},
+	}, dashboardSearchParams(dash.JSONData))
+
+	plots, ok := dash.JSONData["plots"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, plots, 3)
+	require.Equal(t, "api_requests", plots[0].(map[string]interface{})["metricName"])
+	require.Equal(t, []string{"api_errors", "api_latency_bucket", "api_requests"}, dashboardMetricNames(dash.JSONData))
+
+	_, _, err = convertGrafanaDashboard([]byte(`{"dashboard": {"title": "Empty", "panels": [{"type": "text"}]}}`))
+	require.Error(t, err)
+}
+
+func TestGrafanaRelativeTime(t *testing.T) {
+	for s, want := range map[string]int64{"now": 0, "now-15m": -900, "now-2d": -2 * 86400, "now-1w": -7 * 86400} {
+		got, ok := grafanaRelativeTime(s)
+		require.True(t, ok, s)
+		require.Equal(t, want, got, s)
+	}
+	for _, s := range []string{"now/d", "2024-01-01T00:00:00Z", "now-1h/h"} {
+		_, ok := grafanaRelativeTime(s)
+		require.False(t, ok, s)
+	}
+}
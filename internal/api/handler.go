@@ -49,6 +49,7 @@ import (
 	"github.com/vkcom/statshouse/internal/pcache"
 	"github.com/vkcom/statshouse/internal/promql"
 	"github.com/vkcom/statshouse/internal/util"
+	"github.com/vkcom/statshouse/internal/vkgo/rpc"
 	"github.com/vkcom/statshouse/internal/vkgo/srvfunc"
 	"github.com/vkcom/statshouse/internal/vkgo/vkuth"
 
@@ -108,14 +109,17 @@ const (
 	paramDashboardID  = "id"
 	paramShowDisabled = "sd"
 	paramPriority     = "priority"
+	paramApply        = "apply"
 	paramYL, paramYH  = "yl", "yh" // Y scale range
 
-	Version1       = "1"
-	Version2       = "2"
-	dataFormatPNG  = "png"
-	dataFormatSVG  = "svg"
-	dataFormatText = "text"
-	dataFormatCSV  = "csv"
+	Version1          = "1"
+	Version2          = "2"
+	dataFormatPNG     = "png"
+	dataFormatSVG     = "svg"
+	dataFormatText    = "text"
+	dataFormatCSV     = "csv"
+	dataFormatArrow   = "arrow" // IPC stream
+	dataFormatParquet = "parquet"
 
 	defSeries     = 10
 	maxSeries     = 10_000
@@ -182,13 +186,18 @@ type (
 		pointFloatsPool       sync.Pool
 		cacheInvalidateTicker *time.Ticker
 		cacheInvalidateStop   chan chan struct{}
+		liveNotifier          *liveNotifier
+		liveQueries           *liveQueries
 		metadataLoader        *metajournal.MetricMetaLoader
+		rpcClient             *rpc.Client // for agents and aggregators
 		jwtHelper             *vkuth.JWTHelper
 		plotRenderSem         *semaphore.Weighted
 		plotTemplate          *ttemplate.Template
 		rUsage                syscall.Rusage // accessed without lock by first shard addBuiltIns
 		rmID                  int
 		promEngine            promql.Engine
+		rules                 *ruleManager // nil if neither alerting nor recording rules are enabled
+		alertRuleMatchers     *ruleMatcherCache
 	}
 
 	//easyjson:json
@@ -384,6 +393,7 @@ type (
 		ExcessPointLeft   bool                    `json:"excess_point_left"`
 		ExcessPointRight  bool                    `json:"excess_point_right"`
 		MetricMeta        *format.MetricMetaValue `json:"metric"`
+		Annotations       []format.Annotation     `json:"annotations,omitempty"`
 		immutable         bool
 	}
 
@@ -494,9 +504,16 @@ type (
 	}
 
 	metadata struct {
-		UserEmail string `json:"user_email"`
-		UserName  string `json:"user_name"`
-		UserRef   string `json:"user_ref"`
+		UserEmail string      `json:"user_email"`
+		UserName  string      `json:"user_name"`
+		UserRef   string      `json:"user_ref"`
+		Grants    []roleGrant `json:"grants,omitempty"` // namespace and group role changes
+	}
+
+	// role change, empty role means revoked
+	roleGrant struct {
+		User string `json:"user"`
+		Role string `json:"role"`
 	}
 
 	HistoryEvent struct {
@@ -533,6 +550,7 @@ func NewHandler(staticDir fs.FS, jsSettings JSSettings, showInvisible bool, chV1
 		indexTemplate:  tmpl,
 		indexSettings:  string(settings),
 		metadataLoader: metadataLoader,
+		rpcClient:      metadataClient.Client,
 		ch: map[string]*util.ClickHouse{
 			Version1: chV1,
 			Version2: chV2,
@@ -576,6 +594,9 @@ func NewHandler(staticDir fs.FS, jsSettings JSSettings, showInvisible bool, chV1
 		},
 		cacheInvalidateTicker: time.NewTicker(cacheInvalidateCheckInterval),
 		cacheInvalidateStop:   make(chan chan struct{}),
+		liveNotifier:          newLiveNotifier(),
+		liveQueries:           newLiveQueries(),
+		alertRuleMatchers:     newRuleMatcherCache(),
 		jwtHelper:             jwtHelper,
 		plotRenderSem:         semaphore.NewWeighted(maxConcurrentPlots),
 		plotTemplate:          ttemplate.Must(ttemplate.New("").Parse(gnuplotTemplate)),
@@ -626,12 +647,19 @@ func NewHandler(staticDir fs.FS, jsSettings JSSettings, showInvisible bool, chV1
 		writeActiveQuieries(chV2, "2")
 	})
 	h.promEngine = promql.NewEngine(h, h.location, h.utcOffset)
+	if h.alerting || h.recordingRules {
+		h.rules = newRuleManager(h)
+		go h.rules.run()
+	}
 	return h, nil
 }
 
 func (h *Handler) Close() error {
 	statshouse.StopRegularMeasurement(h.rmID)
 	h.cacheInvalidateTicker.Stop()
+	if h.rules != nil {
+		h.rules.close()
+	}
 
 	ch := make(chan struct{})
 	h.cacheInvalidateStop <- ch
@@ -738,6 +766,9 @@ SETTINGS
 			h.pointsCache.invalidate(times)
 		}
 	}
+	if len(todo) != 0 {
+		h.liveNotifier.notify()
+	}
 
 	return from, newSeen
 }
@@ -1033,7 +1064,14 @@ func (h *Handler) HandleStatic(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) parseAccessToken(r *http.Request, es *endpointStat) (accessInfo, error) {
-	ai, err := parseAccessToken(h.jwtHelper, vkuth.GetAccessToken(r), h.protectedMetricPrefixes, h.LocalMode, h.insecureMode)
+	var ai accessInfo
+	var err error
+	if token := vkuth.GetAccessToken(r); isAPIToken(token) {
+		ai, err = parseAPIToken(h.metricsStorage, token, h.protectedMetricPrefixes, time.Now())
+	} else {
+		ai, err = parseAccessToken(h.jwtHelper, token, h.protectedMetricPrefixes, h.LocalMode, h.insecureMode)
+		ai.roles = h.metricsStorage
+	}
 	if es != nil {
 		es.setAccessInfo(ai)
 	}
@@ -1171,6 +1209,10 @@ func handlePostEntity[T easyjson.Unmarshaler](h *Handler, w http.ResponseWriter,
 		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
 		return
 	}
+	if ai.readOnly {
+		respondJSON(w, nil, 0, 0, httpErr(http.StatusForbidden, fmt.Errorf("read-only API token %q", ai.apiToken)), h.verbose, ai.user, sl)
+		return
+	}
 	rd := &io.LimitedReader{
 		R: r.Body,
 		N: maxEntityHTTPBodySize,
@@ -1482,13 +1524,19 @@ func (h *Handler) handleGetDashboardList(ai accessInfo, showInvisible bool) (*Ge
 }
 
 func (h *Handler) handlePostDashboard(ctx context.Context, ai accessInfo, dash DashboardMetaInfo, create, delete bool) (*DashboardInfo, error) {
+	oldName := dash.Name
 	if !create {
 		if _, ok := format.BuiltinDashboardByID[dash.DashboardID]; ok {
 			return &DashboardInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("can't edit builtin dashboard %d", dash.DashboardID))
 		}
-		if h.metricsStorage.GetDashboardMeta(dash.DashboardID) == nil {
+		old := h.metricsStorage.GetDashboardMeta(dash.DashboardID)
+		if old == nil {
 			return &DashboardInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("dashboard %d not found", dash.DashboardID))
 		}
+		oldName = old.Name
+	}
+	if !ai.CanEditDashboard(oldName, dash.Name) {
+		return &DashboardInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit dashboard %q", oldName))
 	}
 	if dash.JSONData == nil {
 		dash.JSONData = map[string]interface{}{}
@@ -1559,17 +1607,28 @@ func (h *Handler) handleGetNamespaceList(ai accessInfo, showInvisible bool) (*Ge
 }
 
 func (h *Handler) handlePostNamespace(ctx context.Context, ai accessInfo, namespace format.NamespaceMeta, create bool) (*NamespaceInfo, error) {
-	if !ai.isAdmin() {
-		return nil, httpErr(http.StatusNotFound, fmt.Errorf("namespace %s not found", namespace.Name))
-	}
+	var old *format.NamespaceMeta
 	if !create {
-		if h.metricsStorage.GetNamespace(namespace.ID) == nil {
+		if old = h.metricsStorage.GetNamespace(namespace.ID); old == nil {
 			return &NamespaceInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("namespace %d not found", namespace.ID))
 		}
 	}
+	if !ai.isAdmin() {
+		// owners can only change roles
+		if old == nil || old.ID < 0 || !ai.canGrantRoles(old.Roles) ||
+			old.Name != namespace.Name || old.Weight != namespace.Weight || old.Disable != namespace.Disable ||
+			old.QuotaRowsPerSec != namespace.QuotaRowsPerSec || old.QuotaBytesPerSec != namespace.QuotaBytesPerSec ||
+			old.QuotaCardinalityPerHour != namespace.QuotaCardinalityPerHour {
+			return nil, httpErr(http.StatusNotFound, fmt.Errorf("namespace %s not found", namespace.Name))
+		}
+	}
 	var err error
 	if namespace.ID >= 0 {
-		namespace, err = h.metadataLoader.SaveNamespace(ctx, namespace, create, ai.toMetadata())
+		var oldRoles map[string]string
+		if old != nil {
+			oldRoles = old.Roles
+		}
+		namespace, err = h.metadataLoader.SaveNamespace(ctx, namespace, create, ai.toMetadataWithGrants(roleGrants(oldRoles, namespace.Roles)))
 	} else {
 		n := h.metricsStorage.GetNamespace(namespace.ID)
 		if n == nil {
@@ -1594,20 +1653,29 @@ func (h *Handler) handlePostNamespace(ctx context.Context, ai accessInfo, namesp
 }
 
 func (h *Handler) handlePostGroup(ctx context.Context, ai accessInfo, group format.MetricsGroup, create bool) (*MetricsGroupInfo, error) {
-	if !ai.isAdmin() {
-		return nil, httpErr(http.StatusNotFound, fmt.Errorf("group %s not found", group.Name))
-	}
+	var old *format.MetricsGroup
 	if !create {
-		if h.metricsStorage.GetGroup(group.ID) == nil {
+		if old = h.metricsStorage.GetGroup(group.ID); old == nil {
 			return &MetricsGroupInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("group %d not found", group.ID))
 		}
 	}
+	if !ai.isAdmin() {
+		// owners of group or its namespace can only change group roles
+		if old == nil || old.ID < 0 || !(ai.canGrantRoles(old.Roles) || old.Namespace != nil && ai.canGrantRoles(old.Namespace.Roles)) ||
+			old.Name != group.Name || old.NamespaceID != group.NamespaceID || old.Weight != group.Weight || old.Disable != group.Disable {
+			return nil, httpErr(http.StatusNotFound, fmt.Errorf("group %s not found", group.Name))
+		}
+	}
 	if !h.metricsStorage.CanAddOrChangeGroup(group.Name, group.ID) {
 		return &MetricsGroupInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("group name %s is not posible", group.Name))
 	}
 	var err error
 	if group.ID >= 0 {
-		group, err = h.metadataLoader.SaveMetricsGroup(ctx, group, create, ai.toMetadata())
+		var oldRoles map[string]string
+		if old != nil {
+			oldRoles = old.Roles
+		}
+		group, err = h.metadataLoader.SaveMetricsGroup(ctx, group, create, ai.toMetadataWithGrants(roleGrants(oldRoles, group.Roles)))
 	} else {
 		group, err = h.metadataLoader.SaveBuiltInGroup(ctx, group)
 	}
@@ -1638,7 +1706,15 @@ func (h *Handler) handlePostMetric(ctx context.Context, ai accessInfo, _ string,
 	if metric.PreKeyOnly && (metric.PreKeyFrom == 0 || metric.PreKeyTagID == "") {
 		return format.MetricMetaValue{}, httpErr(http.StatusBadRequest, fmt.Errorf("use prekey_only with non empty prekey_tag_id"))
 	}
+	if err = h.mapSampleKeepValues(&metric); err != nil {
+		return format.MetricMetaValue{}, err
+	}
 	if create {
+		if metric.State != format.MetricStateActive {
+			return format.MetricMetaValue{}, httpErr(http.StatusBadRequest, fmt.Errorf("metric must be created in active state"))
+		}
+		metric.PurgeTime = 0
+		metric.SetStateTime(nil, time.Now())
 		if !ai.CanEditMetric(true, metric, metric) {
 			return format.MetricMetaValue{}, httpErr(http.StatusForbidden, fmt.Errorf("can't create metric %q", metric.Name))
 		}
@@ -1656,6 +1732,14 @@ func (h *Handler) handlePostMetric(ctx context.Context, ai accessInfo, _ string,
 		if old == nil {
 			return format.MetricMetaValue{}, httpErr(http.StatusNotFound, fmt.Errorf("metric %q not found (id %d)", metric.Name, metric.MetricID))
 		}
+		if err = checkMetricStateChange(*old, metric); err != nil {
+			return format.MetricMetaValue{}, httpErr(http.StatusBadRequest, err)
+		}
+		if metric.OptionsVersion == 0 { // client is unaware of options, so they must not change with description
+			metric.CopyOptions(old)
+		}
+		metric.PurgeTime = old.PurgeTime // set by aggregator only
+		metric.SetStateTime(old, time.Now())
 		if !ai.CanEditMetric(false, *old, metric) {
 			return format.MetricMetaValue{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit metric %q", old.Name))
 		}
@@ -1669,6 +1753,46 @@ func (h *Handler) handlePostMetric(ctx context.Context, ai accessInfo, _ string,
 	return resp, nil
 }
 
+// agents and aggregators compare tag value IDs, so sampling policy values are mapped once when metric is saved
+func (h *Handler) mapSampleKeepValues(metric *format.MetricMetaValue) error {
+	metric.SampleKeepValueIDs = nil
+	if len(metric.SampleKeepValues) == 0 {
+		return nil
+	}
+	m := *metric // RestoreCachedInfo modifies tags
+	m.Tags = append([]format.MetricMetaTag(nil), metric.Tags...)
+	_ = m.RestoreCachedInfo() // all errors are reported when metric is saved
+	tag, ok := m.Name2Tag[metric.SampleKeepTagID]
+	if !ok {
+		return httpErr(http.StatusBadRequest, fmt.Errorf("invalid sample_keep_tag_id: %q", metric.SampleKeepTagID))
+	}
+	for _, v := range metric.SampleKeepValues {
+		id, err := h.getRichTagValueID(&tag, Version2, v)
+		if err != nil {
+			return httpErr(http.StatusBadRequest, fmt.Errorf("failed to map sample_keep_values %q (send some data with this value first): %w", v, err))
+		}
+		metric.SampleKeepValueIDs = append(metric.SampleKeepValueIDs, id)
+	}
+	return nil
+}
+
+// lifecycle goes through deprecated and disabled states before deletion, so users notice missing data before it is deleted
+func checkMetricStateChange(old format.MetricMetaValue, new_ format.MetricMetaValue) error {
+	if old.State == new_.State {
+		return nil
+	}
+	if !format.ValidMetricState(new_.State) {
+		return fmt.Errorf("invalid metric state %q", new_.State)
+	}
+	if old.State == format.MetricStateDeleted && old.PurgeTime != 0 {
+		return fmt.Errorf("metric %q data is already deleted", old.Name)
+	}
+	if new_.State == format.MetricStateDeleted && old.State != format.MetricStateDisabled {
+		return fmt.Errorf("metric %q must be disabled before deletion", old.Name)
+	}
+	return nil
+}
+
 func (h *Handler) HandleGetMetricTagValues(w http.ResponseWriter, r *http.Request) {
 	sl := newEndpointStatHTTP(EndpointMetricTagValues, r.Method, h.getMetricIDForStat(r.FormValue(ParamMetric)), "", r.FormValue(paramPriority))
 	ai, err := h.parseAccessToken(r, sl)
@@ -1902,6 +2026,10 @@ func (h *Handler) HandleGetTable(w http.ResponseWriter, r *http.Request) {
 	if req.numResults <= 0 || maxTableRowsPage < req.numResults {
 		req.numResults = maxTableRowsPage
 	}
+	if df := r.FormValue(paramDataFormat); isExportDataFormat(df) {
+		h.exportTable(ctx, w, req, df, sl)
+		return
+	}
 	respTable, immutable, err := h.handleGetTable(ctx, req.ai, true, req)
 	if h.verbose && err == nil {
 		log.Printf("[debug] handled query (%v rows) for %q in %v", len(respTable.Rows), req.ai.user, time.Since(sl.timestamp))
@@ -1933,8 +2061,11 @@ func (h *Handler) HandleSeriesQuery(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.FormValue(paramDataFormat) == dataFormatCSV:
 		exportCSV(w, h.buildSeriesResponse(s...), req.metricWithNamespace, sl)
+	case isExportDataFormat(r.FormValue(paramDataFormat)):
+		exportSeries(w, h.buildSeriesResponse(s...), req.metricWithNamespace, r.FormValue(paramDataFormat), sl)
 	default:
 		res := h.buildSeriesResponse(s...)
+		res.Annotations = h.getAnnotations(req.ai, res.MetricMeta, req.from.Unix(), req.to.Unix(), req.filterIn)
 		cache, cacheStale := queryClientCacheDuration(res.immutable)
 		respondJSON(w, res, cache, cacheStale, nil, h.verbose, req.ai.user, sl)
 	}
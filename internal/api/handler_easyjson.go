@@ -285,6 +285,29 @@ func easyjson888c126aDecodeGithubComVkcomStatshouseInternalApi2(in *jlexer.Lexer
 				}
 				easyjson888c126aDecodeGithubComVkcomStatshouseInternalFormat(in, out.MetricMeta)
 			}
+		case "annotations":
+			if in.IsNull() {
+				in.Skip()
+				out.Annotations = nil
+			} else {
+				in.Delim('[')
+				if out.Annotations == nil {
+					if !in.IsDelim(']') {
+						out.Annotations = make([]format.Annotation, 0, 0)
+					} else {
+						out.Annotations = []format.Annotation{}
+					}
+				} else {
+					out.Annotations = (out.Annotations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 format.Annotation
+					easyjson8b883f07DecodeGithubComVkcomStatshouseInternalFormat(in, &v9)
+					out.Annotations = append(out.Annotations, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -369,6 +392,20 @@ func easyjson888c126aEncodeGithubComVkcomStatshouseInternalApi2(out *jwriter.Wri
 			easyjson888c126aEncodeGithubComVkcomStatshouseInternalFormat(out, *in.MetricMeta)
 		}
 	}
+	if len(in.Annotations) != 0 {
+		const prefix string = ",\"annotations\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v10, v11 := range in.Annotations {
+				if v10 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8b883f07EncodeGithubComVkcomStatshouseInternalFormat(out, v11)
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 
@@ -483,6 +520,95 @@ func easyjson888c126aDecodeGithubComVkcomStatshouseInternalFormat(in *jlexer.Lex
 			out.MetricType = string(in.String())
 		case "fair_key_tag_id":
 			out.FairKeyTagID = string(in.String())
+		case "state":
+			out.State = string(in.String())
+		case "purge_time":
+			out.PurgeTime = uint32(in.Uint32())
+		case "state_time":
+			out.StateTime = uint32(in.Uint32())
+		case "sample_keep_tag_id":
+			out.SampleKeepTagID = string(in.String())
+		case "sample_keep_values":
+			if in.IsNull() {
+				in.Skip()
+				out.SampleKeepValues = nil
+			} else {
+				in.Delim('[')
+				if out.SampleKeepValues == nil {
+					if !in.IsDelim(']') {
+						out.SampleKeepValues = make([]string, 0, 4)
+					} else {
+						out.SampleKeepValues = []string{}
+					}
+				} else {
+					out.SampleKeepValues = (out.SampleKeepValues)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v901 string
+					v901 = string(in.String())
+					out.SampleKeepValues = append(out.SampleKeepValues, v901)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "sample_keep_value_ids":
+			if in.IsNull() {
+				in.Skip()
+				out.SampleKeepValueIDs = nil
+			} else {
+				in.Delim('[')
+				if out.SampleKeepValueIDs == nil {
+					if !in.IsDelim(']') {
+						out.SampleKeepValueIDs = make([]int32, 0, 16)
+					} else {
+						out.SampleKeepValueIDs = []int32{}
+					}
+				} else {
+					out.SampleKeepValueIDs = (out.SampleKeepValueIDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v902 int32
+					v902 = int32(in.Int32())
+					out.SampleKeepValueIDs = append(out.SampleKeepValueIDs, v902)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "sample_stratify_tag_id":
+			out.SampleStratifyTagID = string(in.String())
+		case "sample_stratify_min":
+			out.SampleStratifyMin = int(in.Int())
+		case "sample_min_budget":
+			out.SampleMinBudget = int64(in.Int64())
+		case "options_version":
+			out.OptionsVersion = int(in.Int())
+		case "round_sample_factors":
+			out.RoundSampleFactors = bool(in.Bool())
+		case "shard_unique_values":
+			out.ShardUniqueValues = bool(in.Bool())
+		case "histogram_buckets":
+			if in.IsNull() {
+				in.Skip()
+				out.HistogramBuckets = nil
+			} else {
+				in.Delim('[')
+				if out.HistogramBuckets == nil {
+					if !in.IsDelim(']') {
+						out.HistogramBuckets = make([]float32, 0, 16)
+					} else {
+						out.HistogramBuckets = []float32{}
+					}
+				} else {
+					out.HistogramBuckets = (out.HistogramBuckets)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v911 float32
+					v911 = float32(in.Float32())
+					out.HistogramBuckets = append(out.HistogramBuckets, v911)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -630,6 +756,98 @@ func easyjson888c126aEncodeGithubComVkcomStatshouseInternalFormat(out *jwriter.W
 		out.RawString(prefix)
 		out.String(string(in.FairKeyTagID))
 	}
+	if in.State != "" {
+		const prefix string = ",\"state\":"
+		out.RawString(prefix)
+		out.String(string(in.State))
+	}
+	if in.PurgeTime != 0 {
+		const prefix string = ",\"purge_time\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.PurgeTime))
+	}
+	if in.StateTime != 0 {
+		const prefix string = ",\"state_time\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.StateTime))
+	}
+	if in.SampleKeepTagID != "" {
+		const prefix string = ",\"sample_keep_tag_id\":"
+		out.RawString(prefix)
+		out.String(string(in.SampleKeepTagID))
+	}
+	if len(in.SampleKeepValues) != 0 {
+		const prefix string = ",\"sample_keep_values\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v903, v904 := range in.SampleKeepValues {
+				if v903 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v904))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.SampleKeepValueIDs) != 0 {
+		const prefix string = ",\"sample_keep_value_ids\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v905, v906 := range in.SampleKeepValueIDs {
+				if v905 > 0 {
+					out.RawByte(',')
+				}
+				out.Int32(int32(v906))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.SampleStratifyTagID != "" {
+		const prefix string = ",\"sample_stratify_tag_id\":"
+		out.RawString(prefix)
+		out.String(string(in.SampleStratifyTagID))
+	}
+	if in.SampleStratifyMin != 0 {
+		const prefix string = ",\"sample_stratify_min\":"
+		out.RawString(prefix)
+		out.Int(int(in.SampleStratifyMin))
+	}
+	if in.SampleMinBudget != 0 {
+		const prefix string = ",\"sample_min_budget\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.SampleMinBudget))
+	}
+	if in.OptionsVersion != 0 {
+		const prefix string = ",\"options_version\":"
+		out.RawString(prefix)
+		out.Int(int(in.OptionsVersion))
+	}
+	if in.RoundSampleFactors {
+		const prefix string = ",\"round_sample_factors\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.RoundSampleFactors))
+	}
+	if in.ShardUniqueValues {
+		const prefix string = ",\"shard_unique_values\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.ShardUniqueValues))
+	}
+	if len(in.HistogramBuckets) != 0 {
+		const prefix string = ",\"histogram_buckets\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v912, v913 := range in.HistogramBuckets {
+				if v912 > 0 {
+					out.RawByte(',')
+				}
+				out.Float32(float32(v913))
+			}
+			out.RawByte(']')
+		}
+	}
 	out.RawByte('}')
 }
 func easyjson888c126aDecodeGithubComVkcomStatshouseInternalFormat1(in *jlexer.Lexer, out *format.MetricMetaTag) {
@@ -1237,6 +1455,32 @@ func easyjson888c126aDecodeGithubComVkcomStatshouseInternalFormat2(in *jlexer.Le
 			out.Weight = float64(in.Float64())
 		case "disable":
 			out.Disable = bool(in.Bool())
+		case "roles":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Roles = make(map[string]string)
+				} else {
+					out.Roles = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v108 string
+					v108 = string(in.String())
+					(out.Roles)[key] = v108
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "quota_rows_per_sec":
+			out.QuotaRowsPerSec = int64(in.Int64())
+		case "quota_bytes_per_sec":
+			out.QuotaBytesPerSec = int64(in.Int64())
+		case "quota_cardinality_per_hour":
+			out.QuotaCardinalityPerHour = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -1286,6 +1530,40 @@ func easyjson888c126aEncodeGithubComVkcomStatshouseInternalFormat2(out *jwriter.
 		out.RawString(prefix)
 		out.Bool(bool(in.Disable))
 	}
+	if len(in.Roles) != 0 {
+		const prefix string = ",\"roles\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v109First := true
+			for v109Name, v109Value := range in.Roles {
+				if v109First {
+					v109First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v109Name))
+				out.RawByte(':')
+				out.String(string(v109Value))
+			}
+			out.RawByte('}')
+		}
+	}
+	if in.QuotaRowsPerSec != 0 {
+		const prefix string = ",\"quota_rows_per_sec\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.QuotaRowsPerSec))
+	}
+	if in.QuotaBytesPerSec != 0 {
+		const prefix string = ",\"quota_bytes_per_sec\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.QuotaBytesPerSec))
+	}
+	if in.QuotaCardinalityPerHour != 0 {
+		const prefix string = ",\"quota_cardinality_per_hour\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.QuotaCardinalityPerHour))
+	}
 	out.RawByte('}')
 }
 func easyjson888c126aDecodeGithubComVkcomStatshouseInternalApi6(in *jlexer.Lexer, out *MetricsGroupInfo) {
@@ -1412,6 +1690,26 @@ func easyjson888c126aDecodeGithubComVkcomStatshouseInternalFormat3(in *jlexer.Le
 			out.Weight = float64(in.Float64())
 		case "disable":
 			out.Disable = bool(in.Bool())
+		case "roles":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Roles = make(map[string]string)
+				} else {
+					out.Roles = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v110 string
+					v110 = string(in.String())
+					(out.Roles)[key] = v110
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -1461,6 +1759,25 @@ func easyjson888c126aEncodeGithubComVkcomStatshouseInternalFormat3(out *jwriter.
 		out.RawString(prefix)
 		out.Bool(bool(in.Disable))
 	}
+	if len(in.Roles) != 0 {
+		const prefix string = ",\"roles\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v111First := true
+			for v111Name, v111Value := range in.Roles {
+				if v111First {
+					v111First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v111Name))
+				out.RawByte(':')
+				out.String(string(v111Value))
+			}
+			out.RawByte('}')
+		}
+	}
 	out.RawByte('}')
 }
 func easyjson888c126aDecodeGithubComVkcomStatshouseInternalApi7(in *jlexer.Lexer, out *MetricInfo) {
@@ -0,0 +1,98 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+	"github.com/vkcom/statshouse/internal/vkgo/rpc"
+)
+
+func newTestMetadataHandler(t *testing.T) *Handler {
+	m := metajournal.NewMetadataMock()
+	ln, err := net.Listen("tcp4", "127.0.0.1:")
+	require.NoError(t, err)
+	s := rpc.NewServer(rpc.ServerWithHandler(m.Handle))
+	t.Cleanup(func() { _ = s.Close() })
+	go func() { _ = s.Serve(ln) }()
+	c := rpc.NewClient(rpc.ClientWithLogf(t.Logf))
+	t.Cleanup(func() { _ = c.Close() })
+	storage := metajournal.MakeMetricsStorage("", nil, nil)
+	storage.Journal().Start(nil, nil, m.LoadJournal)
+	return &Handler{
+		metricsStorage: storage,
+		metadataLoader: metajournal.NewMetricMetaLoader(&tlmetadata.Client{
+			Client:  c,
+			Network: "tcp4",
+			Address: ln.Addr().String(),
+		}, time.Second),
+	}
+}
+
+func TestHandlePostMetricOptions(t *testing.T) {
+	h := newTestMetadataHandler(t)
+	ctx := context.Background()
+	ai := accessInfo{user: "admin@", bitAdmin: true}
+	save := func(m format.MetricMetaValue) format.MetricMetaValue {
+		res, err := h.handlePostMetric(ctx, ai, "", m)
+		require.NoError(t, err)
+		require.NoError(t, h.waitVersionUpdate(ctx, res.Version))
+		return res
+	}
+	m := save(format.MetricMetaValue{
+		Name:             "latency",
+		Kind:             format.MetricKindCounter,
+		Resolution:       1,
+		OptionsVersion:   format.MetricOptionsVersion,
+		HistogramBuckets: []float32{0.1, 1},
+	})
+
+	// client unaware of options keeps them
+	m = save(format.MetricMetaValue{MetricID: m.MetricID, Version: m.Version, Name: "latency", Kind: format.MetricKindCounter, Resolution: 1, Description: "latency"})
+	require.Equal(t, []float32{0.1, 1}, m.HistogramBuckets)
+
+	// but still can change kind, buckets are only for counters
+	m = save(format.MetricMetaValue{MetricID: m.MetricID, Version: m.Version, Name: "latency", Kind: format.MetricKindValue, Resolution: 1, Description: "latency"})
+	require.Equal(t, format.MetricKindValue, m.Kind)
+	require.Empty(t, m.HistogramBuckets)
+	require.Equal(t, format.MetricKindValue, h.metricsStorage.GetMetaMetric(m.MetricID).Kind)
+}
+
+func TestHandlePostMetricStateTime(t *testing.T) {
+	h := newTestMetadataHandler(t)
+	ctx := context.Background()
+	ai := accessInfo{user: "admin@", bitAdmin: true}
+	save := func(m format.MetricMetaValue) format.MetricMetaValue {
+		res, err := h.handlePostMetric(ctx, ai, "", m)
+		require.NoError(t, err)
+		require.NoError(t, h.waitVersionUpdate(ctx, res.Version))
+		return res
+	}
+	m := save(format.MetricMetaValue{Name: "errors", Kind: format.MetricKindCounter, Resolution: 1})
+	require.NotZero(t, m.StateTime)
+
+	m.State = format.MetricStateDisabled
+	m.StateTime = 1 // set by server only
+	m = save(m)
+	disabled := m.StateTime
+	require.Greater(t, disabled, uint32(1))
+
+	// edits do not change state time, so do not postpone deletion of data
+	m.Description = "errors"
+	m.StateTime = 0
+	m = save(m)
+	require.Equal(t, disabled, m.StateTime)
+	require.Equal(t, disabled, h.metricsStorage.GetMetaMetric(m.MetricID).StateTime)
+}
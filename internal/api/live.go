@@ -0,0 +1,364 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Live tail: client subscribes with the same parameters as series query and server keeps connection open,
+// pushing Server-Sent Events. First "series" event is full response, then each time invalidateLoop reports
+// changed seconds, time range slides to current time and "update" event carries only new or changed points.
+// Range is queried through the same caches, so only invalidated seconds are loaded from ClickHouse.
+// Identical live queries of the same user share evaluation, each subscriber only computes its own difference.
+// Series which are no longer present in range are reported by "remove" event.
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mailru/easyjson/jwriter"
+)
+
+//go:generate easyjson -no_std_marshalers live.go
+
+const (
+	liveKeepAliveInterval = 15 * time.Second
+	liveMaxDuration       = 1 * time.Hour // client is expected to reconnect
+	liveMaxQueriesPerUser = 10
+)
+
+type (
+	//easyjson:json
+	SeriesLiveUpdate struct {
+		From   int64       `json:"from"`   // points before are out of range
+		Series querySeries `json:"series"` // starting from the first new or changed point, unchanged series are omitted
+	}
+
+	//easyjson:json
+	SeriesLiveRemove struct {
+		Series []QuerySeriesMetaV2 `json:"series"`
+	}
+)
+
+// liveNotifier wakes up all live queries after cache invalidation
+type liveNotifier struct {
+	mu  sync.Mutex
+	ch  chan struct{}
+	gen uint64
+}
+
+func newLiveNotifier() *liveNotifier {
+	return &liveNotifier{ch: make(chan struct{})}
+}
+
+// channel is closed on next notification, generation identifies notifications already happened
+func (n *liveNotifier) wait() (<-chan struct{}, uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ch, n.gen
+}
+
+func (n *liveNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.ch)
+	n.ch = make(chan struct{})
+	n.gen++
+}
+
+// liveQueries limits live queries per user and shares evaluation of identical ones
+type liveQueries struct {
+	mu      sync.Mutex
+	queries map[string]*liveQuery
+	users   map[string]int
+}
+
+type liveQuery struct {
+	refs int // protected by liveQueries.mu
+	mu   sync.Mutex
+	gen  uint64
+	res  *SeriesResponse // shared between subscribers, must not be modified
+}
+
+func newLiveQueries() *liveQueries {
+	return &liveQueries{
+		queries: map[string]*liveQuery{},
+		users:   map[string]int{},
+	}
+}
+
+func (l *liveQueries) subscribe(user string, key string) (*liveQuery, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.users[user] >= liveMaxQueriesPerUser {
+		return nil, httpErr(http.StatusTooManyRequests, fmt.Errorf("too many live queries, at most %d are allowed per user", liveMaxQueriesPerUser))
+	}
+	l.users[user]++
+	q := l.queries[key]
+	if q == nil {
+		q = &liveQuery{}
+		l.queries[key] = q
+	}
+	q.refs++
+	return q, nil
+}
+
+func (l *liveQueries) unsubscribe(user string, key string, q *liveQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.users[user]--; l.users[user] <= 0 {
+		delete(l.users, user)
+	}
+	if q.refs--; q.refs <= 0 {
+		delete(l.queries, key)
+	}
+}
+
+// evaluates at most once per notification generation, errors are not shared because evaluation
+// might be cancelled by subscriber disconnecting
+func (q *liveQuery) get(gen uint64, evaluate func() (*SeriesResponse, error)) (*SeriesResponse, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.res != nil && q.gen >= gen {
+		return q.res, nil
+	}
+	res, err := evaluate()
+	if err != nil {
+		return nil, err
+	}
+	q.res, q.gen = res, gen
+	return res, nil
+}
+
+// subscribers with the same parameters and width share live query, response depends
+// on access rights (view bits, protected prefixes, roles), so it is shared by the same user only
+func liveQueryKey(r *http.Request, user string, width time.Duration) string {
+	form := url.Values{}
+	for k, v := range r.Form {
+		switch k {
+		case ParamFromTime, ParamToTime, ParamQueryVerbose:
+		default:
+			form[k] = v
+		}
+	}
+	return fmt.Sprint(user, " ", width, " ", form.Encode())
+}
+
+func liveSeriesKey(meta QuerySeriesMetaV2) string {
+	keys := make([]string, 0, len(meta.Tags))
+	for k, v := range meta.Tags {
+		keys = append(keys, k+"="+v.Value)
+	}
+	sort.Strings(keys)
+	return fmt.Sprint(meta.What, meta.TimeShift, keys)
+}
+
+// response data is released after query, so live query keeps its own copy
+func copySeriesData(res *SeriesResponse) {
+	res.Series.Time = append([]int64(nil), res.Series.Time...)
+	for i, d := range res.Series.SeriesData {
+		v := append([]float64(nil), *d...)
+		res.Series.SeriesData[i] = &v
+	}
+}
+
+func sameValue(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+// returns series of prev not present in cur
+func liveRemovedSeries(prev, cur *SeriesResponse) []QuerySeriesMetaV2 {
+	curKeys := make(map[string]bool, len(cur.Series.SeriesMeta))
+	for _, meta := range cur.Series.SeriesMeta {
+		curKeys[liveSeriesKey(meta)] = true
+	}
+	var res []QuerySeriesMetaV2
+	for _, meta := range prev.Series.SeriesMeta {
+		if !curKeys[liveSeriesKey(meta)] {
+			res = append(res, meta)
+		}
+	}
+	return res
+}
+
+// returns nil if cur has no new or changed points compared to prev
+func newSeriesLiveUpdate(prev, cur *SeriesResponse) *SeriesLiveUpdate {
+	prevTime := make(map[int64]int, len(prev.Series.Time))
+	for i, t := range prev.Series.Time {
+		prevTime[t] = i
+	}
+	prevData := make(map[string][]float64, len(prev.Series.SeriesMeta))
+	for i, meta := range prev.Series.SeriesMeta {
+		prevData[liveSeriesKey(meta)] = *prev.Series.SeriesData[i]
+	}
+	first := len(cur.Series.Time)
+	firstChange := make([]int, len(cur.Series.SeriesMeta)) // per series
+	for i, meta := range cur.Series.SeriesMeta {
+		p, ok := prevData[liveSeriesKey(meta)]
+		d := *cur.Series.SeriesData[i]
+		j := 0
+		for ; j < len(d); j++ {
+			if math.IsNaN(d[j]) && !ok {
+				continue // no need to send gaps of new series
+			}
+			k, found := prevTime[cur.Series.Time[j]]
+			if !ok || !found || !sameValue(p[k], d[j]) {
+				break
+			}
+		}
+		firstChange[i] = j
+		if j < first {
+			first = j
+		}
+	}
+	if first == len(cur.Series.Time) {
+		return nil
+	}
+	res := &SeriesLiveUpdate{
+		Series: querySeries{
+			Time:       cur.Series.Time[first:],
+			SeriesMeta: []QuerySeriesMetaV2{},
+			SeriesData: []*[]float64{},
+		},
+	}
+	if len(cur.Series.Time) != 0 {
+		res.From = cur.Series.Time[0]
+	}
+	for i, meta := range cur.Series.SeriesMeta {
+		if firstChange[i] == len(cur.Series.Time) {
+			continue
+		}
+		d := (*cur.Series.SeriesData[i])[first:]
+		res.Series.SeriesMeta = append(res.Series.SeriesMeta, meta)
+		res.Series.SeriesData = append(res.Series.SeriesData, &d)
+	}
+	return res
+}
+
+func writeLiveEvent(w http.ResponseWriter, event string, r Response) error {
+	var jw jwriter.Writer
+	r.MarshalEasyJSON(&jw)
+	if jw.Error != nil {
+		return jw.Error
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: ", event); err != nil {
+		return err
+	}
+	if _, err := jw.DumpTo(w); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte("\n\n")); err != nil {
+		return err
+	}
+	w.(http.Flusher).Flush()
+	return nil
+}
+
+func (h *Handler) HandleSeriesQueryLive(w http.ResponseWriter, r *http.Request) {
+	var err error
+	var req seriesRequest
+	sl := newEndpointStatHTTP(EndpointQueryLive, r.Method, h.getMetricIDForStat(r.FormValue(ParamMetric)), "", r.FormValue(paramPriority))
+	if req, err = h.parseHTTPRequest(r); err == nil {
+		if req.ai, err = h.parseAccessToken(r, sl); err == nil {
+			err = req.validate()
+		}
+	}
+	if err == nil {
+		if _, ok := w.(http.Flusher); !ok {
+			err = fmt.Errorf("streaming is not supported")
+		}
+	}
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, req.ai.user, sl)
+		return
+	}
+	ctx := r.Context()
+	width := req.to.Sub(req.from)
+	key := liveQueryKey(r, req.ai.user, width)
+	lq, err := h.liveQueries.subscribe(req.ai.user, key)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, req.ai.user, sl)
+		return
+	}
+	defer h.liveQueries.unsubscribe(req.ai.user, key, lq)
+	query := func(ctx context.Context, req seriesRequest) (*SeriesResponse, error) {
+		s, cancel, err := h.handleSeriesRequestS(withEndpointStat(ctx, sl), req, sl, make([]seriesResponse, 2))
+		if err != nil {
+			return nil, err
+		}
+		defer cancel()
+		res := h.buildSeriesResponse(s...)
+		copySeriesData(res)
+		return res, nil
+	}
+	wait, _ := h.liveNotifier.wait() // do not miss invalidation happened during first query
+	prev, err := query(ctx, req)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, req.ai.user, sl)
+		return
+	}
+	prev.Annotations = h.getAnnotations(req.ai, prev.MetricMeta, req.from.Unix(), req.to.Unix(), req.filterIn)
+
+	sl.reportServiceTime(http.StatusOK, nil)
+	defer sl.reportResponseTime(http.StatusOK)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err = writeLiveEvent(w, "series", Response{Data: prev}); err != nil {
+		return
+	}
+	req.verbose = false // badges and debug queries are sent only once
+	deadline := time.NewTimer(liveMaxDuration)
+	defer deadline.Stop()
+	keepAlive := time.NewTicker(liveKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			return
+		case <-keepAlive.C:
+			if _, err = w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		case <-wait:
+			var gen uint64
+			wait, gen = h.liveNotifier.wait()
+			cur, err := lq.get(gen, func() (*SeriesResponse, error) {
+				req.to = time.Now()
+				req.from = req.to.Add(-width)
+				return query(ctx, req)
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					_ = writeLiveEvent(w, "error", Response{Error: err.Error()})
+				}
+				return
+			}
+			removed := liveRemovedSeries(prev, cur)
+			u := newSeriesLiveUpdate(prev, cur)
+			prev = cur
+			if len(removed) != 0 {
+				if err = writeLiveEvent(w, "remove", Response{Data: SeriesLiveRemove{Series: removed}}); err != nil {
+					return
+				}
+			}
+			if u == nil {
+				continue
+			}
+			if err = writeLiveEvent(w, "update", Response{Data: u}); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testLiveSeries(time []int64, series map[string][]float64) *SeriesResponse {
+	res := &SeriesResponse{Series: querySeries{Time: time}}
+	for _, what := range []string{"count", "avg"} {
+		if d, ok := series[what]; ok {
+			res.Series.SeriesMeta = append(res.Series.SeriesMeta, QuerySeriesMetaV2{
+				What: what,
+				Tags: map[string]SeriesMetaTag{"key1": {Value: "production"}},
+			})
+			res.Series.SeriesData = append(res.Series.SeriesData, &d)
+		}
+	}
+	return res
+}
+
+func TestSeriesLiveUpdate(t *testing.T) {
+	nan := math.NaN()
+	prev := testLiveSeries([]int64{10, 11, 12}, map[string][]float64{"count": {1, 2, nan}})
+
+	// nothing changed
+	cur := testLiveSeries([]int64{10, 11, 12}, map[string][]float64{"count": {1, 2, nan}})
+	require.Nil(t, newSeriesLiveUpdate(prev, cur))
+
+	// range slided, last point changed and new point appeared
+	cur = testLiveSeries([]int64{11, 12, 13}, map[string][]float64{"count": {2, 3, 4}})
+	u := newSeriesLiveUpdate(prev, cur)
+	require.NotNil(t, u)
+	require.Equal(t, int64(11), u.From)
+	require.Equal(t, []int64{12, 13}, u.Series.Time)
+	require.Len(t, u.Series.SeriesData, 1)
+	require.Equal(t, []float64{3, 4}, *u.Series.SeriesData[0])
+
+	// new series is sent starting from its first point, unchanged series is omitted
+	prev = cur
+	cur = testLiveSeries([]int64{11, 12, 13}, map[string][]float64{"count": {2, 3, 4}, "avg": {nan, nan, 5}})
+	u = newSeriesLiveUpdate(prev, cur)
+	require.NotNil(t, u)
+	require.Equal(t, []int64{13}, u.Series.Time)
+	require.Len(t, u.Series.SeriesMeta, 1)
+	require.Equal(t, "avg", u.Series.SeriesMeta[0].What)
+	require.Equal(t, []float64{5}, *u.Series.SeriesData[0])
+}
+
+func TestLiveRemovedSeries(t *testing.T) {
+	prev := testLiveSeries([]int64{10, 11}, map[string][]float64{"count": {1, 2}, "avg": {3, 4}})
+	cur := testLiveSeries([]int64{11, 12}, map[string][]float64{"count": {2, 3}})
+	require.Empty(t, liveRemovedSeries(prev, prev))
+	removed := liveRemovedSeries(prev, cur)
+	require.Len(t, removed, 1)
+	require.Equal(t, "avg", removed[0].What)
+}
+
+func TestLiveNotifier(t *testing.T) {
+	n := newLiveNotifier()
+	w, gen := n.wait()
+	select {
+	case <-w:
+		t.Fatal("notified before notify")
+	default:
+	}
+	n.notify()
+	<-w
+	w, next := n.wait()
+	require.Equal(t, gen+1, next)
+	select {
+	case <-w:
+		t.Fatal("next wait must block until next notify")
+	default:
+	}
+}
+
+func TestLiveQueries(t *testing.T) {
+	l := newLiveQueries()
+	var qs []*liveQuery
+	for i := 0; i < liveMaxQueriesPerUser; i++ {
+		q, err := l.subscribe("user", "key")
+		require.NoError(t, err)
+		qs = append(qs, q)
+	}
+	require.Same(t, qs[0], qs[len(qs)-1])
+	_, err := l.subscribe("user", "other")
+	require.Error(t, err)
+	_, err = l.subscribe("other", "other")
+	require.NoError(t, err)
+	l.unsubscribe("user", "key", qs[0])
+	_, err = l.subscribe("user", "other")
+	require.NoError(t, err)
+
+	// evaluated once per generation, errors are not shared
+	q := qs[0]
+	evaluations := 0
+	evaluate := func() (*SeriesResponse, error) {
+		evaluations++
+		return &SeriesResponse{}, nil
+	}
+	fail := func() (*SeriesResponse, error) {
+		return nil, fmt.Errorf("cancelled")
+	}
+	_, err = q.get(1, fail)
+	require.Error(t, err)
+	res, err := q.get(1, evaluate)
+	require.NoError(t, err)
+	same, err := q.get(1, evaluate)
+	require.NoError(t, err)
+	require.Same(t, res, same)
+	require.Equal(t, 1, evaluations)
+	_, err = q.get(2, evaluate)
+	require.NoError(t, err)
+	require.Equal(t, 2, evaluations)
+}
+
+func TestLiveQueryKey(t *testing.T) {
+	key := func(query string, user string, width time.Duration) string {
+		r := httptest.NewRequest("GET", "/?"+query, nil)
+		require.NoError(t, r.ParseForm())
+		return liveQueryKey(r, user, width)
+	}
+	k := key("s=metric&f=1&t=2&qw=count", "alice@", time.Hour)
+	require.Equal(t, k, key("s=metric&f=3&t=4&qw=count", "alice@", time.Hour))
+	require.NotEqual(t, k, key("s=metric&f=1&t=2&qw=count", "bob@", time.Hour)) // access rights differ between users
+	require.NotEqual(t, k, key("s=metric&f=1&t=2&qw=count", "alice@", time.Minute))
+	require.NotEqual(t, k, key("s=metric&f=1&t=2&qw=avg", "alice@", time.Hour))
+}
@@ -0,0 +1,182 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mailru/easyjson/jwriter"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+)
+
+//go:generate easyjson -no_std_marshalers mapping_search.go
+
+const (
+	paramMappingPrefix = "prefix"
+	paramMappingRegex  = "re" // RE2 syntax
+	paramMappingFromID = "from"
+	paramMappingLimit  = "limit"
+
+	dataFormatJSON = "json" // one object per line
+	dataFormatTL   = "tl"   // sequence of boxed metadata.mappingInfo
+
+	mappingExportPageSize = 10_000
+)
+
+type (
+	//easyjson:json
+	MappingSearchResp struct {
+		Mappings []MappingInfo `json:"mappings"`
+		NextID   int32         `json:"next_id"` // pass as "from" to get next page, 0 if there are no more mappings
+	}
+
+	//easyjson:json
+	MappingInfo struct {
+		ID        int32  `json:"id"`
+		Value     string `json:"value"`
+		Metric    string `json:"metric,omitempty"`     // for which mapping was created, empty if unknown
+		CreatedAt uint32 `json:"created_at,omitempty"` // rounded to flood limit step, 0 if unknown
+	}
+)
+
+func newMappingInfo(m tlmetadata.MappingInfo) MappingInfo {
+	return MappingInfo{ID: m.Id, Value: m.Name, Metric: m.Metric, CreatedAt: m.CreatedAt}
+}
+
+func (h *Handler) handleMappingSearch(ctx context.Context, ai accessInfo, prefix string, regex string, fromID int32, limit int32) (*MappingSearchResp, error) {
+	if !ai.isAdmin() {
+		return nil, httpErr(http.StatusForbidden, fmt.Errorf("admin access required"))
+	}
+	mappings, nextID, err := h.metadataLoader.SearchMappings(ctx, prefix, regex, fromID, limit)
+	if err != nil {
+		return nil, err
+	}
+	res := &MappingSearchResp{Mappings: make([]MappingInfo, 0, len(mappings)), NextID: nextID}
+	for _, m := range mappings {
+		res.Mappings = append(res.Mappings, newMappingInfo(m))
+	}
+	return res, nil
+}
+
+func parseMappingSearchParams(r *http.Request) (fromID int32, limit int32, err error) {
+	for _, p := range []struct {
+		name string
+		v    *int32
+	}{{paramMappingFromID, &fromID}, {paramMappingLimit, &limit}} {
+		s := r.FormValue(p.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, 0, httpErr(http.StatusBadRequest, fmt.Errorf("can't parse %s: %w", p.name, err))
+		}
+		*p.v = int32(n)
+	}
+	return fromID, limit, nil
+}
+
+func writeMappingExport(w io.Writer, dataFormat string, mappings []tlmetadata.MappingInfo, buf []byte) ([]byte, error) {
+	buf = buf[:0]
+	switch dataFormat {
+	case dataFormatTL:
+		for i := range mappings {
+			buf = mappings[i].WriteBoxed(buf)
+		}
+	default:
+		var jw jwriter.Writer
+		for _, m := range mappings {
+			info := newMappingInfo(m)
+			info.MarshalEasyJSON(&jw)
+			jw.RawByte('\n')
+		}
+		if jw.Error != nil {
+			return buf, jw.Error
+		}
+		buf = jw.Buffer.BuildBytes(buf)
+	}
+	_, err := w.Write(buf)
+	return buf, err
+}
+
+func (h *Handler) HandleGetMappingSearch(w http.ResponseWriter, r *http.Request) {
+	sl := newEndpointStatHTTP(EndpointMappingSearch, r.Method, 0, "", r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	fromID, limit, err := parseMappingSearchParams(r)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	resp, err := h.handleMappingSearch(r.Context(), ai, r.FormValue(paramMappingPrefix), r.FormValue(paramMappingRegex), fromID, limit)
+	respondJSON(w, resp, 0, 0, err, h.verbose, ai.user, sl)
+}
+
+// whole mapping table is read page by page, each page is written as soon as it is received
+func (h *Handler) HandleGetMappingExport(w http.ResponseWriter, r *http.Request) {
+	dataFormat := r.FormValue(paramDataFormat)
+	if dataFormat == "" {
+		dataFormat = dataFormatJSON
+	}
+	sl := newEndpointStatHTTP(EndpointMappingExport, r.Method, 0, dataFormat, r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	if dataFormat != dataFormatJSON && dataFormat != dataFormatTL {
+		respondJSON(w, nil, 0, 0, httpErr(http.StatusBadRequest, fmt.Errorf("unsupported export format %q", dataFormat)), h.verbose, ai.user, sl)
+		return
+	}
+	if !ai.isAdmin() {
+		respondJSON(w, nil, 0, 0, httpErr(http.StatusForbidden, fmt.Errorf("admin access required")), h.verbose, ai.user, sl)
+		return
+	}
+	ctx := r.Context()
+	prefix := r.FormValue(paramMappingPrefix)
+	regex := r.FormValue(paramMappingRegex)
+	mappings, nextID, err := h.metadataLoader.SearchMappings(ctx, prefix, regex, 0, mappingExportPageSize)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	sl.reportServiceTime(http.StatusOK, nil)
+	defer sl.reportResponseTime(http.StatusOK)
+	contentType := "application/x-ndjson"
+	if dataFormat == dataFormatTL {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=mappings-%s.%s", time.Now().Format("2006-01-02"), dataFormat))
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	var buf []byte
+	for {
+		if buf, err = writeMappingExport(w, dataFormat, mappings, buf); err != nil {
+			break
+		}
+		flushExport(w)
+		if nextID == 0 {
+			break
+		}
+		if mappings, nextID, err = h.metadataLoader.SearchMappings(ctx, prefix, regex, nextID, mappingExportPageSize); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("[error] failed to write mapping export: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+)
+
+func TestWriteMappingExport(t *testing.T) {
+	mappings := []tlmetadata.MappingInfo{
+		{Id: 1, Name: "host-1", Metric: "api_requests", CreatedAt: 1641027720},
+		{Id: 2, Name: "host-2"},
+	}
+	var w bytes.Buffer
+	buf, err := writeMappingExport(&w, dataFormatJSON, mappings, nil)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1,"value":"host-1","metric":"api_requests","created_at":1641027720}
+{"id":2,"value":"host-2"}
+`, w.String())
+
+	w.Reset()
+	_, err = writeMappingExport(&w, dataFormatTL, mappings, buf)
+	require.NoError(t, err)
+	var got []tlmetadata.MappingInfo
+	for b := w.Bytes(); len(b) != 0; {
+		var m tlmetadata.MappingInfo
+		b, err = m.ReadBoxed(b)
+		require.NoError(t, err)
+		got = append(got, m)
+	}
+	require.Equal(t, mappings, got)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/mailru/easyjson/jlexer"
+
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+//go:generate easyjson -no_std_marshalers metadata_sync.go
+
+type (
+	//easyjson:json
+	MetadataSyncResp struct {
+		Changes []metajournal.SchemaChange `json:"changes"`
+		Applied bool                       `json:"applied"`
+	}
+
+	// parsed by metajournal.ParseSchema, the same way as schema files
+	metadataSchemaJSON []byte
+)
+
+func (d *metadataSchemaJSON) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	*d = append((*d)[:0], l.Raw()...)
+}
+
+func (h *Handler) handleMetadataSync(ctx context.Context, ai accessInfo, data []byte, apply bool) (*MetadataSyncResp, int64, error) {
+	if !ai.isAdmin() {
+		return nil, 0, httpErr(http.StatusForbidden, fmt.Errorf("admin access required"))
+	}
+	schema, err := metajournal.ParseSchema(data, false)
+	if err != nil {
+		return nil, 0, httpErr(http.StatusBadRequest, err)
+	}
+	plan, err := metajournal.PlanSchema(h.metricsStorage, schema)
+	if err != nil {
+		return nil, 0, httpErr(http.StatusBadRequest, err)
+	}
+	res := &MetadataSyncResp{Changes: plan, Applied: apply}
+	if res.Changes == nil {
+		res.Changes = []metajournal.SchemaChange{}
+	}
+	if !apply || len(plan) == 0 {
+		return res, 0, nil
+	}
+	version, err := metajournal.ApplySchema(ctx, h.metadataLoader, h.metricsStorage, plan, ai.toMetadata())
+	if err != nil {
+		return nil, 0, err
+	}
+	return res, version, nil
+}
+
+func (h *Handler) HandlePostMetadataSync(w http.ResponseWriter, r *http.Request) {
+	var data metadataSchemaJSON
+	apply := r.FormValue(paramApply) == "1"
+	handlePostEntity(h, w, r, EndpointMetadataSync, &data, func(ctx context.Context, ai accessInfo, entity *metadataSchemaJSON, _ bool) (resp interface{}, versionToWait int64, err error) {
+		return h.handleMetadataSync(ctx, ai, *entity, apply)
+	})
+}
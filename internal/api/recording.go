@@ -0,0 +1,176 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Recording rule is named after its target metric, every series returned by rule expression
+// is written as value of this metric through local agent, series labels become named tags.
+// Target metric must exist, tags are mapped by its tag names, like for any other metric.
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/vkcom/statshouse-go"
+
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/metajournal"
+)
+
+//go:generate easyjson -no_std_marshalers recording.go
+
+type (
+	//easyjson:json
+	RecordingRuleInfo struct {
+		RecordingRule format.RecordingRule `json:"recording_rule"`
+		Delete        bool                 `json:"delete_mark"`
+	}
+
+	//easyjson:json
+	GetRecordingRuleListResp struct {
+		RecordingRules []recordingRuleShortInfo `json:"recording_rules"`
+	}
+
+	recordingRuleShortInfo struct {
+		Id      int32  `json:"id"`
+		Name    string `json:"name"`
+		Disable bool   `json:"disable"`
+	}
+)
+
+type recordingRuleEvaluator struct {
+	m *ruleManager
+}
+
+func (e *recordingRuleEvaluator) interval(rule *format.RecordingRule) time.Duration {
+	return time.Duration(rule.EffectiveInterval()) * time.Second
+}
+
+func (e *recordingRuleEvaluator) eval(rule *format.RecordingRule, scheduled time.Time) {
+	status := format.TagValueIDRecordingRuleStatusOK
+	samples, err := e.m.query(context.Background(), rule.Expr, scheduled)
+	if err != nil {
+		log.Printf("[error] failed to evaluate recording rule %q: %v", rule.Name, err)
+		status = format.TagValueIDRecordingRuleStatusError
+	}
+	for _, s := range samples {
+		statshouse.MetricNamed(rule.Name, recordingRuleTags(rule, s.labels)).Value(s.value)
+	}
+	statshouse.Metric(
+		format.BuiltinMetricNameRecordingRuleEval,
+		statshouse.Tags{
+			1: strconv.Itoa(int(rule.ID)),
+			2: strconv.Itoa(status),
+		},
+	).Value(time.Since(scheduled).Seconds())
+}
+
+func (e *recordingRuleEvaluator) close(*format.RecordingRule) {}
+
+func recordingRuleTags(rule *format.RecordingRule, series map[string]string) statshouse.NamedTags {
+	lbs := make(map[string]string, len(series)+len(rule.Labels))
+	for k, v := range series {
+		lbs[k] = v
+	}
+	for k, v := range rule.Labels {
+		lbs[k] = v
+	}
+	res := make(statshouse.NamedTags, 0, len(lbs))
+	for k, v := range lbs {
+		res = append(res, [2]string{k, v})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i][0] < res[j][0] })
+	return res
+}
+
+func (h *Handler) handleGetRecordingRule(ctx context.Context, _ accessInfo, id int32, version int64) (*RecordingRuleInfo, time.Duration, error) {
+	if version == 0 {
+		rule := h.metricsStorage.GetRecordingRule(id)
+		if rule == nil {
+			return nil, 0, httpErr(http.StatusNotFound, fmt.Errorf("recording rule %d not found", id))
+		}
+		return &RecordingRuleInfo{RecordingRule: *rule}, defaultCacheTTL, nil
+	}
+	rule, err := h.metadataLoader.GetRecordingRule(ctx, int64(id), version)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &RecordingRuleInfo{RecordingRule: rule}, defaultCacheTTL, nil
+}
+
+func (h *Handler) handleGetRecordingRuleList(_ accessInfo, showInvisible bool) (*GetRecordingRuleListResp, time.Duration, error) {
+	resp := &GetRecordingRuleListResp{}
+	for _, rule := range h.metricsStorage.GetRecordingRuleList() {
+		if rule.Disable && !showInvisible {
+			continue
+		}
+		resp.RecordingRules = append(resp.RecordingRules, recordingRuleShortInfo{
+			Id:      rule.ID,
+			Name:    rule.Name,
+			Disable: rule.Disable,
+		})
+	}
+	return resp, defaultCacheTTL, nil
+}
+
+func (h *Handler) handlePostRecordingRule(ctx context.Context, ai accessInfo, rule format.RecordingRule, create, delete bool) (*RecordingRuleInfo, error) {
+	if !create {
+		old := h.metricsStorage.GetRecordingRule(rule.ID)
+		if old == nil {
+			return &RecordingRuleInfo{}, httpErr(http.StatusNotFound, fmt.Errorf("recording rule %d not found", rule.ID))
+		}
+		// otherwise rule writing into metric could be taken over by user who can not edit that metric
+		if metric := h.metricsStorage.GetMetaMetricByName(old.Name); metric != nil && !ai.CanEditMetric(false, *metric, *metric) {
+			return &RecordingRuleInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit metric %q", old.Name))
+		}
+	}
+	metric := h.metricsStorage.GetMetaMetricByName(rule.Name)
+	if metric == nil {
+		return &RecordingRuleInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("recording rule metric %q must be created first", rule.Name))
+	}
+	if !ai.CanEditMetric(false, *metric, *metric) {
+		return &RecordingRuleInfo{}, httpErr(http.StatusForbidden, fmt.Errorf("can't edit metric %q", rule.Name))
+	}
+	if err := h.checkRuleExpr(ai, rule.Expr); err != nil {
+		return &RecordingRuleInfo{}, err
+	}
+	rule, err := h.metadataLoader.SaveRecordingRule(ctx, rule, create, delete, ai.toMetadata())
+	if err != nil {
+		s := "edit"
+		if create {
+			s = "create"
+		}
+		if metajournal.IsUserRequestError(err) {
+			return &RecordingRuleInfo{}, httpErr(http.StatusBadRequest, fmt.Errorf("can't %s recording rule: %w", s, err))
+		}
+		return &RecordingRuleInfo{}, fmt.Errorf("can't %s recording rule: %w", s, err)
+	}
+	return &RecordingRuleInfo{RecordingRule: rule}, nil
+}
+
+func (h *Handler) HandleGetRecordingRule(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntity(w, r, h, EndpointRecordingRule, h.handleGetRecordingRule)
+}
+
+func (h *Handler) HandleGetRecordingRuleList(w http.ResponseWriter, r *http.Request) {
+	HandleGetEntityList(w, r, h, EndpointRecordingRuleList, h.handleGetRecordingRuleList)
+}
+
+func (h *Handler) HandlePutPostRecordingRule(w http.ResponseWriter, r *http.Request) {
+	var ruleInfo RecordingRuleInfo
+	handlePostEntity(h, w, r, EndpointRecordingRule, &ruleInfo, func(ctx context.Context, ai accessInfo, entity *RecordingRuleInfo, create bool) (resp interface{}, versionToWait int64, err error) {
+		response, err := h.handlePostRecordingRule(ctx, ai, entity.RecordingRule, create, entity.Delete)
+		if err != nil {
+			return nil, 0, err
+		}
+		return response, response.RecordingRule.Version, nil
+	})
+}
@@ -0,0 +1,26 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vkcom/statshouse-go"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestRecordingRuleTags(t *testing.T) {
+	rule := &format.RecordingRule{
+		Name:   "api_latency_p99",
+		Expr:   `histogram_quantile(0.99, sum by (le, env) (api_latency))`,
+		Labels: map[string]string{"source": "recording", "env": "production"},
+	}
+	tags := recordingRuleTags(rule, map[string]string{"env": "staging", "1": "GET"})
+	require.Equal(t, statshouse.NamedTags{{"1", "GET"}, {"env", "production"}, {"source", "recording"}}, tags)
+}
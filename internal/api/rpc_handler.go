@@ -254,8 +254,81 @@ func (h *RPCHandler) ReleaseChunks(_ context.Context, args tlstatshouseApi.Relea
 	return res, nil
 }
 
+func (h *RPCHandler) CreateAnnotation(ctx context.Context, args tlstatshouseApi.CreateAnnotation) (tlstatshouseApi.CreateAnnotationResponse, error) {
+	es := newEndpointStatRPC(EndpointAnnotation, args.TLName())
+	ai, err := h.parseAccessToken(args.AccessToken)
+	defer func() {
+		h.statRpcTime(es, err, recover())
+	}()
+	if err != nil {
+		err = rpc.Error{Code: rpcErrorCodeAuthFailed, Description: fmt.Sprintf("can't parse access token: %v", err)}
+		return tlstatshouseApi.CreateAnnotationResponse{}, err
+	}
+	es.setAccessInfo(ai)
+	if h.ah.readOnly {
+		err = rpc.Error{Code: rpcErrorCodeForbidden, Description: "readonly mode"}
+		return tlstatshouseApi.CreateAnnotationResponse{}, err
+	}
+	if ai.readOnly {
+		err = rpc.Error{Code: rpcErrorCodeForbidden, Description: fmt.Sprintf("read-only API token %q", ai.apiToken)}
+		return tlstatshouseApi.CreateAnnotationResponse{}, err
+	}
+	resp, err := h.ah.handlePostAnnotation(ctx, ai, format.Annotation{
+		Name:      args.Name,
+		From:      args.From,
+		To:        args.To,
+		Text:      args.Text,
+		Namespace: args.Namespace,
+		Metric:    args.Metric,
+		Tags:      args.Tags,
+	}, true, false)
+	if err != nil {
+		err = rpc.Error{Code: rpcErrorCodeQueryHandlingFailed, Description: fmt.Sprintf("can't create annotation: %v", err)}
+		return tlstatshouseApi.CreateAnnotationResponse{}, err
+	}
+	return tlstatshouseApi.CreateAnnotationResponse{
+		Id:      resp.Annotation.ID,
+		Version: resp.Annotation.Version,
+	}, nil
+}
+
+func (h *RPCHandler) RollbackEntity(ctx context.Context, args tlstatshouseApi.RollbackEntity) (tlstatshouseApi.RollbackEntityResponse, error) {
+	es := newEndpointStatRPC(EndpointHistoryRollback, args.TLName())
+	ai, err := h.parseAccessToken(args.AccessToken)
+	defer func() {
+		h.statRpcTime(es, err, recover())
+	}()
+	if err != nil {
+		err = rpc.Error{Code: rpcErrorCodeAuthFailed, Description: fmt.Sprintf("can't parse access token: %v", err)}
+		return tlstatshouseApi.RollbackEntityResponse{}, err
+	}
+	es.setAccessInfo(ai)
+	if h.ah.readOnly {
+		err = rpc.Error{Code: rpcErrorCodeForbidden, Description: "readonly mode"}
+		return tlstatshouseApi.RollbackEntityResponse{}, err
+	}
+	if ai.readOnly {
+		err = rpc.Error{Code: rpcErrorCodeForbidden, Description: fmt.Sprintf("read-only API token %q", ai.apiToken)}
+		return tlstatshouseApi.RollbackEntityResponse{}, err
+	}
+	resp, err := h.ah.handleRollbackEntity(ctx, ai, args.Id, args.Version)
+	if err != nil {
+		err = rpc.Error{Code: rpcErrorCodeQueryHandlingFailed, Description: fmt.Sprintf("can't rollback entity: %v", err)}
+		return tlstatshouseApi.RollbackEntityResponse{}, err
+	}
+	return tlstatshouseApi.RollbackEntityResponse{
+		Id:      resp.ID,
+		Version: resp.Version,
+	}, nil
+}
+
 func (h *RPCHandler) parseAccessToken(token string) (accessInfo, error) {
-	return parseAccessToken(h.jwtHelper, token, h.protectedPrefixes, h.localMode, h.insecureMode)
+	if isAPIToken(token) {
+		return parseAPIToken(h.ah.metricsStorage, token, h.protectedPrefixes, time.Now())
+	}
+	ai, err := parseAccessToken(h.jwtHelper, token, h.protectedPrefixes, h.localMode, h.insecureMode)
+	ai.roles = h.ah.metricsStorage
+	return ai, err
 }
 
 type seriesRequestRPC struct {
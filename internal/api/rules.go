@@ -0,0 +1,284 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Alert and recording rules are metadata entities, evaluated by API instance started with --alerting or --recording-rules.
+// Every rule is evaluated by separate goroutine, so slow rules do not delay others.
+// Rule changes are picked up on the next evaluation, interval changes after the next evaluation.
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/promql"
+)
+
+const (
+	ruleSyncInterval = time.Second
+	ruleUser         = "@rules"
+)
+
+var errRuleNotVector = fmt.Errorf("rule expression must return instant vector")
+
+type ruleEvaluator[R any] interface {
+	eval(rule *R, scheduled time.Time)
+	interval(rule *R) time.Duration
+	close(rule *R) // called after last evaluation
+}
+
+type ruleWorker[R any] struct {
+	e    ruleEvaluator[R]
+	rule atomic.Pointer[R]
+	stop chan struct{}
+	done chan struct{}
+}
+
+type ruleManager struct {
+	h              *Handler
+	client         *http.Client
+	alertRules     map[int32]*ruleWorker[format.AlertRule] // accessed only by run goroutine
+	recordingRules map[int32]*ruleWorker[format.RecordingRule]
+	stop           chan chan struct{}
+}
+
+type ruleSample struct {
+	labels map[string]string
+	value  float64
+}
+
+func newRuleManager(h *Handler) *ruleManager {
+	return &ruleManager{
+		h:              h,
+		client:         &http.Client{Timeout: alertWebhookTimeout},
+		alertRules:     map[int32]*ruleWorker[format.AlertRule]{},
+		recordingRules: map[int32]*ruleWorker[format.RecordingRule]{},
+		stop:           make(chan chan struct{}),
+	}
+}
+
+// rules are evaluated by service, so author must be able to view every metric of expression
+func (h *Handler) checkRuleExpr(ai accessInfo, expr string) error {
+	matchers, err := promql.GetMetricNameMatchers(expr, nil)
+	if err != nil {
+		return httpErr(http.StatusBadRequest, fmt.Errorf("invalid rule expression: %w", err))
+	}
+	return h.checkRuleMatchers(ai, matchers)
+}
+
+func (h *Handler) checkRuleMatchers(ai accessInfo, matchers []*labels.Matcher) error {
+	for _, matcher := range matchers {
+		for _, metric := range h.metricsStorage.MatchMetrics(matcher, "", true, nil) {
+			if !ai.CanViewMetric(*metric) {
+				return httpErr(http.StatusForbidden, fmt.Errorf("metric %q forbidden", metric.Name))
+			}
+		}
+	}
+	return nil
+}
+
+// metric name matchers of rule expressions are parsed once per rule version, not on every rule list request
+type ruleMatcherCache struct {
+	mu    sync.Mutex
+	rules map[int32]ruleMatchers
+}
+
+type ruleMatchers struct {
+	version  int64
+	matchers []*labels.Matcher
+	err      error
+}
+
+func newRuleMatcherCache() *ruleMatcherCache {
+	return &ruleMatcherCache{rules: map[int32]ruleMatchers{}}
+}
+
+func (c *ruleMatcherCache) get(id int32, version int64, expr string) ([]*labels.Matcher, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.rules[id]
+	if !ok || r.version != version {
+		r.version = version
+		r.matchers, r.err = promql.GetMetricNameMatchers(expr, nil)
+		c.rules[id] = r
+	}
+	return r.matchers, r.err
+}
+
+// forget rules which were deleted
+func (c *ruleMatcherCache) retain(ids map[int32]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.rules {
+		if !ids[id] {
+			delete(c.rules, id)
+		}
+	}
+}
+
+func (m *ruleManager) run() {
+	ticker := time.NewTicker(ruleSyncInterval)
+	defer ticker.Stop()
+	for {
+		m.sync()
+		select {
+		case ch := <-m.stop:
+			syncRuleWorkers(m.alertRules, nil, nil, nil)
+			syncRuleWorkers(m.recordingRules, nil, nil, nil)
+			close(ch)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *ruleManager) close() {
+	ch := make(chan struct{})
+	m.stop <- ch
+	<-ch
+}
+
+func (m *ruleManager) sync() {
+	if m.h.alerting {
+		syncRuleWorkers(m.alertRules, m.h.metricsStorage.GetAlertRuleList(),
+			func(rule *format.AlertRule) (int32, bool) { return rule.ID, !rule.Disable },
+			func() ruleEvaluator[format.AlertRule] { return &alertRuleEvaluator{m: m, alerts: map[string]*alert{}} })
+	}
+	if m.h.recordingRules {
+		syncRuleWorkers(m.recordingRules, m.h.metricsStorage.GetRecordingRuleList(),
+			func(rule *format.RecordingRule) (int32, bool) { return rule.ID, !rule.Disable },
+			func() ruleEvaluator[format.RecordingRule] { return &recordingRuleEvaluator{m: m} })
+	}
+}
+
+// starts workers for new rules, stops workers for deleted and disabled rules
+func syncRuleWorkers[R any](workers map[int32]*ruleWorker[R], rules []*R, ruleID func(*R) (id int32, enabled bool), newEvaluator func() ruleEvaluator[R]) {
+	active := make(map[int32]bool, len(rules))
+	for _, rule := range rules {
+		id, enabled := ruleID(rule)
+		if !enabled {
+			continue
+		}
+		active[id] = true
+		if w, ok := workers[id]; ok {
+			w.rule.Store(rule)
+			continue
+		}
+		w := &ruleWorker[R]{
+			e:    newEvaluator(),
+			stop: make(chan struct{}),
+			done: make(chan struct{}),
+		}
+		w.rule.Store(rule)
+		workers[id] = w
+		go w.run()
+	}
+	for id, w := range workers {
+		if !active[id] {
+			w.close()
+			delete(workers, id)
+		}
+	}
+}
+
+func (w *ruleWorker[R]) run() {
+	defer close(w.done)
+	scheduled := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.stop:
+			w.e.close(w.rule.Load())
+			return
+		case <-timer.C:
+		}
+		rule := w.rule.Load()
+		w.e.eval(rule, scheduled)
+		scheduled = scheduled.Add(w.e.interval(rule))
+		if now := time.Now(); scheduled.Before(now) {
+			scheduled = now // evaluations which took longer than interval are skipped
+		}
+		timer.Reset(time.Until(scheduled))
+	}
+}
+
+func (w *ruleWorker[R]) close() {
+	close(w.stop)
+	<-w.done
+}
+
+// instant query, every returned series is a sample
+func (m *ruleManager) query(ctx context.Context, expr string, now time.Time) ([]ruleSample, error) {
+	ai := accessInfo{
+		user:              ruleUser,
+		service:           true,
+		insecureMode:      m.h.insecureMode,
+		protectedPrefixes: m.h.protectedMetricPrefixes,
+		bitViewDefault:    true,
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.h.querySelectTimeout)
+	defer cancel()
+	v, cleanup, err := m.h.promEngine.Exec(
+		withAccessInfo(ctx, &ai),
+		promql.Query{
+			Start: now.Unix(),
+			End:   now.Unix() + 1, // handler expects half open interval [start, end)
+			Expr:  expr,
+			Options: promql.Options{
+				Mode:    data_model.InstantQuery,
+				Compat:  true,
+				TimeNow: now.Unix(),
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	ts, _ := v.(*promql.TimeSeries)
+	if ts == nil {
+		return nil, errRuleNotVector
+	}
+	var res []ruleSample
+	for _, d := range ts.Series.Data {
+		value, ok := ruleSampleValue(*d.Values)
+		if !ok {
+			continue
+		}
+		lbs := make(map[string]string, len(d.Tags.ID2Tag))
+		for _, tag := range d.Tags.ID2Tag {
+			if len(tag.SValue) == 0 || tag.SValue == format.TagValueCodeZero || tag.ID == labels.MetricName || tag.ID == promql.LabelWhat {
+				continue
+			}
+			lbs[tag.GetName()] = tag.SValue
+		}
+		res = append(res, ruleSample{labels: lbs, value: value})
+	}
+	return res, nil
+}
+
+// last point of instant query result, NaN means no sample like in Prometheus
+func ruleSampleValue(values []float64) (float64, bool) {
+	for i := len(values) - 1; i >= 0; i-- {
+		if math.Float64bits(values[i]) == promql.NilValueBits {
+			continue
+		}
+		if math.IsNaN(values[i]) {
+			return 0, false
+		}
+		return values[i], true
+	}
+	return 0, false
+}
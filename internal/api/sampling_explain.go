@@ -0,0 +1,137 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+// Sampling explain asks agents and aggregators listed in --sampling-explain-addr why they
+// selected sample factors (reported as "__src_sampling_factor" and "__agg_sampling_factor") for metric or namespace.
+// First request arms explain mode on every source, so explanations appear after next sampler runs.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+//go:generate easyjson -no_std_marshalers sampling_explain.go
+
+const samplingExplainTimeout = 5 * time.Second
+
+type (
+	//easyjson:json
+	SamplingExplainResp struct {
+		ArmedFor int64                   `json:"armed_for"` // seconds explain mode stays on after request
+		Sources  []SamplingExplainSource `json:"sources"`
+	}
+
+	SamplingExplainSource struct {
+		Address      string                `json:"address"`
+		Error        string                `json:"error,omitempty"`
+		Explanations []SamplingExplanation `json:"explanations"`
+	}
+
+	SamplingExplanation struct {
+		data_model.SamplerExplanation
+		MetricName    string `json:"metric_name"`
+		NamespaceName string `json:"namespace_name,omitempty"`
+	}
+)
+
+func (h *Handler) handleGetSamplingExplain(ctx context.Context, ai accessInfo, metricWithNamespace string, namespace string) (*SamplingExplainResp, error) {
+	if len(h.samplingExplainAddrs) == 0 {
+		return nil, httpErr(http.StatusNotImplemented, fmt.Errorf("no agents or aggregators configured, see --sampling-explain-addr"))
+	}
+	var args tlstatshouse.GetSamplingExplain
+	if metricWithNamespace != "" {
+		metricMeta, err := h.getMetricMeta(ai, metricWithNamespace)
+		if err != nil {
+			return nil, err
+		}
+		args.Metric = metricMeta.MetricID
+	} else {
+		ns := h.metricsStorage.GetNamespaceByName(namespace)
+		if ns == nil {
+			return nil, httpErr(http.StatusNotFound, fmt.Errorf("namespace %q not found", namespace))
+		}
+		args.Namespace = ns.ID
+	}
+	resp := &SamplingExplainResp{
+		ArmedFor: int64(data_model.SamplerExplainArmPeriod / time.Second),
+		Sources:  make([]SamplingExplainSource, len(h.samplingExplainAddrs)),
+	}
+	ctx, cancel := context.WithTimeout(ctx, samplingExplainTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, addr := range h.samplingExplainAddrs {
+		wg.Add(1)
+		go func(s *SamplingExplainSource, addr string) {
+			defer wg.Done()
+			s.Address = addr
+			s.Explanations = []SamplingExplanation{}
+			explanations, err := h.loadSamplingExplain(ctx, addr, args)
+			if err != nil {
+				s.Error = err.Error()
+				return
+			}
+			for _, e := range explanations {
+				v := SamplingExplanation{SamplerExplanation: e}
+				if meta := h.metricsStorage.GetMetaMetric(e.MetricID); meta != nil {
+					if !ai.CanViewMetric(*meta) {
+						continue
+					}
+					v.MetricName = meta.Name
+				} else if meta = format.BuiltinMetrics[e.MetricID]; meta != nil {
+					v.MetricName = meta.Name
+				}
+				if ns := h.metricsStorage.GetNamespace(e.NamespaceID); ns != nil {
+					v.NamespaceName = ns.Name
+				}
+				s.Explanations = append(s.Explanations, v)
+			}
+		}(&resp.Sources[i], addr)
+	}
+	wg.Wait()
+	return resp, nil
+}
+
+func (h *Handler) loadSamplingExplain(ctx context.Context, addr string, args tlstatshouse.GetSamplingExplain) ([]data_model.SamplerExplanation, error) {
+	client := tlstatshouse.Client{
+		Client:  h.rpcClient,
+		Network: "tcp4",
+		Address: addr,
+	}
+	var ret string
+	if err := client.GetSamplingExplain(ctx, args, nil, &ret); err != nil {
+		return nil, err
+	}
+	var res []data_model.SamplerExplanation
+	if err := json.Unmarshal([]byte(ret), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse explanations: %w", err)
+	}
+	return res, nil
+}
+
+func (h *Handler) HandleGetSamplingExplain(w http.ResponseWriter, r *http.Request) {
+	sl := newEndpointStatHTTP(EndpointSamplingExplain, r.Method, 0, "", r.FormValue(paramPriority))
+	ai, err := h.parseAccessToken(r, sl)
+	if err != nil {
+		respondJSON(w, nil, 0, 0, err, h.verbose, ai.user, sl)
+		return
+	}
+	var metric string
+	if r.FormValue(ParamMetric) != "" {
+		metric = formValueParamMetric(r)
+	}
+	resp, err := h.handleGetSamplingExplain(r.Context(), ai, metric, r.FormValue(ParamNamespace))
+	respondJSON(w, resp, 0, 0, err, h.verbose, ai.user, sl)
+}
@@ -0,0 +1,448 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package api
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	data_model "github.com/vkcom/statshouse/internal/data_model"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi(in *jlexer.Lexer, out *SamplingExplainResp) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "armed_for":
+			out.ArmedFor = int64(in.Int64())
+		case "sources":
+			if in.IsNull() {
+				in.Skip()
+				out.Sources = nil
+			} else {
+				in.Delim('[')
+				if out.Sources == nil {
+					if !in.IsDelim(']') {
+						out.Sources = make([]SamplingExplainSource, 0, 1)
+					} else {
+						out.Sources = []SamplingExplainSource{}
+					}
+				} else {
+					out.Sources = (out.Sources)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 SamplingExplainSource
+					easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi1(in, &v1)
+					out.Sources = append(out.Sources, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi(out *jwriter.Writer, in SamplingExplainResp) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"armed_for\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ArmedFor))
+	}
+	{
+		const prefix string = ",\"sources\":"
+		out.RawString(prefix)
+		if in.Sources == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Sources {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi1(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SamplingExplainResp) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SamplingExplainResp) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi(l, v)
+}
+func easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi1(in *jlexer.Lexer, out *SamplingExplainSource) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "address":
+			out.Address = string(in.String())
+		case "error":
+			out.Error = string(in.String())
+		case "explanations":
+			if in.IsNull() {
+				in.Skip()
+				out.Explanations = nil
+			} else {
+				in.Delim('[')
+				if out.Explanations == nil {
+					if !in.IsDelim(']') {
+						out.Explanations = make([]SamplingExplanation, 0, 0)
+					} else {
+						out.Explanations = []SamplingExplanation{}
+					}
+				} else {
+					out.Explanations = (out.Explanations)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 SamplingExplanation
+					easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi2(in, &v4)
+					out.Explanations = append(out.Explanations, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi1(out *jwriter.Writer, in SamplingExplainSource) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"address\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Address))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	{
+		const prefix string = ",\"explanations\":"
+		out.RawString(prefix)
+		if in.Explanations == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Explanations {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi2(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson74be4c33DecodeGithubComVkcomStatshouseInternalApi2(in *jlexer.Lexer, out *SamplingExplanation) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "metric_name":
+			out.MetricName = string(in.String())
+		case "namespace_name":
+			out.NamespaceName = string(in.String())
+		case "time":
+			out.Time = uint32(in.Uint32())
+		case "metric":
+			out.MetricID = int32(in.Int32())
+		case "namespace":
+			out.NamespaceID = int32(in.Int32())
+		case "group":
+			out.GroupID = int32(in.Int32())
+		case "sf":
+			out.SF = float64(in.Float64())
+		case "items":
+			out.Items = int(in.Int())
+		case "whales":
+			out.Whales = int(in.Int())
+		case "fair_keys":
+			out.FairKeys = int(in.Int())
+		case "fair_keys_sampled":
+			out.FairKeysSampled = int(in.Int())
+		case "no_sample_agent":
+			out.NoSampleAgent = bool(in.Bool())
+		case "steps":
+			if in.IsNull() {
+				in.Skip()
+				out.Steps = nil
+			} else {
+				in.Delim('[')
+				if out.Steps == nil {
+					if !in.IsDelim(']') {
+						out.Steps = make([]data_model.SamplerExplainStep, 0, 0)
+					} else {
+						out.Steps = []data_model.SamplerExplainStep{}
+					}
+				} else {
+					out.Steps = (out.Steps)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 data_model.SamplerExplainStep
+					easyjson74be4c33DecodeGithubComVkcomStatshouseInternalDataModel(in, &v7)
+					out.Steps = append(out.Steps, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson74be4c33EncodeGithubComVkcomStatshouseInternalApi2(out *jwriter.Writer, in SamplingExplanation) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"metric_name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.MetricName))
+	}
+	if in.NamespaceName != "" {
+		const prefix string = ",\"namespace_name\":"
+		out.RawString(prefix)
+		out.String(string(in.NamespaceName))
+	}
+	{
+		const prefix string = ",\"time\":"
+		out.RawString(prefix)
+		out.Uint32(uint32(in.Time))
+	}
+	{
+		const prefix string = ",\"metric\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.MetricID))
+	}
+	{
+		const prefix string = ",\"namespace\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.NamespaceID))
+	}
+	{
+		const prefix string = ",\"group\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.GroupID))
+	}
+	{
+		const prefix string = ",\"sf\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.SF))
+	}
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix)
+		out.Int(int(in.Items))
+	}
+	{
+		const prefix string = ",\"whales\":"
+		out.RawString(prefix)
+		out.Int(int(in.Whales))
+	}
+	if in.FairKeys != 0 {
+		const prefix string = ",\"fair_keys\":"
+		out.RawString(prefix)
+		out.Int(int(in.FairKeys))
+	}
+	if in.FairKeysSampled != 0 {
+		const prefix string = ",\"fair_keys_sampled\":"
+		out.RawString(prefix)
+		out.Int(int(in.FairKeysSampled))
+	}
+	if in.NoSampleAgent {
+		const prefix string = ",\"no_sample_agent\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.NoSampleAgent))
+	}
+	{
+		const prefix string = ",\"steps\":"
+		out.RawString(prefix)
+		if in.Steps == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Steps {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson74be4c33EncodeGithubComVkcomStatshouseInternalDataModel(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson74be4c33DecodeGithubComVkcomStatshouseInternalDataModel(in *jlexer.Lexer, out *data_model.SamplerExplainStep) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "level":
+			out.Level = string(in.String())
+		case "id":
+			out.ID = int32(in.Int32())
+		case "weight":
+			out.Weight = int64(in.Int64())
+		case "sum_size":
+			out.SumSize = int64(in.Int64())
+		case "budget":
+			out.Budget = int64(in.Int64())
+		case "sum_weight":
+			out.SumWeight = int64(in.Int64())
+		case "share":
+			out.Share = float64(in.Float64())
+		case "sampled":
+			out.Sampled = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson74be4c33EncodeGithubComVkcomStatshouseInternalDataModel(out *jwriter.Writer, in data_model.SamplerExplainStep) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"level\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Level))
+	}
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.ID))
+	}
+	{
+		const prefix string = ",\"weight\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Weight))
+	}
+	{
+		const prefix string = ",\"sum_size\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.SumSize))
+	}
+	{
+		const prefix string = ",\"budget\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Budget))
+	}
+	{
+		const prefix string = ",\"sum_weight\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.SumWeight))
+	}
+	{
+		const prefix string = ",\"share\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Share))
+	}
+	{
+		const prefix string = ",\"sampled\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Sampled))
+	}
+	out.RawByte('}')
+}
@@ -85,6 +85,9 @@ const (
 
 	InternalLogInsertInterval = 5 * time.Second
 
+	MetricPurgeDelay         = 7 * 24 * time.Hour // deleted metric can be restored before its data is deleted
+	MetricPurgeCheckInterval = time.Minute
+
 	RPCErrorMissedRecentConveyor = -5001 // just send again through historic
 	RPCErrorInsert               = -5002 // just send again through historic (for recent), or send again after delay (for historic)
 	RPCErrorNoAutoCreate         = -5004 // just live with it, this is normal
@@ -7,6 +7,8 @@
 package data_model
 
 import (
+	"math"
+	"sort"
 	"sync"
 
 	"github.com/vkcom/statshouse/internal/format"
@@ -26,17 +28,77 @@ type Estimator struct {
 	hour     map[uint32]map[int32]*ChUnique // estimator per hour
 	halfHour map[uint32]map[int32]*ChUnique // estimator per hour, but shifted by 30 minutes
 
+	// per tag estimators for cardinality explorer, only for current hour, reported not more often than once per minute
+	tagHour         map[[2]int32]*tagEstimator // [metric, tag index]
+	tagHourTime     uint32
+	tagReportMinute uint32
+
 	window         uint32
 	maxCardinality float64
 }
 
-func updateEstimate(e map[int32]*ChUnique, metric int32, hash uint64) {
+type tagEstimator struct {
+	values ChUnique
+	top    map[int32]float64 // Space-Saving algorithm, tag value -> # of distinct series during hour, overestimated by not more than evicted count
+	total  float64
+}
+
+const (
+	tagEstimatorTopCapacity = 16
+	tagEstimatorTopReport   = 5
+	tagEstimatorTopMinSize  = 100  // tags with less values cannot explode cardinality, so top values are not interesting
+	tagEstimatorMaxCount    = 4096 // per hour, tags of metrics appeared later are not estimated
+)
+
+// values are inserted for every series, top and total count only series not seen during hour
+func (t *tagEstimator) insert(value int32, newSeries bool) {
+	t.values.Insert(uint64(uint32(value)))
+	if !newSeries {
+		return
+	}
+	t.total++
+	if _, ok := t.top[value]; ok || len(t.top) < tagEstimatorTopCapacity {
+		t.top[value]++
+		return
+	}
+	minValue, minCount := int32(0), math.MaxFloat64
+	for v, c := range t.top {
+		if c < minCount || (c == minCount && v < minValue) { // deterministic
+			minValue, minCount = v, c
+		}
+	}
+	delete(t.top, minValue)
+	t.top[value] = minCount + 1
+}
+
+// returns not more than n values with the largest # of series, sorted by count descending
+func (t *tagEstimator) topValues(n int) []int32 {
+	res := make([]int32, 0, len(t.top))
+	for v := range t.top {
+		res = append(res, v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if t.top[res[i]] != t.top[res[j]] {
+			return t.top[res[i]] > t.top[res[j]]
+		}
+		return res[i] < res[j]
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// returns true if series was not seen before (and was not thinned out by estimator)
+func updateEstimate(e map[int32]*ChUnique, metric int32, hash uint64) bool {
 	u, ok := e[metric]
 	if !ok {
 		u = &ChUnique{}
 		e[metric] = u
 	}
+	n := u.ItemsCount()
 	u.Insert(hash)
+	return u.ItemsCount() > n
 }
 
 // Will cause divide by 0 if forgotten
@@ -51,11 +113,42 @@ func (e *Estimator) UpdateWithKeys(time uint32, keys []Key) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	ah, bh := e.createEstimatorsLocked(time)
+	th := e.createTagEstimatorsLocked(time)
 	for _, key := range keys {
 		hash := key.Hash()
-		updateEstimate(ah, key.Metric, hash)
+		isNew := updateEstimate(ah, key.Metric, hash) // keys are sent every second, count each series once per hour
 		updateEstimate(bh, key.Metric, hash)
+		if key.Metric < 0 || th == nil {
+			continue // built-in metrics have fixed tags, historic keys are not interesting
+		}
+		for i, v := range key.Keys {
+			if v == 0 {
+				continue
+			}
+			t, ok := th[[2]int32{key.Metric, int32(i)}]
+			if !ok {
+				if len(th) >= tagEstimatorMaxCount {
+					continue
+				}
+				t = &tagEstimator{top: map[int32]float64{}}
+				th[[2]int32{key.Metric, int32(i)}] = t
+			}
+			t.insert(v, isNew) // series estimator thins out above 64K series, so values must not depend on isNew
+		}
+	}
+}
+
+// only current hour is reported, so previous hour is dropped and historic keys are not estimated (nil is returned)
+func (e *Estimator) createTagEstimatorsLocked(time uint32) map[[2]int32]*tagEstimator {
+	tp := time / e.window
+	if tp < e.tagHourTime {
+		return nil
 	}
+	if tp > e.tagHourTime || e.tagHour == nil {
+		e.tagHour = map[[2]int32]*tagEstimator{}
+		e.tagHourTime = tp
+	}
+	return e.tagHour
 }
 
 func (e *Estimator) createEstimatorsLocked(time uint32) (map[int32]*ChUnique, map[int32]*ChUnique) {
@@ -112,6 +205,31 @@ func (e *Estimator) ReportHourCardinality(time uint32, usedMetrics map[int32]str
 	}
 }
 
+// ReportHourTagCardinality writes distinct values estimate and top values for each tag of metrics seen during current hour.
+// Unlike ReportHourCardinality, estimates are not multiplied by # of shards, because tag values are often shared between shards.
+func (e *Estimator) ReportHourTagCardinality(time uint32, builtInStat *map[Key]*MultiItem, aggregatorHost int32, shardKey int32, replicaKey int32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if time/60 <= e.tagReportMinute {
+		return
+	}
+	e.tagReportMinute = time / 60
+	ts := (time / 60) * 60
+	for k, t := range e.createTagEstimatorsLocked(time) {
+		size := float64(t.values.Size(true))
+		key := AggKey(ts, format.BuiltinMetricIDAggHourTagCardinality, [16]int32{0, k[0], k[1]}, aggregatorHost, shardKey, replicaKey)
+		MapKeyItemMultiItem(builtInStat, key, AggregatorStringTopCapacity, nil, nil).Tail.AddValueCounterHost(size, 1, aggregatorHost)
+		if size < tagEstimatorTopMinSize {
+			continue
+		}
+		for _, v := range t.topValues(tagEstimatorTopReport) {
+			key = AggKey(ts, format.BuiltinMetricIDAggHourTagTopValues, [16]int32{0, k[0], k[1], v}, aggregatorHost, shardKey, replicaKey)
+			MapKeyItemMultiItem(builtInStat, key, AggregatorStringTopCapacity, nil, nil).Tail.AddValueCounterHost(t.top[v]/t.total, 1, aggregatorHost)
+		}
+	}
+}
+
 func (e *Estimator) GarbageCollect(oldestTime uint32) {
 	// We repeat algorithm in createEstimatorsLocked for last timestamp we accept
 	e.mu.Lock()
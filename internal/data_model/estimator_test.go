@@ -0,0 +1,103 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package data_model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestEstimatorTagCardinality(t *testing.T) {
+	const ts = 3600 * 100
+	var e Estimator
+	e.Init(3600, 1000)
+	var keys []Key
+	for i := int32(1); i <= 200; i++ {
+		keys = append(keys, Key{Timestamp: ts, Metric: 1, Keys: [format.MaxTags]int32{1, i, 7}})
+	}
+	for i := int32(1); i <= 50; i++ { // value 7 of tag 1 contributes most series
+		keys = append(keys, Key{Timestamp: ts, Metric: 1, Keys: [format.MaxTags]int32{1, 7, i}})
+	}
+	e.UpdateWithKeys(ts, keys)
+
+	stat := map[Key]*MultiItem{}
+	e.ReportHourTagCardinality(ts, &stat, 0, 1, 1)
+	distinct := map[int32]float64{}
+	var top []int32
+	for k, v := range stat {
+		require.Equal(t, int32(1), k.Keys[1])
+		require.Equal(t, uint32(ts), k.Timestamp)
+		switch k.Metric {
+		case format.BuiltinMetricIDAggHourTagCardinality:
+			distinct[k.Keys[2]] = v.Tail.Value.ValueMax
+		case format.BuiltinMetricIDAggHourTagTopValues:
+			require.Equal(t, int32(1), k.Keys[2]) // only tag with many values
+			if v.Tail.Value.ValueMax > 0.2 {
+				top = append(top, k.Keys[3])
+			}
+		}
+	}
+	require.Equal(t, map[int32]float64{0: 1, 1: 200, 2: 50}, distinct)
+	require.Equal(t, []int32{7}, top)
+
+	stat = map[Key]*MultiItem{}
+	e.ReportHourTagCardinality(ts+30, &stat, 0, 1, 1)
+	require.Empty(t, stat) // once per minute
+}
+
+func TestEstimatorTagCardinalitySeriesOncePerHour(t *testing.T) {
+	const ts = 3600 * 100
+	var e Estimator
+	e.Init(3600, 1000)
+	var few, many []Key
+	for i := int32(1); i <= 10; i++ { // value 9 of tag 1 has few series sent every second
+		few = append(few, Key{Timestamp: ts, Metric: 1, Keys: [format.MaxTags]int32{1, 9, i}})
+	}
+	for i := int32(1); i <= 200; i++ {
+		many = append(many, Key{Timestamp: ts, Metric: 1, Keys: [format.MaxTags]int32{1, 100 + i, 7}})
+	}
+	for i := uint32(0); i < 100; i++ {
+		e.UpdateWithKeys(ts+i, few)
+	}
+	te := e.tagHour[[2]int32{1, 1}]
+	require.Equal(t, float64(10), te.total)
+	require.Equal(t, float64(10), te.top[9])
+
+	e.UpdateWithKeys(ts+100, many)
+	e.UpdateWithKeys(ts-3600, many) // historic keys are not estimated
+	require.Len(t, e.tagHour, 3)
+	require.Equal(t, float64(210), te.total)
+
+	e.UpdateWithKeys(ts+3600, few) // next hour drops previous
+	require.Equal(t, float64(10), e.tagHour[[2]int32{1, 1}].total)
+}
+
+func TestEstimatorTagCardinalityManySeries(t *testing.T) {
+	const ts = 3600 * 100
+	const n = 100000 // more than series estimator keeps before thinning out
+	var e Estimator
+	e.Init(3600, 1000000)
+	keys := make([]Key, 0, n)
+	for i := int32(1); i <= n; i++ {
+		keys = append(keys, Key{Timestamp: ts, Metric: 1, Keys: [format.MaxTags]int32{1, i, i%10 + 1}})
+	}
+	e.UpdateWithKeys(ts, keys)
+
+	stat := map[Key]*MultiItem{}
+	e.ReportHourTagCardinality(ts, &stat, 0, 1, 1)
+	distinct := map[int32]float64{}
+	for k, v := range stat {
+		if k.Metric == format.BuiltinMetricIDAggHourTagCardinality {
+			distinct[k.Keys[2]] = v.Tail.Value.ValueMax
+		}
+	}
+	require.InEpsilon(t, float64(n), distinct[1], 0.05)
+	require.Equal(t, float64(10), distinct[2])
+}
@@ -101,6 +101,7 @@ const (
 	MetadataGetTagMappingBootstrap               = 0x5fc81a9b // metadata.getTagMappingBootstrap
 	MetadataHistoryShortResponse                 = 0x7186baaf // metadata.history_short_response
 	MetadataHistoryShortResponseEvent            = 0x1186baaf // metadata.history_short_response_event
+	MetadataMappingInfo                          = 0x0761ac2b // metadata.mappingInfo
 	MetadataMetricOld                            = 0x9286abfa // metadata.metricOld
 	MetadataPutBootstrapEvent                    = 0x5854dfaf // metadata.putBootstrapEvent
 	MetadataPutMapping                           = 0x9faf5281 // metadata.putMapping
@@ -111,6 +112,8 @@ const (
 	MetadataResetFlood2                          = 0x88d0fd5e // metadata.resetFlood2
 	MetadataResetFloodResponse                   = 0x9286abee // metadata.resetFloodResponse
 	MetadataResetFloodResponse2                  = 0x9286abef // metadata.resetFloodResponse2
+	MetadataSearchMappings                       = 0x1e4f35a8 // metadata.searchMappings
+	MetadataSearchMappingsResponse               = 0x88864913 // metadata.searchMappingsResponse
 	NetPid                                       = 0x46409ccf // net.pid
 	Pair                                         = 0x0f3c47ab // pair
 	ResultFalse                                  = 0x27930a7b // resultFalse
@@ -124,6 +127,7 @@ const (
 	StatshouseGetConfigResult                    = 0x0c803d07 // statshouse.getConfigResult
 	StatshouseGetMetrics3                        = 0x42855554 // statshouse.getMetrics3
 	StatshouseGetMetricsResult                   = 0x0c803d05 // statshouse.getMetricsResult
+	StatshouseGetSamplingExplain                 = 0x64418cf9 // statshouse.getSamplingExplain
 	StatshouseGetTagMapping2                     = 0x4285ff56 // statshouse.getTagMapping2
 	StatshouseGetTagMappingBootstrap             = 0x75a7f68e // statshouse.getTagMappingBootstrap
 	StatshouseGetTagMappingBootstrapResult       = 0x486a40de // statshouse.getTagMappingBootstrapResult
@@ -144,6 +148,8 @@ const (
 	StatshouseTestConnection2                    = 0x4285ff58 // statshouse.testConnection2
 	StatshouseTopElement                         = 0x9ffdea42 // statshouse.top_element
 	StatshouseApiChunkResponse                   = 0x63928b42 // statshouseApi.chunkResponse
+	StatshouseApiCreateAnnotation                = 0xff647093 // statshouseApi.createAnnotation
+	StatshouseApiCreateAnnotationResponse        = 0xd0c49c5b // statshouseApi.createAnnotationResponse
 	StatshouseApiFilter                          = 0x511276a6 // statshouseApi.filter
 	StatshouseApiFlagAuto                        = 0x2a6e4c14 // statshouseApi.flagAuto
 	StatshouseApiFlagMapped                      = 0x670ab89c // statshouseApi.flagMapped
@@ -193,6 +199,8 @@ const (
 	StatshouseApiQueryResponse                   = 0x4487e49a // statshouseApi.queryResponse
 	StatshouseApiReleaseChunks                   = 0x62adc773 // statshouseApi.releaseChunks
 	StatshouseApiReleaseChunksResponse           = 0xd12dc2bd // statshouseApi.releaseChunksResponse
+	StatshouseApiRollbackEntity                  = 0xe5beca1d // statshouseApi.rollbackEntity
+	StatshouseApiRollbackEntityResponse          = 0x2b14f314 // statshouseApi.rollbackEntityResponse
 	StatshouseApiSeries                          = 0x07a3e919 // statshouseApi.series
 	StatshouseApiSeriesMeta                      = 0x5c2bf286 // statshouseApi.seriesMeta
 	StatshouseApiTagValue                        = 0x43eeb763 // statshouseApi.tagValue
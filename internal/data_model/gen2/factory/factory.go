@@ -107,6 +107,7 @@ func init() {
 	meta.SetGlobalFactoryCreateForFunction(0x9dfa7a83, func() meta.Object { var ret internal.MetadataGetMapping; return &ret }, func() meta.Function { var ret internal.MetadataGetMapping; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForFunction(0x93ba92f5, func() meta.Object { var ret internal.MetadataGetMetrics; return &ret }, func() meta.Function { var ret internal.MetadataGetMetrics; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForFunction(0x5fc81a9b, func() meta.Object { var ret internal.MetadataGetTagMappingBootstrap; return &ret }, func() meta.Function { var ret internal.MetadataGetTagMappingBootstrap; return &ret }, nil)
+	meta.SetGlobalFactoryCreateForObject(0x0761ac2b, func() meta.Object { var ret internal.MetadataMappingInfo; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x5854dfaf, func() meta.Object { var ret internal.MetadataPutBootstrapEvent; return &ret })
 	meta.SetGlobalFactoryCreateForFunction(0x9faf5281, func() meta.Object { var ret internal.MetadataPutMapping; return &ret }, func() meta.Function { var ret internal.MetadataPutMapping; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForObject(0x12345676, func() meta.Object { var ret internal.MetadataPutMappingEvent; return &ret })
@@ -116,6 +117,8 @@ func init() {
 	meta.SetGlobalFactoryCreateForFunction(0x88d0fd5e, func() meta.Object { var ret internal.MetadataResetFlood2; return &ret }, func() meta.Function { var ret internal.MetadataResetFlood2; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForObject(0x9286abee, func() meta.Object { var ret internal.MetadataResetFloodResponse; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x9286abef, func() meta.Object { var ret internal.MetadataResetFloodResponse2; return &ret })
+	meta.SetGlobalFactoryCreateForFunction(0x1e4f35a8, func() meta.Object { var ret internal.MetadataSearchMappings; return &ret }, func() meta.Function { var ret internal.MetadataSearchMappings; return &ret }, nil)
+	meta.SetGlobalFactoryCreateForObject(0x88864913, func() meta.Object { var ret internal.MetadataSearchMappingsResponse; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x46409ccf, func() meta.Object { var ret internal.NetPid; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x9d56e6b2, func() meta.Object { var ret internal.Stat; return &ret })
 	meta.SetGlobalFactoryCreateForFunction(0x56580239, func() meta.Object { var ret internal.StatshouseAddMetricsBatch; return &ret }, func() meta.Function { var ret internal.StatshouseAddMetricsBatch; return &ret }, nil)
@@ -168,6 +171,10 @@ func init() {
 	meta.SetGlobalFactoryCreateForObject(0xc9951bbb, func() meta.Object { var ret internal.StatshouseApiQueryPoint; return &ret })
 	meta.SetGlobalFactoryCreateForFunction(0x62adc773, func() meta.Object { var ret internal.StatshouseApiReleaseChunks; return &ret }, func() meta.Function { var ret internal.StatshouseApiReleaseChunks; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForObject(0xd12dc2bd, func() meta.Object { var ret internal.StatshouseApiReleaseChunksResponse; return &ret })
+	meta.SetGlobalFactoryCreateForFunction(0xe5beca1d, func() meta.Object { var ret internal.StatshouseApiRollbackEntity; return &ret }, func() meta.Function { var ret internal.StatshouseApiRollbackEntity; return &ret }, nil)
+	meta.SetGlobalFactoryCreateForObject(0x2b14f314, func() meta.Object { var ret internal.StatshouseApiRollbackEntityResponse; return &ret })
+	meta.SetGlobalFactoryCreateForFunction(0xff647093, func() meta.Object { var ret internal.StatshouseApiCreateAnnotation; return &ret }, func() meta.Function { var ret internal.StatshouseApiCreateAnnotation; return &ret }, nil)
+	meta.SetGlobalFactoryCreateForObject(0xd0c49c5b, func() meta.Object { var ret internal.StatshouseApiCreateAnnotationResponse; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x07a3e919, func() meta.Object { var ret internal.StatshouseApiSeries; return &ret })
 	meta.SetGlobalFactoryCreateForObject(0x43eeb763, func() meta.Object { var ret internal.StatshouseApiTagValue; return &ret })
 	meta.SetGlobalFactoryCreateForFunction(0x28bea524, func() meta.Object { var ret internal.StatshouseAutoCreate; return &ret }, func() meta.Function { var ret internal.StatshouseAutoCreate; return &ret }, nil)
@@ -175,6 +182,7 @@ func init() {
 	meta.SetGlobalFactoryCreateForFunction(0x4285ff57, func() meta.Object { var ret internal.StatshouseGetConfig2; return &ret }, func() meta.Function { var ret internal.StatshouseGetConfig2; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForFunction(0x42855554, func() meta.Object { var ret internal.StatshouseGetMetrics3; return &ret }, func() meta.Function { var ret internal.StatshouseGetMetrics3; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForObject(0x0c803d05, func() meta.Object { var ret internal.StatshouseGetMetricsResult; return &ret })
+	meta.SetGlobalFactoryCreateForFunction(0x64418cf9, func() meta.Object { var ret internal.StatshouseGetSamplingExplain; return &ret }, func() meta.Function { var ret internal.StatshouseGetSamplingExplain; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForFunction(0x4285ff56, func() meta.Object { var ret internal.StatshouseGetTagMapping2; return &ret }, func() meta.Function { var ret internal.StatshouseGetTagMapping2; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForFunction(0x75a7f68e, func() meta.Object { var ret internal.StatshouseGetTagMappingBootstrap; return &ret }, func() meta.Function { var ret internal.StatshouseGetTagMappingBootstrap; return &ret }, nil)
 	meta.SetGlobalFactoryCreateForObject(0x486a40de, func() meta.Object { var ret internal.StatshouseGetTagMappingBootstrapResult; return &ret })
@@ -299,6 +299,7 @@ func init() {
 	fillFunction("metadata.getMapping#9dfa7a83", "#9dfa7a83", &TLItem{tag: 0x9dfa7a83, annotations: 0x8, tlName: "metadata.getMapping"})
 	fillFunction("metadata.getMetrics#93ba92f5", "#93ba92f5", &TLItem{tag: 0x93ba92f5, annotations: 0x4, tlName: "metadata.getMetrics"})
 	fillFunction("metadata.getTagMappingBootstrap#5fc81a9b", "#5fc81a9b", &TLItem{tag: 0x5fc81a9b, annotations: 0x8, tlName: "metadata.getTagMappingBootstrap"})
+	fillObject("metadata.mappingInfo#0761ac2b", "#0761ac2b", &TLItem{tag: 0x761ac2b, annotations: 0x0, tlName: "metadata.mappingInfo"})
 	fillObject("metadata.putBootstrapEvent#5854dfaf", "#5854dfaf", &TLItem{tag: 0x5854dfaf, annotations: 0x0, tlName: "metadata.putBootstrapEvent"})
 	fillFunction("metadata.putMapping#9faf5281", "#9faf5281", &TLItem{tag: 0x9faf5281, annotations: 0x8, tlName: "metadata.putMapping"})
 	fillObject("metadata.putMappingEvent#12345676", "#12345676", &TLItem{tag: 0x12345676, annotations: 0x0, tlName: "metadata.putMappingEvent"})
@@ -308,6 +309,8 @@ func init() {
 	fillFunction("metadata.resetFlood2#88d0fd5e", "#88d0fd5e", &TLItem{tag: 0x88d0fd5e, annotations: 0x8, tlName: "metadata.resetFlood2"})
 	fillObject("metadata.resetFloodResponse#9286abee", "#9286abee", &TLItem{tag: 0x9286abee, annotations: 0x0, tlName: "metadata.resetFloodResponse"})
 	fillObject("metadata.resetFloodResponse2#9286abef", "#9286abef", &TLItem{tag: 0x9286abef, annotations: 0x0, tlName: "metadata.resetFloodResponse2"})
+	fillFunction("metadata.searchMappings#1e4f35a8", "#1e4f35a8", &TLItem{tag: 0x1e4f35a8, annotations: 0x4, tlName: "metadata.searchMappings"})
+	fillObject("metadata.searchMappingsResponse#88864913", "#88864913", &TLItem{tag: 0x88864913, annotations: 0x0, tlName: "metadata.searchMappingsResponse"})
 	fillObject("net.pid#46409ccf", "#46409ccf", &TLItem{tag: 0x46409ccf, annotations: 0x0, tlName: "net.pid"})
 	fillObject("stat#9d56e6b2", "#9d56e6b2", &TLItem{tag: 0x9d56e6b2, annotations: 0x0, tlName: "stat"})
 	fillFunction("statshouse.addMetricsBatch#56580239", "#56580239", &TLItem{tag: 0x56580239, annotations: 0x10, tlName: "statshouse.addMetricsBatch"})
@@ -360,6 +363,10 @@ func init() {
 	fillObject("statshouseApi.queryPoint#c9951bbb", "#c9951bbb", &TLItem{tag: 0xc9951bbb, annotations: 0x0, tlName: "statshouseApi.queryPoint"})
 	fillFunction("statshouseApi.releaseChunks#62adc773", "#62adc773", &TLItem{tag: 0x62adc773, annotations: 0x10, tlName: "statshouseApi.releaseChunks"})
 	fillObject("statshouseApi.releaseChunksResponse#d12dc2bd", "#d12dc2bd", &TLItem{tag: 0xd12dc2bd, annotations: 0x0, tlName: "statshouseApi.releaseChunksResponse"})
+	fillFunction("statshouseApi.rollbackEntity#e5beca1d", "#e5beca1d", &TLItem{tag: 0xe5beca1d, annotations: 0x10, tlName: "statshouseApi.rollbackEntity"})
+	fillObject("statshouseApi.rollbackEntityResponse#2b14f314", "#2b14f314", &TLItem{tag: 0x2b14f314, annotations: 0x0, tlName: "statshouseApi.rollbackEntityResponse"})
+	fillFunction("statshouseApi.createAnnotation#ff647093", "#ff647093", &TLItem{tag: 0xff647093, annotations: 0x10, tlName: "statshouseApi.createAnnotation"})
+	fillObject("statshouseApi.createAnnotationResponse#d0c49c5b", "#d0c49c5b", &TLItem{tag: 0xd0c49c5b, annotations: 0x0, tlName: "statshouseApi.createAnnotationResponse"})
 	fillObject("statshouseApi.series#07a3e919", "#07a3e919", &TLItem{tag: 0x7a3e919, annotations: 0x0, tlName: "statshouseApi.series"})
 	fillObject("statshouseApi.tagValue#43eeb763", "#43eeb763", &TLItem{tag: 0x43eeb763, annotations: 0x0, tlName: "statshouseApi.tagValue"})
 	fillFunction("statshouse.autoCreate#28bea524", "#28bea524", &TLItem{tag: 0x28bea524, annotations: 0x8, tlName: "statshouse.autoCreate"})
@@ -367,6 +374,7 @@ func init() {
 	fillFunction("statshouse.getConfig2#4285ff57", "#4285ff57", &TLItem{tag: 0x4285ff57, annotations: 0x8, tlName: "statshouse.getConfig2"})
 	fillFunction("statshouse.getMetrics3#42855554", "#42855554", &TLItem{tag: 0x42855554, annotations: 0x8, tlName: "statshouse.getMetrics3"})
 	fillObject("statshouse.getMetricsResult#0c803d05", "#0c803d05", &TLItem{tag: 0xc803d05, annotations: 0x0, tlName: "statshouse.getMetricsResult"})
+	fillFunction("statshouse.getSamplingExplain#64418cf9", "#64418cf9", &TLItem{tag: 0x64418cf9, annotations: 0x8, tlName: "statshouse.getSamplingExplain"})
 	fillFunction("statshouse.getTagMapping2#4285ff56", "#4285ff56", &TLItem{tag: 0x4285ff56, annotations: 0x8, tlName: "statshouse.getTagMapping2"})
 	fillFunction("statshouse.getTagMappingBootstrap#75a7f68e", "#75a7f68e", &TLItem{tag: 0x75a7f68e, annotations: 0x8, tlName: "statshouse.getTagMappingBootstrap"})
 	fillObject("statshouse.getTagMappingBootstrapResult#486a40de", "#486a40de", &TLItem{tag: 0x486a40de, annotations: 0x0, tlName: "statshouse.getTagMappingBootstrapResult"})
@@ -44,6 +44,7 @@ type (
 	GetTagMappingBootstrap               = internal.MetadataGetTagMappingBootstrap
 	HistoryShortResponse                 = internal.MetadataHistoryShortResponse
 	HistoryShortResponseEvent            = internal.MetadataHistoryShortResponseEvent
+	MappingInfo                          = internal.MetadataMappingInfo
 	MetricOld                            = internal.MetadataMetricOld
 	PutBootstrapEvent                    = internal.MetadataPutBootstrapEvent
 	PutMapping                           = internal.MetadataPutMapping
@@ -54,6 +55,8 @@ type (
 	ResetFlood2                          = internal.MetadataResetFlood2
 	ResetFloodResponse                   = internal.MetadataResetFloodResponse
 	ResetFloodResponse2                  = internal.MetadataResetFloodResponse2
+	SearchMappings                       = internal.MetadataSearchMappings
+	SearchMappingsResponse               = internal.MetadataSearchMappingsResponse
 )
 
 type Client struct {
@@ -355,6 +358,30 @@ func (c *Client) ResetFlood2(ctx context.Context, args ResetFlood2, extra *rpc.I
 	return nil
 }
 
+func (c *Client) SearchMappings(ctx context.Context, args SearchMappings, extra *rpc.InvokeReqExtra, ret *SearchMappingsResponse) (err error) {
+	req := c.Client.GetRequest()
+	req.ActorID = c.ActorID
+	req.FunctionName = "metadata.searchMappings"
+	if extra != nil {
+		req.Extra = *extra
+	}
+	req.Body, err = args.WriteBoxedGeneral(req.Body)
+	if err != nil {
+		return internal.ErrorClientWrite("metadata.searchMappings", err)
+	}
+	resp, err := c.Client.Do(ctx, c.Network, c.Address, req)
+	defer c.Client.PutResponse(resp)
+	if err != nil {
+		return internal.ErrorClientDo("metadata.searchMappings", c.Network, c.ActorID, c.Address, err)
+	}
+	if ret != nil {
+		if _, err = args.ReadResult(resp.Body, ret); err != nil {
+			return internal.ErrorClientReadResult("metadata.searchMappings", c.Network, c.ActorID, c.Address, err)
+		}
+	}
+	return nil
+}
+
 type Handler struct {
 	EditEntitynew          func(ctx context.Context, args EditEntitynew) (Event, error)                                                    // metadata.editEntitynew
 	GetEntity              func(ctx context.Context, args GetEntity) (Event, error)                                                        // metadata.getEntity
@@ -368,6 +395,7 @@ type Handler struct {
 	PutTagMappingBootstrap func(ctx context.Context, args PutTagMappingBootstrap) (internal.StatshousePutTagMappingBootstrapResult, error) // metadata.putTagMappingBootstrap
 	ResetFlood             func(ctx context.Context, args ResetFlood) (ResetFloodResponse, error)                                          // metadata.resetFlood
 	ResetFlood2            func(ctx context.Context, args ResetFlood2) (ResetFloodResponse2, error)                                        // metadata.resetFlood2
+	SearchMappings         func(ctx context.Context, args SearchMappings) (SearchMappingsResponse, error)                                  // metadata.searchMappings
 
 	RawEditEntitynew          func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.editEntitynew
 	RawGetEntity              func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.getEntity
@@ -381,6 +409,7 @@ type Handler struct {
 	RawPutTagMappingBootstrap func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.putTagMappingBootstrap
 	RawResetFlood             func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.resetFlood
 	RawResetFlood2            func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.resetFlood2
+	RawSearchMappings         func(ctx context.Context, hctx *rpc.HandlerContext) error // metadata.searchMappings
 }
 
 func (h *Handler) Handle(ctx context.Context, hctx *rpc.HandlerContext) (err error) {
@@ -758,6 +787,37 @@ func (h *Handler) Handle(ctx context.Context, hctx *rpc.HandlerContext) (err err
 			}
 			return nil
 		}
+	case 0x1e4f35a8: // metadata.searchMappings
+		hctx.RequestFunctionName = "metadata.searchMappings"
+		if h.RawSearchMappings != nil {
+			hctx.Request = r
+			err = h.RawSearchMappings(ctx, hctx)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("metadata.searchMappings", err)
+			}
+			return nil
+		}
+		if h.SearchMappings != nil {
+			var args SearchMappings
+			if _, err = args.Read(r); err != nil {
+				return internal.ErrorServerRead("metadata.searchMappings", err)
+			}
+			ctx = hctx.WithContext(ctx)
+			ret, err := h.SearchMappings(ctx, args)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("metadata.searchMappings", err)
+			}
+			if hctx.Response, err = args.WriteResult(hctx.Response, ret); err != nil {
+				return internal.ErrorServerWriteResult("metadata.searchMappings", err)
+			}
+			return nil
+		}
 	}
 	return rpc.ErrNoHandler
 }
@@ -31,6 +31,8 @@ type (
 	GetMetrics3Bytes                  = internal.StatshouseGetMetrics3Bytes
 	GetMetricsResult                  = internal.StatshouseGetMetricsResult
 	GetMetricsResultBytes             = internal.StatshouseGetMetricsResultBytes
+	GetSamplingExplain                = internal.StatshouseGetSamplingExplain
+	GetSamplingExplainBytes           = internal.StatshouseGetSamplingExplainBytes
 	GetTagMapping2                    = internal.StatshouseGetTagMapping2
 	GetTagMapping2Bytes               = internal.StatshouseGetTagMapping2Bytes
 	GetTagMappingBootstrap            = internal.StatshouseGetTagMappingBootstrap
@@ -266,6 +268,54 @@ func (c *Client) GetMetrics3(ctx context.Context, args GetMetrics3, extra *rpc.I
 	return nil
 }
 
+func (c *Client) GetSamplingExplainBytes(ctx context.Context, args GetSamplingExplainBytes, extra *rpc.InvokeReqExtra, ret *[]byte) (err error) {
+	req := c.Client.GetRequest()
+	req.ActorID = c.ActorID
+	req.FunctionName = "statshouse.getSamplingExplain"
+	if extra != nil {
+		req.Extra = *extra
+	}
+	req.Body, err = args.WriteBoxedGeneral(req.Body)
+	if err != nil {
+		return internal.ErrorClientWrite("statshouse.getSamplingExplain", err)
+	}
+	resp, err := c.Client.Do(ctx, c.Network, c.Address, req)
+	defer c.Client.PutResponse(resp)
+	if err != nil {
+		return internal.ErrorClientDo("statshouse.getSamplingExplain", c.Network, c.ActorID, c.Address, err)
+	}
+	if ret != nil {
+		if _, err = args.ReadResult(resp.Body, ret); err != nil {
+			return internal.ErrorClientReadResult("statshouse.getSamplingExplain", c.Network, c.ActorID, c.Address, err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) GetSamplingExplain(ctx context.Context, args GetSamplingExplain, extra *rpc.InvokeReqExtra, ret *string) (err error) {
+	req := c.Client.GetRequest()
+	req.ActorID = c.ActorID
+	req.FunctionName = "statshouse.getSamplingExplain"
+	if extra != nil {
+		req.Extra = *extra
+	}
+	req.Body, err = args.WriteBoxedGeneral(req.Body)
+	if err != nil {
+		return internal.ErrorClientWrite("statshouse.getSamplingExplain", err)
+	}
+	resp, err := c.Client.Do(ctx, c.Network, c.Address, req)
+	defer c.Client.PutResponse(resp)
+	if err != nil {
+		return internal.ErrorClientDo("statshouse.getSamplingExplain", c.Network, c.ActorID, c.Address, err)
+	}
+	if ret != nil {
+		if _, err = args.ReadResult(resp.Body, ret); err != nil {
+			return internal.ErrorClientReadResult("statshouse.getSamplingExplain", c.Network, c.ActorID, c.Address, err)
+		}
+	}
+	return nil
+}
+
 func (c *Client) GetTagMapping2Bytes(ctx context.Context, args GetTagMapping2Bytes, extra *rpc.InvokeReqExtra, ret *GetTagMappingResult) (err error) {
 	req := c.Client.GetRequest()
 	req.ActorID = c.ActorID
@@ -559,6 +609,7 @@ type Handler struct {
 	AutoCreate             func(ctx context.Context, args AutoCreate) (internal.True, error)                            // statshouse.autoCreate
 	GetConfig2             func(ctx context.Context, args GetConfig2) (GetConfigResult, error)                          // statshouse.getConfig2
 	GetMetrics3            func(ctx context.Context, args GetMetrics3) (internal.MetadataGetJournalResponsenew, error)  // statshouse.getMetrics3
+	GetSamplingExplain     func(ctx context.Context, args GetSamplingExplain) (string, error)                           // statshouse.getSamplingExplain
 	GetTagMapping2         func(ctx context.Context, args GetTagMapping2) (GetTagMappingResult, error)                  // statshouse.getTagMapping2
 	GetTagMappingBootstrap func(ctx context.Context, args GetTagMappingBootstrap) (GetTagMappingBootstrapResult, error) // statshouse.getTagMappingBootstrap
 	GetTargets2            func(ctx context.Context, args GetTargets2) (GetTargetsResult, error)                        // statshouse.getTargets2
@@ -570,6 +621,7 @@ type Handler struct {
 	RawAutoCreate             func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.autoCreate
 	RawGetConfig2             func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getConfig2
 	RawGetMetrics3            func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getMetrics3
+	RawGetSamplingExplain     func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getSamplingExplain
 	RawGetTagMapping2         func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getTagMapping2
 	RawGetTagMappingBootstrap func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getTagMappingBootstrap
 	RawGetTargets2            func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouse.getTargets2
@@ -705,6 +757,37 @@ func (h *Handler) Handle(ctx context.Context, hctx *rpc.HandlerContext) (err err
 			}
 			return nil
 		}
+	case 0x64418cf9: // statshouse.getSamplingExplain
+		hctx.RequestFunctionName = "statshouse.getSamplingExplain"
+		if h.RawGetSamplingExplain != nil {
+			hctx.Request = r
+			err = h.RawGetSamplingExplain(ctx, hctx)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouse.getSamplingExplain", err)
+			}
+			return nil
+		}
+		if h.GetSamplingExplain != nil {
+			var args GetSamplingExplain
+			if _, err = args.Read(r); err != nil {
+				return internal.ErrorServerRead("statshouse.getSamplingExplain", err)
+			}
+			ctx = hctx.WithContext(ctx)
+			ret, err := h.GetSamplingExplain(ctx, args)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouse.getSamplingExplain", err)
+			}
+			if hctx.Response, err = args.WriteResult(hctx.Response, ret); err != nil {
+				return internal.ErrorServerWriteResult("statshouse.getSamplingExplain", err)
+			}
+			return nil
+		}
 	case 0x4285ff56: // statshouse.getTagMapping2
 		hctx.RequestFunctionName = "statshouse.getTagMapping2"
 		if h.RawGetTagMapping2 != nil {
@@ -16,23 +16,27 @@ import (
 )
 
 type (
-	Filter                = internal.StatshouseApiFilter
-	Flag                  = internal.StatshouseApiFlag
-	Function              = internal.StatshouseApiFunction
-	GetChunk              = internal.StatshouseApiGetChunk
-	GetChunkResponse      = internal.StatshouseApiGetChunkResponse
-	GetQuery              = internal.StatshouseApiGetQuery
-	GetQueryPoint         = internal.StatshouseApiGetQueryPoint
-	GetQueryPointResponse = internal.StatshouseApiGetQueryPointResponse
-	GetQueryResponse      = internal.StatshouseApiGetQueryResponse
-	PointMeta             = internal.StatshouseApiPointMeta
-	Query                 = internal.StatshouseApiQuery
-	QueryPoint            = internal.StatshouseApiQueryPoint
-	ReleaseChunks         = internal.StatshouseApiReleaseChunks
-	ReleaseChunksResponse = internal.StatshouseApiReleaseChunksResponse
-	Series                = internal.StatshouseApiSeries
-	SeriesMeta            = internal.StatshouseApiSeriesMeta
-	TagValue              = internal.StatshouseApiTagValue
+	CreateAnnotation         = internal.StatshouseApiCreateAnnotation
+	CreateAnnotationResponse = internal.StatshouseApiCreateAnnotationResponse
+	Filter                   = internal.StatshouseApiFilter
+	Flag                     = internal.StatshouseApiFlag
+	Function                 = internal.StatshouseApiFunction
+	GetChunk                 = internal.StatshouseApiGetChunk
+	GetChunkResponse         = internal.StatshouseApiGetChunkResponse
+	GetQuery                 = internal.StatshouseApiGetQuery
+	GetQueryPoint            = internal.StatshouseApiGetQueryPoint
+	GetQueryPointResponse    = internal.StatshouseApiGetQueryPointResponse
+	GetQueryResponse         = internal.StatshouseApiGetQueryResponse
+	PointMeta                = internal.StatshouseApiPointMeta
+	Query                    = internal.StatshouseApiQuery
+	QueryPoint               = internal.StatshouseApiQueryPoint
+	ReleaseChunks            = internal.StatshouseApiReleaseChunks
+	ReleaseChunksResponse    = internal.StatshouseApiReleaseChunksResponse
+	RollbackEntity           = internal.StatshouseApiRollbackEntity
+	RollbackEntityResponse   = internal.StatshouseApiRollbackEntityResponse
+	Series                   = internal.StatshouseApiSeries
+	SeriesMeta               = internal.StatshouseApiSeriesMeta
+	TagValue                 = internal.StatshouseApiTagValue
 )
 
 func FlagAuto() Flag                   { return internal.StatshouseApiFlagAuto() }
@@ -81,6 +85,30 @@ type Client struct {
 	ActorID int64 // should be non-zero when using rpc-proxy
 }
 
+func (c *Client) CreateAnnotation(ctx context.Context, args CreateAnnotation, extra *rpc.InvokeReqExtra, ret *CreateAnnotationResponse) (err error) {
+	req := c.Client.GetRequest()
+	req.ActorID = c.ActorID
+	req.FunctionName = "statshouseApi.createAnnotation"
+	if extra != nil {
+		req.Extra = *extra
+	}
+	req.Body, err = args.WriteBoxedGeneral(req.Body)
+	if err != nil {
+		return internal.ErrorClientWrite("statshouseApi.createAnnotation", err)
+	}
+	resp, err := c.Client.Do(ctx, c.Network, c.Address, req)
+	defer c.Client.PutResponse(resp)
+	if err != nil {
+		return internal.ErrorClientDo("statshouseApi.createAnnotation", c.Network, c.ActorID, c.Address, err)
+	}
+	if ret != nil {
+		if _, err = args.ReadResult(resp.Body, ret); err != nil {
+			return internal.ErrorClientReadResult("statshouseApi.createAnnotation", c.Network, c.ActorID, c.Address, err)
+		}
+	}
+	return nil
+}
+
 func (c *Client) GetChunk(ctx context.Context, args GetChunk, extra *rpc.InvokeReqExtra, ret *GetChunkResponse) (err error) {
 	req := c.Client.GetRequest()
 	req.ActorID = c.ActorID
@@ -179,21 +207,80 @@ func (c *Client) ReleaseChunks(ctx context.Context, args ReleaseChunks, extra *r
 	return nil
 }
 
+func (c *Client) RollbackEntity(ctx context.Context, args RollbackEntity, extra *rpc.InvokeReqExtra, ret *RollbackEntityResponse) (err error) {
+	req := c.Client.GetRequest()
+	req.ActorID = c.ActorID
+	req.FunctionName = "statshouseApi.rollbackEntity"
+	if extra != nil {
+		req.Extra = *extra
+	}
+	req.Body, err = args.WriteBoxedGeneral(req.Body)
+	if err != nil {
+		return internal.ErrorClientWrite("statshouseApi.rollbackEntity", err)
+	}
+	resp, err := c.Client.Do(ctx, c.Network, c.Address, req)
+	defer c.Client.PutResponse(resp)
+	if err != nil {
+		return internal.ErrorClientDo("statshouseApi.rollbackEntity", c.Network, c.ActorID, c.Address, err)
+	}
+	if ret != nil {
+		if _, err = args.ReadResult(resp.Body, ret); err != nil {
+			return internal.ErrorClientReadResult("statshouseApi.rollbackEntity", c.Network, c.ActorID, c.Address, err)
+		}
+	}
+	return nil
+}
+
 type Handler struct {
-	GetChunk      func(ctx context.Context, args GetChunk) (GetChunkResponse, error)           // statshouseApi.getChunk
-	GetQuery      func(ctx context.Context, args GetQuery) (GetQueryResponse, error)           // statshouseApi.getQuery
-	GetQueryPoint func(ctx context.Context, args GetQueryPoint) (GetQueryPointResponse, error) // statshouseApi.getQueryPoint
-	ReleaseChunks func(ctx context.Context, args ReleaseChunks) (ReleaseChunksResponse, error) // statshouseApi.releaseChunks
+	CreateAnnotation func(ctx context.Context, args CreateAnnotation) (CreateAnnotationResponse, error) // statshouseApi.createAnnotation
+	GetChunk         func(ctx context.Context, args GetChunk) (GetChunkResponse, error)                 // statshouseApi.getChunk
+	GetQuery         func(ctx context.Context, args GetQuery) (GetQueryResponse, error)                 // statshouseApi.getQuery
+	GetQueryPoint    func(ctx context.Context, args GetQueryPoint) (GetQueryPointResponse, error)       // statshouseApi.getQueryPoint
+	ReleaseChunks    func(ctx context.Context, args ReleaseChunks) (ReleaseChunksResponse, error)       // statshouseApi.releaseChunks
+	RollbackEntity   func(ctx context.Context, args RollbackEntity) (RollbackEntityResponse, error)     // statshouseApi.rollbackEntity
 
-	RawGetChunk      func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getChunk
-	RawGetQuery      func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getQuery
-	RawGetQueryPoint func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getQueryPoint
-	RawReleaseChunks func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.releaseChunks
+	RawCreateAnnotation func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.createAnnotation
+	RawGetChunk         func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getChunk
+	RawGetQuery         func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getQuery
+	RawGetQueryPoint    func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.getQueryPoint
+	RawReleaseChunks    func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.releaseChunks
+	RawRollbackEntity   func(ctx context.Context, hctx *rpc.HandlerContext) error // statshouseApi.rollbackEntity
 }
 
 func (h *Handler) Handle(ctx context.Context, hctx *rpc.HandlerContext) (err error) {
 	tag, r, _ := basictl.NatReadTag(hctx.Request) // keep hctx.Request intact for handler chaining
 	switch tag {
+	case 0xff647093: // statshouseApi.createAnnotation
+		hctx.RequestFunctionName = "statshouseApi.createAnnotation"
+		if h.RawCreateAnnotation != nil {
+			hctx.Request = r
+			err = h.RawCreateAnnotation(ctx, hctx)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouseApi.createAnnotation", err)
+			}
+			return nil
+		}
+		if h.CreateAnnotation != nil {
+			var args CreateAnnotation
+			if _, err = args.Read(r); err != nil {
+				return internal.ErrorServerRead("statshouseApi.createAnnotation", err)
+			}
+			ctx = hctx.WithContext(ctx)
+			ret, err := h.CreateAnnotation(ctx, args)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouseApi.createAnnotation", err)
+			}
+			if hctx.Response, err = args.WriteResult(hctx.Response, ret); err != nil {
+				return internal.ErrorServerWriteResult("statshouseApi.createAnnotation", err)
+			}
+			return nil
+		}
 	case 0x52721884: // statshouseApi.getChunk
 		hctx.RequestFunctionName = "statshouseApi.getChunk"
 		if h.RawGetChunk != nil {
@@ -318,6 +405,37 @@ func (h *Handler) Handle(ctx context.Context, hctx *rpc.HandlerContext) (err err
 			}
 			return nil
 		}
+	case 0xe5beca1d: // statshouseApi.rollbackEntity
+		hctx.RequestFunctionName = "statshouseApi.rollbackEntity"
+		if h.RawRollbackEntity != nil {
+			hctx.Request = r
+			err = h.RawRollbackEntity(ctx, hctx)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouseApi.rollbackEntity", err)
+			}
+			return nil
+		}
+		if h.RollbackEntity != nil {
+			var args RollbackEntity
+			if _, err = args.Read(r); err != nil {
+				return internal.ErrorServerRead("statshouseApi.rollbackEntity", err)
+			}
+			ctx = hctx.WithContext(ctx)
+			ret, err := h.RollbackEntity(ctx, args)
+			if rpc.IsHijackedResponse(err) {
+				return err
+			}
+			if err != nil {
+				return internal.ErrorServerHandle("statshouseApi.rollbackEntity", err)
+			}
+			if hctx.Response, err = args.WriteResult(hctx.Response, ret); err != nil {
+				return internal.ErrorServerWriteResult("statshouseApi.rollbackEntity", err)
+			}
+			return nil
+		}
 	}
 	return rpc.ErrNoHandler
 }
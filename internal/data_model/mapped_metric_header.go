@@ -18,6 +18,7 @@ type HandlerArgs struct {
 	Description    string
 	ScrapeInterval int
 	MapCallback    MapCallbackFunc
+	RetryOverload  bool // caller will retry on queue overload, so it must not be recorded in ingestion status
 }
 
 type MapCallbackFunc func(tlstatshouse.MetricBytes, MappedMetricHeader)
@@ -48,6 +49,7 @@ type MappedMetricHeader struct {
 	LegacyCanonicalTagKey int32  // +TagIDShift, as required by "tag_id" in builtin metric. If more than 1, remembers some
 	InvalidRawValue       []byte // reference to memory inside tlstatshouse.MetricBytes. If more than 1 problem, reports the last one
 	InvalidRawTagKey      int32  // key of InvalidRawValue
+	MetricDeprecated      bool
 }
 
 // TODO - implement InvalidRawValue and InvalidRawTagKey
@@ -73,3 +75,8 @@ func (h *MappedMetricHeader) SetInvalidString(ingestionStatus int32, tagIDKey in
 	h.IngestionTagKey = tagIDKey
 	h.InvalidString = invalidString
 }
+
+func IngestionStatusQueueOverload(ingestionStatus int32) bool {
+	return ingestionStatus == format.TagValueIDSrcIngestionStatusErrMapGlobalQueueOverload ||
+		ingestionStatus == format.TagValueIDSrcIngestionStatusErrMapPerMetricQueueOverload
+}
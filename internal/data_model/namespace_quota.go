@@ -0,0 +1,136 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package data_model
+
+import (
+	"sync"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+type (
+	// NamespaceQuota enforces hard limits set in format.NamespaceMeta on aggregator.
+	// Rows and bytes are limited per bucket second, cardinality per hour of bucket time.
+	// Each aggregator shard receives only its part of series, so limits are divided between shards.
+	NamespaceQuota struct {
+		shards int64
+
+		mu   sync.Mutex
+		hour uint32
+		seen map[int32]map[uint64]struct{} // namespace -> series seen during hour, never larger than cardinality quota
+	}
+
+	// NamespaceQuotaBatch accounts single insert, not thread safe
+	NamespaceQuotaBatch struct {
+		quota   *NamespaceQuota
+		seconds map[[2]int64]*namespaceQuotaSecond // [namespace, bucket time]
+		Stat    map[[2]int32]*NamespaceQuotaStat   // [namespace, quota]
+	}
+
+	NamespaceQuotaStat struct {
+		Keep    float64 // rows, bytes or series count for cardinality
+		Discard float64
+	}
+
+	namespaceQuotaSecond struct {
+		rows  int64
+		bytes int64
+	}
+)
+
+func NewNamespaceQuota(shards int) *NamespaceQuota {
+	if shards < 1 {
+		shards = 1
+	}
+	return &NamespaceQuota{shards: int64(shards), seen: map[int32]map[uint64]struct{}{}}
+}
+
+func (q *NamespaceQuota) NewBatch() *NamespaceQuotaBatch {
+	return &NamespaceQuotaBatch{
+		quota:   q,
+		seconds: map[[2]int64]*namespaceQuotaSecond{},
+		Stat:    map[[2]int32]*NamespaceQuotaStat{},
+	}
+}
+
+func (q *NamespaceQuota) shardLimit(limit int64) int64 {
+	return (limit + q.shards - 1) / q.shards
+}
+
+// returns false if series is new and cardinality quota is exhausted, otherwise remembers series
+func (q *NamespaceQuota) acceptSeries(ns *format.NamespaceMeta, hash uint64, bucketTs uint32) (bool, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	hour := bucketTs / 3600
+	if hour > q.hour {
+		q.hour = hour
+		q.seen = map[int32]map[uint64]struct{}{}
+	}
+	if hour < q.hour { // we do not keep series of past hours, historic data is limited only by rows and bytes
+		return true, 0
+	}
+	seen := q.seen[ns.ID]
+	if seen == nil {
+		seen = map[uint64]struct{}{}
+		q.seen[ns.ID] = seen
+	}
+	if _, ok := seen[hash]; ok {
+		return true, len(seen)
+	}
+	if int64(len(seen)) >= q.shardLimit(ns.QuotaCardinalityPerHour) {
+		return false, len(seen)
+	}
+	seen[hash] = struct{}{}
+	return true, len(seen)
+}
+
+// Accept returns 0 if series fits into namespace quotas, otherwise returns exceeded quota tag and series must be discarded
+func (b *NamespaceQuotaBatch) Accept(ns *format.NamespaceMeta, k *Key, rows int, size int, bucketTs uint32) int32 {
+	secondKey := [2]int64{int64(ns.ID), int64(bucketTs)}
+	second := b.seconds[secondKey]
+	if second == nil {
+		second = &namespaceQuotaSecond{}
+		b.seconds[secondKey] = second
+	}
+	if ns.QuotaRowsPerSec > 0 && second.rows+int64(rows) > b.quota.shardLimit(ns.QuotaRowsPerSec) {
+		b.stat(ns.ID, format.TagValueIDNamespaceQuotaRows).Discard += float64(rows)
+		return format.TagValueIDNamespaceQuotaRows
+	}
+	if ns.QuotaBytesPerSec > 0 && second.bytes+int64(size) > b.quota.shardLimit(ns.QuotaBytesPerSec) {
+		b.stat(ns.ID, format.TagValueIDNamespaceQuotaBytes).Discard += float64(size)
+		return format.TagValueIDNamespaceQuotaBytes
+	}
+	if ns.QuotaCardinalityPerHour > 0 {
+		ok, cardinality := b.quota.acceptSeries(ns, k.Hash(), bucketTs)
+		stat := b.stat(ns.ID, format.TagValueIDNamespaceQuotaCardinality)
+		if cardinality != 0 {
+			stat.Keep = float64(cardinality)
+		}
+		if !ok {
+			stat.Discard++
+			return format.TagValueIDNamespaceQuotaCardinality
+		}
+	}
+	second.rows += int64(rows)
+	second.bytes += int64(size)
+	if ns.QuotaRowsPerSec > 0 {
+		b.stat(ns.ID, format.TagValueIDNamespaceQuotaRows).Keep += float64(rows)
+	}
+	if ns.QuotaBytesPerSec > 0 {
+		b.stat(ns.ID, format.TagValueIDNamespaceQuotaBytes).Keep += float64(size)
+	}
+	return 0
+}
+
+func (b *NamespaceQuotaBatch) stat(namespaceID int32, quota int32) *NamespaceQuotaStat {
+	s := b.Stat[[2]int32{namespaceID, quota}]
+	if s == nil {
+		s = &NamespaceQuotaStat{}
+		b.Stat[[2]int32{namespaceID, quota}] = s
+	}
+	return s
+}
@@ -0,0 +1,48 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package data_model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestNamespaceQuota(t *testing.T) {
+	const bucketTs = 3600 * 100
+	key := func(i int32) *Key {
+		return &Key{Timestamp: bucketTs, Metric: 1, Keys: [format.MaxTags]int32{0, i}}
+	}
+	t.Run("rows and bytes", func(t *testing.T) {
+		ns := &format.NamespaceMeta{ID: 1, QuotaRowsPerSec: 20, QuotaBytesPerSec: 1000}
+		b := NewNamespaceQuota(2).NewBatch() // 10 rows and 500 bytes per shard
+		require.Zero(t, b.Accept(ns, key(1), 6, 100, bucketTs))
+		require.Equal(t, int32(format.TagValueIDNamespaceQuotaRows), b.Accept(ns, key(2), 5, 100, bucketTs))
+		require.Zero(t, b.Accept(ns, key(3), 4, 100, bucketTs))
+		require.Zero(t, b.Accept(ns, key(4), 4, 100, bucketTs+1)) // next second has own limit
+		require.Equal(t, int32(format.TagValueIDNamespaceQuotaBytes), b.Accept(ns, key(5), 1, 401, bucketTs+1))
+		require.Equal(t, NamespaceQuotaStat{Keep: 14, Discard: 5}, *b.Stat[[2]int32{1, format.TagValueIDNamespaceQuotaRows}])
+		require.Equal(t, NamespaceQuotaStat{Keep: 300, Discard: 401}, *b.Stat[[2]int32{1, format.TagValueIDNamespaceQuotaBytes}])
+	})
+	t.Run("cardinality", func(t *testing.T) {
+		ns := &format.NamespaceMeta{ID: 2, QuotaCardinalityPerHour: 2}
+		q := NewNamespaceQuota(1)
+		b := q.NewBatch()
+		require.Zero(t, b.Accept(ns, key(1), 1, 100, bucketTs))
+		require.Zero(t, b.Accept(ns, key(2), 1, 100, bucketTs))
+		require.Equal(t, int32(format.TagValueIDNamespaceQuotaCardinality), b.Accept(ns, key(3), 1, 100, bucketTs))
+		b = q.NewBatch() // series seen during hour are kept between inserts
+		require.Zero(t, b.Accept(ns, key(1), 1, 100, bucketTs+1))
+		require.Equal(t, int32(format.TagValueIDNamespaceQuotaCardinality), b.Accept(ns, key(3), 1, 100, bucketTs+1))
+		require.Equal(t, NamespaceQuotaStat{Keep: 2, Discard: 1}, *b.Stat[[2]int32{2, format.TagValueIDNamespaceQuotaCardinality}])
+		b = q.NewBatch()
+		require.Zero(t, b.Accept(ns, key(3), 1, 100, bucketTs+3600)) // next hour starts from scratch
+		require.Zero(t, b.Accept(ns, key(4), 1, 100, bucketTs-1))    // previous hour is not tracked any more
+	})
+}
@@ -9,6 +9,7 @@ package data_model
 import (
 	"math"
 	"sort"
+	"time"
 
 	"pgregory.net/rand"
 
@@ -26,6 +27,8 @@ type (
 		BucketTs    uint32
 		metric      *format.MetricMetaValue
 		fairKey     int32
+		policyKeep  bool // never sampled according to metric sampling policy
+		policy      bool // kept because of metric sampling policy
 	}
 
 	SamplerGroup struct { // either metric group, metric or fair key
@@ -56,15 +59,25 @@ type (
 		KeepF    func(Key, *MultiItem, uint32)
 		DiscardF func(Key, *MultiItem, uint32)
 
+		// Records sampling decisions for metrics and namespaces armed by debug request, optional
+		Explain *SamplerExplainLog
+
 		// Unit tests support
 		RoundF  func(float64, *rand.Rand) float64 // rounds sample factor to an integer
 		SelectF func([]SamplingMultiItemPair, float64, *rand.Rand) int
 	}
 
 	Sampler struct {
-		items  []SamplingMultiItemPair
-		config SamplerConfig
-		partF  []func(*Sampler, []SamplingMultiItemPair) ([]SamplerGroup, int64)
+		items     []SamplingMultiItemPair
+		config    SamplerConfig
+		partF     []func(*Sampler, []SamplingMultiItemPair) ([]SamplerGroup, int64)
+		partLevel []string // for explanations, parallel to partF
+
+		// explain mode, empty unless armed
+		explainMetrics    map[int32]bool
+		explainNamespaces map[int32]bool
+		explainPath       []SamplerExplainStep
+		explainCur        *SamplerExplanation
 	}
 
 	SamplerStep struct {
@@ -83,8 +96,9 @@ type (
 	}
 
 	SamplerStatisticsItem struct {
-		SumSizeKeep    ItemValue
-		SumSizeDiscard ItemValue
+		SumSizeKeep       ItemValue
+		SumSizeDiscard    ItemValue
+		SumSizeKeepPolicy ItemValue // kept because of metric sampling policy, not included into SumSizeKeep
 	}
 )
 
@@ -118,13 +132,17 @@ func NewSampler(capacity int, config SamplerConfig) Sampler {
 	}
 	if config.SampleNamespaces {
 		h.partF = append(h.partF, partitionByNamespace)
+		h.partLevel = append(h.partLevel, SamplerExplainLevelNamespace)
 	}
 	if config.SampleGroups {
 		h.partF = append(h.partF, partitionByGroup)
+		h.partLevel = append(h.partLevel, SamplerExplainLevelGroup)
 	}
 	h.partF = append(h.partF, partitionByMetric)
+	h.partLevel = append(h.partLevel, SamplerExplainLevelMetric)
 	if config.SampleKeys {
 		h.partF = append(h.partF, partitionByKey)
+		h.partLevel = append(h.partLevel, SamplerExplainLevelKey)
 	}
 	return h
 }
@@ -144,6 +162,7 @@ func (h *Sampler) Add(p SamplingMultiItemPair) {
 			p.fairKey = p.Key.Keys[x]
 		}
 	}
+	p.policyKeep = sampleKeep(p.metric, &p.Key)
 	h.items = append(h.items, p)
 }
 
@@ -162,6 +181,9 @@ func (h *Sampler) Run(budget int64, stat *SamplerStatistics) {
 		}
 		return lhs.fairKey < rhs.fairKey
 	})
+	if h.config.Explain != nil {
+		h.explainMetrics, h.explainNamespaces = h.config.Explain.armed(time.Now())
+	}
 	h.run(h.items, 0, budget, stat)
 }
 
@@ -185,6 +207,13 @@ func (h *Sampler) run(s []SamplingMultiItemPair, depth int, budget int64, stat *
 		var lhs, rhs *SamplerGroup = &groups[i], &groups[j]
 		return lhs.sumSize*rhs.weight < rhs.sumSize*lhs.weight // comparing rational numbers
 	})
+	if h.partLevel[depth] == SamplerExplainLevelMetric {
+		groups, budget, sumWeight = h.runMinBudget(groups, depth, budget, sumWeight, stat)
+		if budget < 1 {
+			budget = 1
+		}
+	}
+	explain := h.explainMetrics != nil || h.explainNamespaces != nil
 	// Groups smaller than the budget aren't sampled
 	i := 0
 	for ; i < len(groups); i++ {
@@ -193,6 +222,9 @@ func (h *Sampler) run(s []SamplingMultiItemPair, depth int, budget int64, stat *
 		if bxw < sumWeight*g.sumSize {
 			break // SF > 1
 		}
+		if explain {
+			h.explainKeep(g, g.explainStep(h.partLevel[depth], budget, sumWeight, false))
+		}
 		if g.MetricID == 0 {
 			// namespace or group budget
 			g.statBudget(h, stat, bxw, sumWeight)
@@ -209,12 +241,16 @@ func (h *Sampler) run(s []SamplingMultiItemPair, depth int, budget int64, stat *
 	for j := i; j < len(groups); j++ {
 		g := &groups[j]
 		bxw := budget * g.weight
+		explainPushed := explain && h.explainPush(g, g.explainStep(h.partLevel[depth], budget, sumWeight, true))
 		if g.noSampleAgent && h.config.ModeAgent {
 			if g.MetricID == 0 {
 				// namespace or group budget
 				g.statBudget(h, stat, bxw, sumWeight)
 			}
 			g.keep(h, stat)
+			if h.explainCur != nil {
+				h.explainCur.NoSampleAgent = true
+			}
 		} else if depth < len(h.partF)-1 {
 			b := int64(h.config.RoundF(float64(bxw)/float64(sumWeight), h.config.Rand))
 			if g.MetricID == 0 && (g.groupID != 0 || !h.config.SampleGroups) {
@@ -227,6 +263,15 @@ func (h *Sampler) run(s []SamplingMultiItemPair, depth int, budget int64, stat *
 			if g.SF > 1 {
 				n++
 			}
+			if h.explainCur != nil {
+				h.explainCur.SF = max(h.explainCur.SF, g.SF)
+				if h.partLevel[depth] == SamplerExplainLevelKey && g.SF > 1 {
+					h.explainCur.FairKeysSampled++
+				}
+			}
+		}
+		if explainPushed {
+			h.explainPop()
 		}
 	}
 	// Update statistics
@@ -241,9 +286,96 @@ func (h *Sampler) run(s []SamplingMultiItemPair, depth int, budget int64, stat *
 	}
 }
 
+// Metrics with minimum budget policy whose fair share is less than minimum get exactly minimum budget,
+// and are removed from fair share of other metrics
+func (h *Sampler) runMinBudget(groups []SamplerGroup, depth int, budget int64, sumWeight int64, stat *SamplerStatistics) ([]SamplerGroup, int64, int64) {
+	var (
+		res       = groups[:0] // filter in place
+		sampled   []SamplerGroup
+		sumBudget int64
+		sumW      int64
+		capBudget = budget / 2 // metrics with minimum budget policy together never take more than half
+	)
+	explain := h.explainMetrics != nil || h.explainNamespaces != nil
+	for i := range groups {
+		g := groups[i]
+		minBudget := min(g.items[0].metric.SampleMinBudget, capBudget)
+		if minBudget <= 0 || minBudget*sumWeight <= budget*g.weight || (g.noSampleAgent && h.config.ModeAgent) {
+			res = append(res, g)
+			continue
+		}
+		sumWeight -= g.weight
+		if g.sumSize <= minBudget {
+			if explain {
+				h.explainKeep(&g, g.explainStep(h.partLevel[depth], minBudget, g.weight, false))
+			}
+			budget -= g.sumSize
+			capBudget -= g.sumSize
+			g.keepPolicy(h, stat)
+			continue
+		}
+		budget -= minBudget
+		capBudget -= minBudget
+		explainPushed := explain && h.explainPush(&g, g.explainStep(h.partLevel[depth], minBudget, g.weight, true))
+		if h.explainCur != nil {
+			h.explainCur.MinBudget = minBudget
+		}
+		if depth < len(h.partF)-1 {
+			h.run(g.items, depth+1, minBudget, stat)
+		} else {
+			h.sample(&g, minBudget, 1, stat)
+			if h.explainCur != nil {
+				h.explainCur.SF = max(h.explainCur.SF, g.SF)
+			}
+			if g.SF > 1 {
+				sampled = append(sampled, g)
+				sumBudget += minBudget
+				sumW += g.weight
+			}
+		}
+		if explainPushed {
+			h.explainPop()
+		}
+	}
+	if len(sampled) != 0 {
+		stat.Count += len(sampled)
+		stat.Steps = append(stat.Steps, SamplerStep{
+			Groups:    sampled,
+			Budget:    sumBudget,
+			SumWeight: sumW,
+		})
+	}
+	return res, budget, sumWeight
+}
+
 func (g *SamplerGroup) keep(h *Sampler, stat *SamplerStatistics) {
 	for i := range g.items {
-		g.items[i].keep(1, h, stat)
+		if g.items[i].policyKeep {
+			g.items[i].keepPolicy(h, stat)
+		} else {
+			g.items[i].keep(1, h, stat)
+		}
+	}
+}
+
+// keeps series which metric sampling policy never samples, they are moved to the front, returns their number and size
+func (g *SamplerGroup) keepPolicyItems(h *Sampler, stat *SamplerStatistics) (int, int64) {
+	var n int
+	var size int64
+	for i := range g.items {
+		if g.items[i].policyKeep {
+			g.items[i].keepPolicy(h, stat)
+			g.items[n], g.items[i] = g.items[i], g.items[n]
+			size += int64(g.items[n].Size)
+			n++
+		}
+	}
+	return n, size
+}
+
+func (g *SamplerGroup) keepPolicy(h *Sampler, stat *SamplerStatistics) {
+	for i := range g.items {
+		g.items[i].keepPolicy(h, stat)
 	}
 }
 
@@ -268,6 +400,11 @@ func (p *SamplingMultiItemPair) keep(sf float64, h *Sampler, stat *SamplerStatis
 	stat.add(p, true)
 }
 
+func (p *SamplingMultiItemPair) keepPolicy(h *Sampler, stat *SamplerStatistics) {
+	p.policy = true
+	p.keep(1, h, stat)
+}
+
 func (p *SamplingMultiItemPair) discard(sf float64, h *Sampler, stat *SamplerStatistics) {
 	p.Item.SF = sf // communicate selected factor to next step of processing
 	if h.config.DiscardF != nil {
@@ -277,6 +414,15 @@ func (p *SamplingMultiItemPair) discard(sf float64, h *Sampler, stat *SamplerSta
 }
 
 func (h *Sampler) sample(g *SamplerGroup, budgetNum, budgetDenom int64, stat *SamplerStatistics) {
+	// Series which metric sampling policy never samples take their size from the fair share of their metric
+	if n, size := g.keepPolicyItems(h, stat); n != 0 {
+		g.items = g.items[n:]
+		g.sumSize -= size
+		budgetNum -= size * budgetDenom
+		if len(g.items) == 0 {
+			return
+		}
+	}
 	sfNum := budgetDenom * g.sumSize
 	sfDenom := budgetNum
 	if sfNum < 1 {
@@ -295,16 +441,48 @@ func (h *Sampler) sample(g *SamplerGroup, budgetNum, budgetDenom int64, stat *Sa
 		sfNum = int64(sf)
 		sfDenom = 1
 	}
+	var (
+		items = g.items
+		pos   = int(int64(len(items)) * sfDenom / sfNum / 2) // len(items) / sf / 2
+	)
+	// Keep strata
+	//
+	// Stratified sampling policy keeps a few series for every value of a tag, the rest of the budget is for other series.
+	// Like whales, strata take not more than half of sampling budget, otherwise high cardinality tag turns sampling off.
+	if m := items[0].metric; m.SampleStratifyMin > 0 && 0 <= m.SampleStratifyIndex && m.SampleStratifyIndex < len(items[0].Key.Keys) {
+		var n int
+		items, n = h.keepStrata(items, m.SampleStratifyIndex, m.SampleStratifyMin, int(float64(len(items))/sf/2), stat)
+		if h.explainCur != nil {
+			h.explainCur.Stratified += n
+		}
+		left := float64(len(g.items))/sf - float64(n) // series which still fit into budget
+		if left >= float64(len(items)) {
+			for i := range items {
+				items[i].keep(1, h, stat)
+			}
+			return
+		}
+		if left < 1 {
+			left = 1
+		}
+		sf = float64(len(items)) / left
+		if g.roundFactors {
+			sf = h.config.RoundF(sf, h.config.Rand)
+			if sf <= 1 {
+				for i := range items {
+					items[i].keep(1, h, stat)
+				}
+				return
+			}
+		}
+		pos = int(left / 2)
+	}
 	g.SF = sf
 	// Keep whales
 	//
 	// Often we have a few rows with dominating counts (whales). If we randomly discard those rows, we get wild fluctuation
 	// of sums. On the other hand if we systematically discard rows with small counts, rare events, like errors cannot get through.
 	// So we allow half of sampling budget for whales, and the other half is spread fairly between other events.
-	var (
-		items = g.items
-		pos   = int(int64(len(items)) * sfDenom / sfNum / 2) // len(items) / sf / 2
-	)
 	if pos > 0 {
 		if pos > len(items) { // should always hold but checking is cheap
 			pos = len(items)
@@ -316,6 +494,9 @@ func (h *Sampler) sample(g *SamplerGroup, budgetNum, budgetDenom int64, stat *Sa
 			p := &items[i]
 			p.keep(1, h, stat)
 		}
+		if h.explainCur != nil {
+			h.explainCur.Whales += pos
+		}
 		items = items[pos:]
 		sf *= 2 // space has been taken by whales
 	}
@@ -331,6 +512,50 @@ func (h *Sampler) sample(g *SamplerGroup, budgetNum, budgetDenom int64, stat *Sa
 	}
 }
 
+// keeps first "k" series (by whale weight) for every value of tag "x", but not more than "limit" series in total,
+// strata with heavier series are preferred when limit is reached, returns the rest and number of series kept
+func (h *Sampler) keepStrata(items []SamplingMultiItemPair, x int, k int, limit int, stat *SamplerStatistics) ([]SamplingMultiItemPair, int) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Key.Keys[x] != items[j].Key.Keys[x] {
+			return items[i].Key.Keys[x] < items[j].Key.Keys[x]
+		}
+		return items[i].WhaleWeight > items[j].WhaleWeight
+	})
+	var strata [][2]int // [begin, end) of every tag value
+	for i := 0; i < len(items); {
+		j := i + 1
+		for ; j < len(items) && items[j].Key.Keys[x] == items[i].Key.Keys[x]; j++ {
+		}
+		strata = append(strata, [2]int{i, j})
+		i = j
+	}
+	if limit < len(strata)*k {
+		sort.SliceStable(strata, func(i, j int) bool {
+			return items[strata[i][0]].WhaleWeight > items[strata[j][0]].WhaleWeight
+		})
+	}
+	n := 0 // every stratum gets j-th series before any gets (j+1)-th
+	for j := 0; j < k && n < limit; j++ {
+		for _, s := range strata {
+			if n == limit {
+				break
+			}
+			if s[0]+j < s[1] {
+				items[s[0]+j].keepPolicy(h, stat)
+				n++
+			}
+		}
+	}
+	n = 0 // kept series are moved to the front
+	for i := range items {
+		if items[i].policy {
+			items[n], items[i] = items[i], items[n]
+			n++
+		}
+	}
+	return items[n:], n
+}
+
 func (stat *SamplerStatistics) add(p *SamplingMultiItemPair, keep bool) {
 	var metricKind int32
 	switch {
@@ -357,7 +582,9 @@ func (stat *SamplerStatistics) add(p *SamplingMultiItemPair, keep bool) {
 		v = &SamplerStatisticsItem{}
 		stat.Items = map[[3]int32]*SamplerStatisticsItem{k: v}
 	}
-	if keep {
+	if keep && p.policy {
+		v.SumSizeKeepPolicy.AddValue(float64(p.Size))
+	} else if keep {
 		v.SumSizeKeep.AddValue(float64(p.Size))
 	} else {
 		v.SumSizeDiscard.AddValue(float64(p.Size))
@@ -514,6 +741,20 @@ func partitionByKey(_ *Sampler, s []SamplingMultiItemPair) ([]SamplerGroup, int6
 	return res, sumWeight
 }
 
+// reports whether metric sampling policy forbids sampling series
+func sampleKeep(metric *format.MetricMetaValue, key *Key) bool {
+	x := metric.SampleKeepIndex
+	if len(metric.SampleKeepValueIDs) == 0 || x < 0 || x >= len(key.Keys) {
+		return false
+	}
+	for _, v := range metric.SampleKeepValueIDs {
+		if key.Keys[x] == v {
+			return true
+		}
+	}
+	return false
+}
+
 func (h *Sampler) getMetricMeta(metricID int32) *format.MetricMetaValue {
 	if h.config.Meta == nil {
 		return &nilMetric
@@ -611,11 +852,15 @@ func roundSampleFactor(sf float64, rnd *rand.Rand) float64 {
 
 // This function assumes structure of hour table with time = toStartOfHour(time)
 // This turned out bad idea, so we do not use it anywhere now
-func SampleFactorDeterministic(sampleFactors map[int32]float64, key Key, time uint32) (float64, bool) {
+// Metric (optional) sampling policy is honored partially, stratification requires seeing all series, so only Sampler does it
+func SampleFactorDeterministic(sampleFactors map[int32]float64, key Key, time uint32, metric *format.MetricMetaValue) (float64, bool) {
 	sf, ok := sampleFactors[key.Metric]
 	if !ok {
 		return 1, true
 	}
+	if metric != nil && sampleKeep(metric, &key) {
+		return 1, true
+	}
 	// Deterministic sampling - we select random set of allowed keys per hour
 	key.Metric = int32(time/3600) * 3600 // a bit of hack, we do not need metric here, so we replace it with toStartOfHour(time)
 	ha := key.Hash()
@@ -0,0 +1,237 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package data_model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+)
+
+const (
+	SamplerExplainCapacity  = 64               // last explanations kept
+	SamplerExplainArmPeriod = 10 * time.Minute // explain mode is turned off automatically, so nobody forgets it enabled
+)
+
+const (
+	SamplerExplainLevelNamespace = "namespace"
+	SamplerExplainLevelGroup     = "group"
+	SamplerExplainLevelMetric    = "metric"
+	SamplerExplainLevelKey       = "key"
+)
+
+type (
+	// SamplerExplainLog records why sampler selected sample factor for chosen metrics or namespaces.
+	// Explain mode is armed by RPC request and costs nothing while nobody asks.
+	SamplerExplainLog struct {
+		mu         sync.Mutex
+		metrics    map[int32]time.Time // armed until
+		namespaces map[int32]time.Time
+		log        []SamplerExplanation // ring buffer
+		pos        int
+	}
+
+	// SamplerExplanation describes single sampler decision for a metric
+	SamplerExplanation struct {
+		Time            uint32               `json:"time"`
+		MetricID        int32                `json:"metric"`
+		NamespaceID     int32                `json:"namespace"`
+		GroupID         int32                `json:"group"`
+		SF              float64              `json:"sf"` // maximum across fair keys if sampled by keys
+		Items           int                  `json:"items"`
+		Whales          int                  `json:"whales"`               // items kept with SF 1 because of large WhaleWeight
+		Stratified      int                  `json:"stratified,omitempty"` // items kept with SF 1 by stratified sampling policy
+		MinBudget       int64                `json:"min_budget,omitempty"` // set if metric got minimum budget from its sampling policy
+		FairKeys        int                  `json:"fair_keys,omitempty"`
+		FairKeysSampled int                  `json:"fair_keys_sampled,omitempty"`
+		NoSampleAgent   bool                 `json:"no_sample_agent,omitempty"`
+		Steps           []SamplerExplainStep `json:"steps"` // from namespace down to metric
+	}
+
+	// SamplerExplainStep describes place of group among its siblings at one sampler step
+	SamplerExplainStep struct {
+		Level     string  `json:"level"`
+		ID        int32   `json:"id"`
+		Weight    int64   `json:"weight"`
+		SumSize   int64   `json:"sum_size"`
+		Budget    int64   `json:"budget"`     // left after groups which fit into their share
+		SumWeight int64   `json:"sum_weight"` // of groups sharing Budget
+		Share     float64 `json:"share"`      // Budget*Weight/SumWeight
+		Sampled   bool    `json:"sampled"`    // false if group fit into its share
+	}
+)
+
+// Arm enables explain mode for metric or namespace (or both) for SamplerExplainArmPeriod
+func (l *SamplerExplainLog) Arm(metricID int32, namespaceID int32, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	until := now.Add(SamplerExplainArmPeriod)
+	if metricID != 0 {
+		if l.metrics == nil {
+			l.metrics = map[int32]time.Time{}
+		}
+		l.metrics[metricID] = until
+	}
+	if namespaceID != 0 {
+		if l.namespaces == nil {
+			l.namespaces = map[int32]time.Time{}
+		}
+		l.namespaces[namespaceID] = until
+	}
+}
+
+// Last returns explanations for metric or namespace, oldest first
+func (l *SamplerExplainLog) Last(metricID int32, namespaceID int32) []SamplerExplanation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res := []SamplerExplanation{}
+	for i := 0; i < len(l.log); i++ {
+		e := l.log[(l.pos+i)%len(l.log)]
+		if (metricID != 0 && e.MetricID == metricID) || (namespaceID != 0 && e.NamespaceID == namespaceID) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// HandleGetSamplingExplain arms explain mode and returns JSON array of explanations collected so far
+func (l *SamplerExplainLog) HandleGetSamplingExplain(args tlstatshouse.GetSamplingExplain, now time.Time) (string, error) {
+	if args.Metric == 0 && args.Namespace == 0 {
+		return "", fmt.Errorf("either metric or namespace must be set")
+	}
+	l.Arm(args.Metric, args.Namespace, now)
+	res, err := json.Marshal(l.Last(args.Metric, args.Namespace))
+	return string(res), err
+}
+
+// armed returns filter for a single sampler run, nil maps if explain mode is off
+func (l *SamplerExplainLog) armed(now time.Time) (metrics map[int32]bool, namespaces map[int32]bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for k, until := range l.metrics {
+		if now.After(until) {
+			delete(l.metrics, k)
+			continue
+		}
+		if metrics == nil {
+			metrics = map[int32]bool{}
+		}
+		metrics[k] = true
+	}
+	for k, until := range l.namespaces {
+		if now.After(until) {
+			delete(l.namespaces, k)
+			continue
+		}
+		if namespaces == nil {
+			namespaces = map[int32]bool{}
+		}
+		namespaces[k] = true
+	}
+	return metrics, namespaces
+}
+
+func (l *SamplerExplainLog) add(e SamplerExplanation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.log) < SamplerExplainCapacity {
+		l.log = append(l.log, e)
+		return
+	}
+	l.log[l.pos] = e
+	l.pos = (l.pos + 1) % len(l.log)
+}
+
+func (g *SamplerGroup) explainStep(level string, budget int64, sumWeight int64, sampled bool) SamplerExplainStep {
+	res := SamplerExplainStep{
+		Level:     level,
+		Weight:    g.weight,
+		SumSize:   g.sumSize,
+		Budget:    budget,
+		SumWeight: sumWeight,
+		Sampled:   sampled,
+	}
+	switch level {
+	case SamplerExplainLevelNamespace:
+		res.ID = g.namespaceID
+	case SamplerExplainLevelGroup:
+		res.ID = g.groupID
+	case SamplerExplainLevelMetric:
+		res.ID = g.MetricID
+	case SamplerExplainLevelKey:
+		res.ID = g.items[0].fairKey
+	}
+	if sumWeight != 0 {
+		res.Share = float64(budget) * float64(g.weight) / float64(sumWeight)
+	}
+	return res
+}
+
+func (h *Sampler) explainMatch(p *SamplingMultiItemPair) bool {
+	return h.explainMetrics[p.MetricID] || h.explainNamespaces[p.metric.NamespaceID]
+}
+
+func (h *Sampler) newExplanation(items []SamplingMultiItemPair) *SamplerExplanation {
+	p := &items[0]
+	return &SamplerExplanation{
+		Time:        p.BucketTs,
+		MetricID:    p.MetricID,
+		NamespaceID: p.metric.NamespaceID,
+		GroupID:     p.metric.GroupID,
+		SF:          1,
+		Items:       len(items),
+		Steps:       append([]SamplerExplainStep(nil), h.explainPath...),
+	}
+}
+
+// group fit into its share, so all metrics inside are kept
+func (h *Sampler) explainKeep(g *SamplerGroup, step SamplerExplainStep) {
+	if step.Level == SamplerExplainLevelKey {
+		if h.explainCur != nil {
+			h.explainCur.FairKeys++
+		}
+		return
+	}
+	h.explainPath = append(h.explainPath, step)
+	for s := g.items; len(s) != 0; { // items are sorted by metric
+		n := 1
+		for n < len(s) && s[n].MetricID == s[0].MetricID {
+			n++
+		}
+		if h.explainMatch(&s[0]) {
+			h.config.Explain.add(*h.newExplanation(s[:n]))
+		}
+		s = s[n:]
+	}
+	h.explainPath = h.explainPath[:len(h.explainPath)-1]
+}
+
+// returns true if step was pushed and must be popped after group is processed
+func (h *Sampler) explainPush(g *SamplerGroup, step SamplerExplainStep) bool {
+	if step.Level == SamplerExplainLevelKey {
+		if h.explainCur != nil {
+			h.explainCur.FairKeys++
+		}
+		return false
+	}
+	h.explainPath = append(h.explainPath, step)
+	if step.Level == SamplerExplainLevelMetric && h.explainMatch(&g.items[0]) {
+		h.explainCur = h.newExplanation(g.items)
+	}
+	return true
+}
+
+func (h *Sampler) explainPop() {
+	if h.explainCur != nil && h.explainPath[len(h.explainPath)-1].Level == SamplerExplainLevelMetric {
+		h.config.Explain.add(*h.explainCur)
+		h.explainCur = nil
+	}
+	h.explainPath = h.explainPath[:len(h.explainPath)-1]
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package data_model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rand"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func TestSamplerExplain(t *testing.T) {
+	var log SamplerExplainLog
+	s := NewSampler(0, SamplerConfig{SampleKeys: true, Rand: rand.New(), Explain: &log})
+	add := func(metric int32, n int) {
+		for i := 0; i < n; i++ {
+			s.Add(SamplingMultiItemPair{
+				Key:         Key{Metric: metric, Keys: [16]int32{int32(i % 4), int32(i)}},
+				Item:        &MultiItem{},
+				WhaleWeight: float64(i),
+				Size:        10,
+				MetricID:    metric,
+				BucketTs:    100,
+			})
+		}
+	}
+	add(1, 10)
+	add(2, 100)
+	add(3, 100) // not armed
+	log.Arm(1, 0, time.Now())
+	log.Arm(2, 0, time.Now())
+	s.Run(300+2000/3, &SamplerStatistics{})
+
+	const w = format.EffectiveWeightOne
+	require.Equal(t, []SamplerExplanation{{
+		Time:        100,
+		MetricID:    1,
+		NamespaceID: format.BuiltinNamespaceIDMissing,
+		GroupID:     format.BuiltinGroupIDMissing,
+		SF:          1,
+		Items:       10,
+		Steps:       []SamplerExplainStep{{Level: "metric", ID: 1, Weight: w, SumSize: 100, Budget: 966, SumWeight: 3 * w, Share: 322, Sampled: false}},
+	}}, log.Last(1, 0))
+	// metric 2 gets (966-100)/2 = 433 bytes, each of 4 fair keys gets 108.25 and is sampled with SF 250/108.25
+	e := log.Last(2, 0)
+	require.Len(t, e, 1)
+	require.Equal(t, 100, e[0].Items)
+	require.Equal(t, 4, e[0].FairKeys)
+	require.Equal(t, 4, e[0].FairKeysSampled)
+	require.Equal(t, 20, e[0].Whales) // 25 items per key, len/sf/2 = 5 of them are whales
+	require.InDelta(t, 1000./433, e[0].SF, 0.01)
+	require.Equal(t, []SamplerExplainStep{{Level: "metric", ID: 2, Weight: w, SumSize: 1000, Budget: 866, SumWeight: 2 * w, Share: 433, Sampled: true}}, e[0].Steps)
+	require.Empty(t, log.Last(3, 0))
+
+	s = NewSampler(0, SamplerConfig{Rand: rand.New(), Explain: &log})
+	add(1, 10)
+	log.Arm(1, 0, time.Now().Add(-SamplerExplainArmPeriod-time.Second)) // expired
+	s.Run(1000, &SamplerStatistics{})
+	require.Len(t, log.Last(1, 0), 1, "explain mode must be off")
+}
@@ -11,6 +11,7 @@ import (
 	"pgregory.net/rand"
 	"pgregory.net/rapid"
 
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
 	"github.com/vkcom/statshouse/internal/format"
 )
 
@@ -629,3 +630,161 @@ func sampleBucketLegacy(bucket *MetricsBucket, config samplerConfigEx) map[int32
 	}
 	return sampleFactors
 }
+
+func TestSamplingPolicy(t *testing.T) {
+	meta := &format.MetricMetaValue{
+		MetricID:            1,
+		Name:                "errors",
+		Resolution:          1,
+		Kind:                format.MetricKindCounter,
+		Tags:                []format.MetricMetaTag{{}, {Name: "status"}, {Name: "service"}},
+		SampleKeepTagID:     "1",
+		SampleKeepValues:    []string{"error"},
+		SampleKeepValueIDs:  []int32{7},
+		SampleStratifyTagID: "2",
+		SampleStratifyMin:   2,
+	}
+	require.NoError(t, meta.RestoreCachedInfo())
+	var kept []SamplingMultiItemPair
+	s := NewSampler(0, SamplerConfig{
+		Rand: rand.New(),
+		KeepF: func(k Key, item *MultiItem, _ uint32) {
+			kept = append(kept, SamplingMultiItemPair{Key: k, Item: item})
+		},
+	})
+	for i := 0; i < 100; i++ {
+		status := int32(1)
+		if i%10 == 0 {
+			status = 7
+		}
+		s.Add(SamplingMultiItemPair{
+			Key:      Key{Metric: 1, Keys: [16]int32{0, status, int32(i % 5)}},
+			Item:     &MultiItem{MetricMeta: meta},
+			Size:     10,
+			MetricID: 1,
+		})
+	}
+	var stat SamplerStatistics
+	s.Run(300, &stat)
+	// 10 series with status 7 take 100 bytes, 90 others are sampled to fit 200 bytes with SF 4.5,
+	// 2 series per service are kept, so 10 others fit with SF 8
+	require.Equal(t, []tlstatshouse.SampleFactor{{Metric: 1, Value: 8}}, stat.GetSampleFactors(nil))
+	errors := 0
+	strata := map[int32]int{}
+	for _, p := range kept {
+		if p.Key.Keys[1] == 7 {
+			require.Equal(t, 1., p.Item.SF)
+			errors++
+		} else if p.Item.SF == 1 {
+			strata[p.Key.Keys[2]]++
+		}
+	}
+	require.Equal(t, 10, errors)
+	for i := int32(0); i < 5; i++ {
+		require.GreaterOrEqual(t, strata[i], 2)
+	}
+	var policy float64
+	for _, v := range stat.Items {
+		policy += v.SumSizeKeepPolicy.ValueSum
+	}
+	require.Equal(t, float64(200), policy)
+
+	// status 7 is never sampled deterministically
+	sf, ok := SampleFactorDeterministic(map[int32]float64{1: 1e9}, Key{Metric: 1, Keys: [16]int32{0, 7}}, 0, meta)
+	require.True(t, ok)
+	require.Equal(t, 1., sf)
+}
+
+func TestSamplingPolicyManyStrata(t *testing.T) {
+	meta := &format.MetricMetaValue{
+		MetricID:            1,
+		Name:                "requests",
+		Resolution:          1,
+		Kind:                format.MetricKindCounter,
+		Tags:                []format.MetricMetaTag{{}, {Name: "user"}},
+		SampleStratifyTagID: "1",
+		SampleStratifyMin:   2,
+	}
+	require.NoError(t, meta.RestoreCachedInfo())
+	var keptSize int
+	strata := map[int32]bool{}
+	s := NewSampler(0, SamplerConfig{
+		Rand: rand.New(),
+		KeepF: func(k Key, item *MultiItem, _ uint32) {
+			keptSize += 10
+			if item.SF == 1 {
+				strata[k.Keys[1]] = true
+			}
+		},
+	})
+	for i := 0; i < 100; i++ { // every series is a stratum
+		s.Add(SamplingMultiItemPair{
+			Key:         Key{Metric: 1, Keys: [16]int32{0, int32(i)}},
+			Item:        &MultiItem{MetricMeta: meta},
+			WhaleWeight: float64(i),
+			Size:        10,
+			MetricID:    1,
+		})
+	}
+	var stat SamplerStatistics
+	s.Run(200, &stat)
+	// strata take half of budget (heaviest 10 series), 90 others are sampled to fit 10 series
+	require.Equal(t, []tlstatshouse.SampleFactor{{Metric: 1, Value: 9}}, stat.GetSampleFactors(nil))
+	for i := int32(90); i < 100; i++ {
+		require.True(t, strata[i])
+	}
+	require.Less(t, keptSize, 400)
+}
+
+func TestSamplingMinBudget(t *testing.T) {
+	meta := &format.MetricMetaValue{MetricID: 2, Name: "small", Kind: format.MetricKindCounter, Resolution: 1, SampleMinBudget: 300}
+	require.NoError(t, meta.RestoreCachedInfo())
+	greedy := &format.MetricMetaValue{MetricID: 2, Name: "greedy", Kind: format.MetricKindCounter, Resolution: 1, SampleMinBudget: 10000}
+	require.NoError(t, greedy.RestoreCachedInfo())
+	for _, m := range []*format.MetricMetaValue{meta, greedy} {
+		s := NewSampler(0, SamplerConfig{Rand: rand.New()})
+		for i := 0; i < 100; i++ {
+			s.Add(SamplingMultiItemPair{Key: Key{Metric: 1, Keys: [16]int32{int32(i)}}, Item: &MultiItem{}, Size: 10, MetricID: 1})
+			s.Add(SamplingMultiItemPair{Key: Key{Metric: 2, Keys: [16]int32{int32(i)}}, Item: &MultiItem{MetricMeta: m}, Size: 10, MetricID: 2})
+			s.Add(SamplingMultiItemPair{Key: Key{Metric: 3, Keys: [16]int32{int32(i)}}, Item: &MultiItem{}, Size: 10, MetricID: 3})
+		}
+		var stat SamplerStatistics
+		s.Run(600, &stat)
+		// fair share of 200 bytes is less than minimum, metrics 1 and 3 share the rest,
+		// minimum budget is capped by half of budget
+		sf := map[int32]float32{}
+		for _, v := range stat.GetSampleFactors(nil) {
+			sf[v.Metric] = v.Value
+		}
+		require.Equal(t, map[int32]float32{1: float32(1000. / 150), 2: float32(1000. / 300), 3: float32(1000. / 150)}, sf, m.Name)
+	}
+}
+
+func TestSamplingKeepChargedToMetric(t *testing.T) {
+	meta := &format.MetricMetaValue{
+		MetricID:           1,
+		Name:               "errors",
+		Resolution:         1,
+		Weight:             1,
+		Kind:               format.MetricKindCounter,
+		Tags:               []format.MetricMetaTag{{}, {Name: "status"}},
+		SampleKeepTagID:    "1",
+		SampleKeepValues:   []string{"error"},
+		SampleKeepValueIDs: []int32{7},
+	}
+	require.NoError(t, meta.RestoreCachedInfo())
+	s := NewSampler(0, SamplerConfig{Rand: rand.New()})
+	for i := 0; i < 100; i++ {
+		s.Add(SamplingMultiItemPair{Key: Key{Metric: 1, Keys: [16]int32{int32(i), 7}}, Item: &MultiItem{MetricMeta: meta}, Size: 10, MetricID: 1})
+		s.Add(SamplingMultiItemPair{Key: Key{Metric: 2, Keys: [16]int32{int32(i)}}, Item: &MultiItem{}, Size: 10, MetricID: 2})
+	}
+	var stat SamplerStatistics
+	s.Run(400, &stat)
+	// all series of metric 1 are kept, but metric 2 still gets its fair share
+	require.Equal(t, []tlstatshouse.SampleFactor{{Metric: 2, Value: 5}}, stat.GetSampleFactors(nil))
+	var policy float64
+	for _, v := range stat.Items {
+		policy += v.SumSizeKeepPolicy.ValueSum
+	}
+	require.Equal(t, float64(1000), policy)
+}
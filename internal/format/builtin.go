@@ -118,6 +118,11 @@ const (
 	BuiltinMetricIDAggSamplingTime            = -95
 	BuiltinMetricIDAgentDiskCacheSize         = -96
 	BuiltinMetricIDAggContributors            = -97
+	BuiltinMetricIDAlertRuleState             = -98
+	BuiltinMetricIDRecordingRuleEval          = -99
+	BuiltinMetricIDAggNamespaceQuotaUsage     = -100
+	BuiltinMetricIDAggHourTagCardinality      = -101
+	BuiltinMetricIDAggHourTagTopValues        = -102
 
 	// [-1000..-2000] reserved by host system metrics
 	// [-10000..-12000] reserved by builtin dashboard
@@ -157,6 +162,11 @@ const (
 	BuiltinMetricNamePromQLEngineTime           = "__promql_engine_time"
 	BuiltinMetricNameAPICacheHit                = "__api_cache_hit_rate"
 	BuiltinMetricNameIDUIErrors                 = "__ui_errors"
+	BuiltinMetricNameAlertRuleState             = "__alert_rule_state"
+	BuiltinMetricNameRecordingRuleEval          = "__recording_rule_eval"
+	BuiltinMetricNameAggHourTagCardinality      = "__agg_hour_tag_cardinality"
+	BuiltinMetricNameAggHourTagTopValues        = "__agg_hour_tag_top_values"
+	BuiltinMetricNameAggNamespaceQuotaUsage     = "__agg_namespace_quota_usage"
 
 	TagValueIDBadgeAgentSamplingFactor = -1
 	TagValueIDBadgeAggSamplingFactor   = -10
@@ -248,14 +258,22 @@ const (
 	TagValueIDSrcIngestionStatusWarnOldCounterSemantic       = 51 // never written, for historic data
 	TagValueIDSrcIngestionStatusWarnMapInvalidRawTagValue    = 52
 	TagValueIDSrcIngestionStatusWarnMapTagNameFoundDraft     = 53
-
-	TagValueIDPacketFormatLegacy   = 1
-	TagValueIDPacketFormatTL       = 2
-	TagValueIDPacketFormatMsgPack  = 3
-	TagValueIDPacketFormatJSON     = 4
-	TagValueIDPacketFormatProtobuf = 5
-	TagValueIDPacketFormatRPC      = 6
-	TagValueIDPacketFormatEmpty    = 7
+	TagValueIDSrcIngestionStatusWarnMetricDeprecated         = 54
+	TagValueIDSrcIngestionStatusErrNamespaceQuota            = 55 // written by aggregator
+	TagValueIDSrcIngestionStatusErrMetricStateDisabled       = 56
+	TagValueIDSrcIngestionStatusErrMetricStateDeleted        = 57
+
+	TagValueIDPacketFormatLegacy          = 1
+	TagValueIDPacketFormatTL              = 2
+	TagValueIDPacketFormatMsgPack         = 3
+	TagValueIDPacketFormatJSON            = 4
+	TagValueIDPacketFormatProtobuf        = 5
+	TagValueIDPacketFormatRPC             = 6
+	TagValueIDPacketFormatEmpty           = 7
+	TagValueIDPacketFormatOTLP            = 8
+	TagValueIDPacketFormatStatsD          = 9
+	TagValueIDPacketFormatPromRemoteWrite = 10
+	TagValueIDPacketFormatStream          = 11
 
 	TagValueIDAgentReceiveStatusOK    = 1
 	TagValueIDAgentReceiveStatusError = 2
@@ -351,11 +369,23 @@ const (
 	TagRPCError     = 4
 	TagTimeoutError = 5
 
-	TagValueIDSamplingDecisionKeep    = -1
-	TagValueIDSamplingDecisionDiscard = -2
+	TagValueIDSamplingDecisionKeep       = -1
+	TagValueIDSamplingDecisionDiscard    = -2
+	TagValueIDSamplingDecisionKeepPolicy = -3
 
 	TagValueIDDMESGParseError = 1
 	TagValueIDAPIPanicError   = 2
+
+	TagValueIDAlertStateInactive = 1
+	TagValueIDAlertStatePending  = 2
+	TagValueIDAlertStateFiring   = 3
+
+	TagValueIDRecordingRuleStatusOK    = 1
+	TagValueIDRecordingRuleStatusError = 2
+
+	TagValueIDNamespaceQuotaRows        = 1
+	TagValueIDNamespaceQuotaBytes       = 2
+	TagValueIDNamespaceQuotaCardinality = 3
 )
 
 var (
@@ -609,6 +639,10 @@ This metric uses sampling budgets of metric it refers to, so flooding by errors
 					TagValueIDSrcIngestionStatusWarnOldCounterSemantic:       "warn_deprecated_counter_semantic",
 					TagValueIDSrcIngestionStatusWarnMapInvalidRawTagValue:    "warn_map_invalid_raw_tag_value",
 					TagValueIDSrcIngestionStatusWarnMapTagNameFoundDraft:     "warn_tag_draft_found",
+					TagValueIDSrcIngestionStatusWarnMetricDeprecated:         "warn_metric_deprecated",
+					TagValueIDSrcIngestionStatusErrNamespaceQuota:            "err_namespace_quota",
+					TagValueIDSrcIngestionStatusErrMetricStateDisabled:       "err_metric_state_disabled",
+					TagValueIDSrcIngestionStatusErrMetricStateDeleted:        "err_metric_state_deleted",
 				}),
 			}, {
 				Description: "tag_id",
@@ -1527,6 +1561,7 @@ Ingress proxies first proxy request (to record host and IP of agent), then repla
 					0x28bea524: "statshouse.autoCreate",
 					0x4285ff57: "statshouse.getConfig2",
 					0x42855554: "statshouse.getMetrics3",
+					0x64418cf9: "statshouse.getSamplingExplain",
 					0x4285ff56: "statshouse.getTagMapping2",
 					0x75a7f68e: "statshouse.getTagMappingBootstrap",
 					0x41df72a3: "statshouse.getTargets2",
@@ -1724,8 +1759,9 @@ Value is delta between second value and time it was inserted.`,
 			}, {
 				Name: "sampling_decision",
 				ValueComments: convertToValueComments(map[int32]string{
-					TagValueIDSamplingDecisionKeep:    "keep",
-					TagValueIDSamplingDecisionDiscard: "discard",
+					TagValueIDSamplingDecisionKeep:       "keep",
+					TagValueIDSamplingDecisionDiscard:    "discard",
+					TagValueIDSamplingDecisionKeepPolicy: "keep_policy",
 				}),
 			}, {
 				Name:        "namespace",
@@ -1759,8 +1795,9 @@ Value is delta between second value and time it was inserted.`,
 			}, {
 				Name: "sampling_decision",
 				ValueComments: convertToValueComments(map[int32]string{
-					TagValueIDSamplingDecisionKeep:    "keep",
-					TagValueIDSamplingDecisionDiscard: "discard",
+					TagValueIDSamplingDecisionKeep:       "keep",
+					TagValueIDSamplingDecisionDiscard:    "discard",
+					TagValueIDSamplingDecisionKeepPolicy: "keep_policy",
 				}),
 			}, {
 				Name:        "namespace",
@@ -1977,6 +2014,101 @@ Value is delta between second value and time it was inserted.`,
 				Description: "-",
 			}},
 		},
+		BuiltinMetricIDAlertRuleState: {
+			Name:        BuiltinMetricNameAlertRuleState,
+			Kind:        MetricKindCounter,
+			Description: "Alert state changes, written by API evaluating alert rules. Every series returned by rule expression is a separate alert.",
+			Tags: []MetricMetaTag{{
+				Description: "alert_rule",
+				Raw:         true,
+			}, {
+				Description:   "state_from",
+				ValueComments: convertToValueComments(alertStateToValue),
+			}, {
+				Description:   "state_to",
+				ValueComments: convertToValueComments(alertStateToValue),
+			}},
+		},
+		BuiltinMetricIDRecordingRuleEval: {
+			Name:        BuiltinMetricNameRecordingRuleEval,
+			Kind:        MetricKindValue,
+			MetricType:  MetricSecond,
+			Description: "Recording rule evaluation lag, time from scheduled evaluation till results are written. Written by API evaluating recording rules.",
+			Tags: []MetricMetaTag{{
+				Description: "recording_rule",
+				Raw:         true,
+			}, {
+				Description: "status",
+				ValueComments: convertToValueComments(map[int32]string{
+					TagValueIDRecordingRuleStatusOK:    "ok",
+					TagValueIDRecordingRuleStatusError: "error",
+				}),
+			}},
+		},
+		BuiltinMetricIDAggHourTagCardinality: {
+			Name: BuiltinMetricNameAggHourTagCardinality,
+			Kind: MetricKindValue,
+			Description: `Estimated unique tag values of metric per hour, collected so far for this hour.
+Each aggregator writes value once per minute for series it received, values are not multiplied by # of aggregator shards.`,
+			Resolution: 60,
+			Tags: []MetricMetaTag{{
+				Description: "-",
+			}, {
+				Description: "metric",
+				IsMetric:    true,
+			}, {
+				Description: "tag",
+				Raw:         true,
+			}},
+			PreKeyTagID: "1",
+		},
+		BuiltinMetricIDAggHourTagTopValues: {
+			Name: BuiltinMetricNameAggHourTagTopValues,
+			Kind: MetricKindValue,
+			Description: `Tag values contributing most distinct series to metric during current hour, value is share of distinct series seen by aggregator.
+Written only for tags with many unique values, see "__agg_hour_tag_cardinality".`,
+			Resolution: 60,
+			Tags: []MetricMetaTag{{
+				Description: "-",
+			}, {
+				Description: "metric",
+				IsMetric:    true,
+			}, {
+				Description: "tag",
+				Raw:         true,
+			}, {
+				Description: "tag_value",
+				Raw:         true,
+			}},
+			PreKeyTagID: "1",
+		},
+		BuiltinMetricIDAggNamespaceQuotaUsage: {
+			Name:        BuiltinMetricNameAggNamespaceQuotaUsage,
+			Kind:        MetricKindValue,
+			Description: `Namespace quota usage on aggregator shard, rows and bytes per inserted second, cardinality per hour. Rejected series are also written as "err_namespace_quota" ingestion status.`,
+			Tags: []MetricMetaTag{{
+				Description: "-",
+			}, {
+				Description:   "conveyor",
+				ValueComments: convertToValueComments(conveyorToValue),
+			}, {
+				Description: "namespace",
+				IsNamespace: true,
+			}, {
+				Description: "quota",
+				ValueComments: convertToValueComments(map[int32]string{
+					TagValueIDNamespaceQuotaRows:        "rows_per_sec",
+					TagValueIDNamespaceQuotaBytes:       "bytes_per_sec",
+					TagValueIDNamespaceQuotaCardinality: "cardinality_per_hour",
+				}),
+			}, {
+				Description: "quota_decision",
+				ValueComments: convertToValueComments(map[int32]string{
+					TagValueIDSamplingDecisionKeep:    "keep",
+					TagValueIDSamplingDecisionDiscard: "discard",
+				}),
+			}},
+		},
 	}
 
 	builtinMetricsInvisible = map[int32]bool{
@@ -2006,6 +2138,8 @@ Value is delta between second value and time it was inserted.`,
 		BuiltinMetricIDUIErrors:                   true,
 		BuiltinMetricIDStatsHouseErrors:           true,
 		BuiltinMetricIDPromQLEngineTime:           true,
+		BuiltinMetricIDAlertRuleState:             true,
+		BuiltinMetricIDRecordingRuleEval:          true,
 	}
 
 	builtinMetricsNoSamplingAgent = map[int32]bool{
@@ -2108,6 +2242,8 @@ Value is delta between second value and time it was inserted.`,
 		BuiltinMetricIDUIErrors:                   true,
 		BuiltinMetricIDStatsHouseErrors:           true,
 		BuiltinMetricIDPromQLEngineTime:           true,
+		BuiltinMetricIDAlertRuleState:             true,
+		BuiltinMetricIDRecordingRuleEval:          true,
 	}
 
 	insertKindToValue = map[int32]string{
@@ -2122,6 +2258,12 @@ Value is delta between second value and time it was inserted.`,
 		TagValueIDSizeBuiltIn:           "builtin",          // used only by aggregator
 	}
 
+	alertStateToValue = map[int32]string{
+		TagValueIDAlertStateInactive: "inactive",
+		TagValueIDAlertStatePending:  "pending",
+		TagValueIDAlertStateFiring:   "firing",
+	}
+
 	conveyorToValue = map[int32]string{
 		TagValueIDConveyorRecent:   "recent",
 		TagValueIDConveyorHistoric: "historic",
@@ -2136,13 +2278,17 @@ Value is delta between second value and time it was inserted.`,
 	}
 
 	packetFormatToValue = map[int32]string{
-		TagValueIDPacketFormatLegacy:   "legacy",
-		TagValueIDPacketFormatTL:       "tl",
-		TagValueIDPacketFormatMsgPack:  "msgpack",
-		TagValueIDPacketFormatJSON:     "json",
-		TagValueIDPacketFormatProtobuf: "protobuf",
-		TagValueIDPacketFormatRPC:      "rpc",
-		TagValueIDPacketFormatEmpty:    "empty",
+		TagValueIDPacketFormatLegacy:          "legacy",
+		TagValueIDPacketFormatTL:              "tl",
+		TagValueIDPacketFormatMsgPack:         "msgpack",
+		TagValueIDPacketFormatJSON:            "json",
+		TagValueIDPacketFormatProtobuf:        "protobuf",
+		TagValueIDPacketFormatRPC:             "rpc",
+		TagValueIDPacketFormatEmpty:           "empty",
+		TagValueIDPacketFormatOTLP:            "otlp",
+		TagValueIDPacketFormatStatsD:          "statsd",
+		TagValueIDPacketFormatPromRemoteWrite: "prom_remote_write",
+		TagValueIDPacketFormatStream:          "stream",
 	}
 
 	aggregatorRoleToValue = map[int32]string{
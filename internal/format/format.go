@@ -8,13 +8,16 @@ package format
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"log"
 	"math"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -40,6 +43,13 @@ const (
 	MaxEffectiveGroupWeight     = 10_000 * EffectiveWeightOne
 	MaxEffectiveNamespaceWeight = 10_000 * EffectiveWeightOne
 
+	RuleDefaultInterval = 60 // seconds, for alert and recording rules
+	RuleMaxInterval     = 86400
+	AlertRuleMaxFor     = 7 * 86400
+
+	AnnotationMaxTextLen  = 4096
+	AnnotationMaxAgeOfEnd = 90 * 86400 // older annotations are hidden
+
 	StringTopTagID            = "_s"
 	HostTagID                 = "_h"
 	ShardTagID                = "_shard_num"
@@ -52,6 +62,8 @@ const (
 	HistogramBucketsEndMark   = "$"
 	HistogramBucketsEndMarkC  = '$'
 
+	MetricOptionsVersion = 1 // options are set by fields, not by magic words in description
+
 	LETagIndex        = 15
 	StringTopTagIndex = -1 // used as flag during mapping
 	HostTagIndex      = -2 // used as flag during mapping
@@ -87,6 +99,15 @@ const (
 	MetricKindMixedPercentiles = "mixed_p"
 )
 
+// Metric lifecycle, each state change is saved as new metric version, so is recorded in entity history.
+// Do not change values, they are stored in DB
+const (
+	MetricStateActive     = ""
+	MetricStateDeprecated = "deprecated" // written, agents report ingestion warning
+	MetricStateDisabled   = "disabled"   // dropped by agents
+	MetricStateDeleted    = "deleted"    // dropped by agents, data is deleted from ClickHouse by aggregator after delay
+)
+
 const (
 	MetricSecond      = "second"
 	MetricMillisecond = "millisecond"
@@ -105,6 +126,13 @@ const (
 	*/
 )
 
+// Roles granted to users per namespace or group, do not change values, they are stored in DB
+const (
+	RoleViewer = "viewer" // view metrics
+	RoleEditor = "editor" // viewer, plus edit metrics and dashboards
+	RoleOwner  = "owner"  // editor, plus grant roles
+)
+
 // Legacy, left for API backward compatibility
 const (
 	LegacyStringTopTagID      = "skey"
@@ -159,11 +187,15 @@ type MetricMetaTag struct {
 }
 
 const (
-	MetricEvent       int32 = 0
-	DashboardEvent    int32 = 1
-	MetricsGroupEvent int32 = 2
-	PromConfigEvent   int32 = 3
-	NamespaceEvent    int32 = 4
+	MetricEvent        int32 = 0
+	DashboardEvent     int32 = 1
+	MetricsGroupEvent  int32 = 2
+	PromConfigEvent    int32 = 3
+	NamespaceEvent     int32 = 4
+	AlertRuleEvent     int32 = 5
+	RecordingRuleEvent int32 = 6
+	AnnotationEvent    int32 = 7
+	APITokenEvent      int32 = 8
 )
 
 type NamespaceMeta struct {
@@ -173,8 +205,14 @@ type NamespaceMeta struct {
 	UpdateTime uint32 `json:"update_time"`
 	DeleteTime uint32 `json:"delete_time"`
 
-	Weight  float64 `json:"weight"`
-	Disable bool    `json:"disable"`
+	Weight  float64           `json:"weight"`
+	Disable bool              `json:"disable"`
+	Roles   map[string]string `json:"roles,omitempty"` // user -> role
+
+	// hard limits enforced by aggregators, series above limits are discarded, 0 means no limit
+	QuotaRowsPerSec         int64 `json:"quota_rows_per_sec,omitempty"`
+	QuotaBytesPerSec        int64 `json:"quota_bytes_per_sec,omitempty"`
+	QuotaCardinalityPerHour int64 `json:"quota_cardinality_per_hour,omitempty"`
 
 	EffectiveWeight int64 `json:"-"`
 }
@@ -190,6 +228,67 @@ type DashboardMeta struct {
 	JSONData   map[string]interface{} `json:"data"`        // TODO - there must be a better way?
 }
 
+// This struct is immutable, it is accessed by alerting code without any locking
+type AlertRule struct {
+	ID         int32  `json:"alert_rule_id"`
+	Name       string `json:"name"`
+	Version    int64  `json:"version,omitempty"`
+	UpdateTime uint32 `json:"update_time"`
+	DeleteTime uint32 `json:"delete_time"`
+
+	Expr        string            `json:"expr"`     // PromQL, every returned series is an alert
+	For         int64             `json:"for"`      // seconds condition must hold before alert fires
+	Interval    int64             `json:"interval"` // evaluation interval in seconds, 0 means RuleDefaultInterval
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Webhooks    []string          `json:"webhooks,omitempty"` // Alertmanager compatible receivers
+	Disable     bool              `json:"disable"`
+}
+
+// This struct is immutable, it is accessed by recording rules code without any locking
+type RecordingRule struct {
+	ID         int32  `json:"recording_rule_id"`
+	Name       string `json:"name"` // target metric, must exist
+	Version    int64  `json:"version,omitempty"`
+	UpdateTime uint32 `json:"update_time"`
+	DeleteTime uint32 `json:"delete_time"`
+
+	Expr     string            `json:"expr"`     // PromQL, every returned series is written as target metric value with series labels as tags
+	Interval int64             `json:"interval"` // evaluation interval in seconds, 0 means RuleDefaultInterval
+	Labels   map[string]string `json:"labels,omitempty"`
+	Disable  bool              `json:"disable"`
+}
+
+// This struct is immutable, it is accessed by series queries without any locking
+type Annotation struct {
+	ID         int32  `json:"annotation_id"`
+	Name       string `json:"name"` // short title shown on graph, not unique
+	Version    int64  `json:"version,omitempty"`
+	UpdateTime uint32 `json:"update_time"`
+	DeleteTime uint32 `json:"delete_time"`
+
+	From      int64             `json:"from"` // unix seconds
+	To        int64             `json:"to"`   // unix seconds, equal to "from" for instant events
+	Text      string            `json:"text,omitempty"`
+	Namespace string            `json:"namespace,omitempty"` // shown on metrics of this namespace only
+	Metric    string            `json:"metric,omitempty"`    // shown on this metric only
+	Tags      map[string]string `json:"tags,omitempty"`      // metric tag name or ID to raw value, hidden if query filters out value
+}
+
+// This struct is immutable, it is accessed by API access checks without any locking
+type APIToken struct {
+	ID         int32  `json:"api_token_id"`
+	Name       string `json:"name"`
+	Version    int64  `json:"version,omitempty"`
+	UpdateTime uint32 `json:"update_time"`
+	DeleteTime uint32 `json:"delete_time"` // revoked tokens are deleted
+
+	Hash       string   `json:"hash,omitempty"` // hex SHA-256 of token, token itself is never stored
+	ReadOnly   bool     `json:"read_only"`
+	Namespaces []string `json:"namespaces,omitempty"` // empty means all namespaces
+	ExpireTime uint32   `json:"expire_time"`          // unix seconds
+}
+
 // This struct is immutable, it is accessed by mapping code without any locking
 type MetricsGroup struct {
 	ID          int32  `json:"group_id"`
@@ -198,8 +297,9 @@ type MetricsGroup struct {
 	Version     int64  `json:"version"`
 	UpdateTime  uint32 `json:"update_time"`
 
-	Weight  float64 `json:"weight,omitempty"`
-	Disable bool    `json:"disable,omitempty"`
+	Weight  float64           `json:"weight,omitempty"`
+	Disable bool              `json:"disable,omitempty"`
+	Roles   map[string]string `json:"roles,omitempty"` // user -> role
 
 	EffectiveWeight int64          `json:"-"`
 	Namespace       *NamespaceMeta `json:"-"`
@@ -230,18 +330,34 @@ type MetricMetaValue struct {
 	PreKeyOnly           bool                     `json:"pre_key_only,omitempty"`
 	MetricType           string                   `json:"metric_type"`
 	FairKeyTagID         string                   `json:"fair_key_tag_id,omitempty"`
+	State                string                   `json:"state,omitempty"`      // lifecycle, see MetricState* constants
+	PurgeTime            uint32                   `json:"purge_time,omitempty"` // set by aggregator after data of deleted metric is deleted from ClickHouse
+	StateTime            uint32                   `json:"state_time,omitempty"` // time of last state change, data of deleted metric is deleted after delay from it
+
+	// Sampling policy, see data_model.Sampler
+	SampleKeepTagID     string   `json:"sample_keep_tag_id,omitempty"` // series with one of SampleKeepValues in this tag are never sampled
+	SampleKeepValues    []string `json:"sample_keep_values,omitempty"`
+	SampleKeepValueIDs  []int32  `json:"sample_keep_value_ids,omitempty"`  // SampleKeepValues mapped by API when metric is saved
+	SampleStratifyTagID string   `json:"sample_stratify_tag_id,omitempty"` // sampler keeps at least SampleStratifyMin series per value of this tag
+	SampleStratifyMin   int      `json:"sample_stratify_min,omitempty"`
+	SampleMinBudget     int64    `json:"sample_min_budget,omitempty"` // bytes, sampler never gives metric less, unless such metrics together exceed half of budget
+
+	// Options, were set by magic words in description before OptionsVersion 1
+	OptionsVersion     int       `json:"options_version,omitempty"`      // see MetricOptionsVersion
+	RoundSampleFactors bool      `json:"round_sample_factors,omitempty"` // Experimental
+	ShardUniqueValues  bool      `json:"shard_unique_values,omitempty"`  // Experimental
+	HistogramBuckets   []float32 `json:"histogram_buckets,omitempty"`    // Prometheus histogram buckets, counters only
 
 	RawTagMask          uint32                   `json:"-"` // Should be restored from Tags after reading
 	Name2Tag            map[string]MetricMetaTag `json:"-"` // Should be restored from Tags after reading
 	EffectiveResolution int                      `json:"-"` // Should be restored from Tags after reading
 	PreKeyIndex         int                      `json:"-"` // index of tag which goes to 'prekey' column, or <0 if no tag goes
 	FairKeyIndex        int                      `json:"-"`
+	SampleKeepIndex     int                      `json:"-"`
+	SampleStratifyIndex int                      `json:"-"`
 	EffectiveWeight     int64                    `json:"-"`
 	HasPercentiles      bool                     `json:"-"`
-	RoundSampleFactors  bool                     `json:"-"` // Experimental, set if magic word in description is found
-	ShardUniqueValues   bool                     `json:"-"` // Experimental, set if magic word in description is found
 	NoSampleAgent       bool                     `json:"-"` // Built-in metrics with fixed/limited # of rows on agent
-	HistorgamBuckets    []float32                `json:"-"` // Prometheus histogram buckets
 
 	GroupID int32 `json:"-"`
 
@@ -349,6 +465,9 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 	if !ValidMetricKind(m.Kind) {
 		err = multierr.Append(err, fmt.Errorf("invalid metric kind %q", m.Kind))
 	}
+	if !ValidMetricState(m.State) {
+		err = multierr.Append(err, fmt.Errorf("invalid metric state %q", m.State))
+	}
 
 	var mask uint32
 	m.Name2Tag = map[string]MetricMetaTag{}
@@ -370,6 +489,8 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 	}
 	m.PreKeyIndex = -1
 	m.FairKeyIndex = FairKeyIndexUnspecified
+	m.SampleKeepIndex = -1
+	m.SampleStratifyIndex = -1
 	tags := m.Tags
 	if len(tags) > MaxTags { // prevent index out of range during mapping
 		tags = tags[:MaxTags]
@@ -395,6 +516,12 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 		if m.FairKeyTagID == tagID { // restore fair key index
 			m.FairKeyIndex = i
 		}
+		if m.SampleKeepTagID == tagID {
+			m.SampleKeepIndex = i
+		}
+		if m.SampleStratifyTagID == tagID {
+			m.SampleStratifyIndex = i
+		}
 		if !ValidRawKind(tag.RawKind) {
 			err = multierr.Append(err, fmt.Errorf("invalid raw kind %q of tag %d", tag.RawKind, i))
 		}
@@ -409,6 +536,20 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 	if m.FairKeyIndex == FairKeyIndexUnspecified && m.FairKeyTagID != "" {
 		err = multierr.Append(err, fmt.Errorf("invalid fair_key_tag_id: %q", m.FairKeyTagID))
 	}
+	if m.SampleKeepIndex == -1 && (m.SampleKeepTagID != "" || len(m.SampleKeepValues) != 0) {
+		err = multierr.Append(err, fmt.Errorf("invalid sample_keep_tag_id: %q", m.SampleKeepTagID))
+	}
+	if m.SampleStratifyIndex == -1 && m.SampleStratifyTagID != "" {
+		err = multierr.Append(err, fmt.Errorf("invalid sample_stratify_tag_id: %q", m.SampleStratifyTagID))
+	}
+	if m.SampleStratifyMin < 0 || (m.SampleStratifyMin == 0 && m.SampleStratifyTagID != "") {
+		err = multierr.Append(err, fmt.Errorf("sample_stratify_min must be positive"))
+		m.SampleStratifyMin = 0
+	}
+	if m.SampleMinBudget < 0 {
+		err = multierr.Append(err, fmt.Errorf("sample_min_budget must not be negative"))
+		m.SampleMinBudget = 0
+	}
 	for i := range tags {
 		tag := &tags[i]
 		if tag.Raw {
@@ -463,25 +604,21 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 		}
 	}
 	m.HasPercentiles = m.Kind == MetricKindValuePercentiles || m.Kind == MetricKindMixedPercentiles
-	m.RoundSampleFactors = strings.Contains(m.Description, "__round_sample_factors") // Experimental
-	m.ShardUniqueValues = strings.Contains(m.Description, "__shard_unique_values")   // Experimental
-	if m.Kind == MetricKindCounter {
-		if i := strings.Index(m.Description, HistogramBucketsStartMark); i != -1 {
-			s := m.Description[i+len(HistogramBucketsStartMark):]
-			if i = strings.Index(s, HistogramBucketsEndMark); i != -1 {
-				s = s[:i]
-				m.HistorgamBuckets = make([]float32, 0, strings.Count(s, HistogramBucketsDelim)+1)
-				for i, j := 0, 1; i < len(s); {
-					for j < len(s) && s[j] != HistogramBucketsDelimC {
-						j++
-					}
-					if f, err := strconv.ParseFloat(s[i:j], 32); err == nil {
-						m.HistorgamBuckets = append(m.HistorgamBuckets, float32(f))
-					}
-					i = j + 1
-					j = i + 1
-				}
-			}
+	if m.OptionsVersion == 0 {
+		m.migrateOptions()
+	}
+	if m.OptionsVersion != MetricOptionsVersion {
+		err = multierr.Append(err, fmt.Errorf("unknown options_version %d", m.OptionsVersion))
+	}
+	if len(m.HistogramBuckets) != 0 && m.Kind != MetricKindCounter {
+		err = multierr.Append(err, fmt.Errorf("histogram_buckets require %q kind", MetricKindCounter))
+		m.HistogramBuckets = nil
+	}
+	for i, b := range m.HistogramBuckets {
+		if math.IsNaN(float64(b)) || (i != 0 && b <= m.HistogramBuckets[i-1]) {
+			err = multierr.Append(err, fmt.Errorf("histogram_buckets must be sorted in ascending order without duplicates"))
+			m.HistogramBuckets = nil
+			break
 		}
 	}
 	m.NoSampleAgent = builtinMetricsNoSamplingAgent[m.MetricID]
@@ -496,6 +633,51 @@ func (m *MetricMetaValue) RestoreCachedInfo() error {
 	return err
 }
 
+// Options were set by magic words in description, so changing description could change aggregation
+func (m *MetricMetaValue) migrateOptions() {
+	m.OptionsVersion = MetricOptionsVersion
+	m.RoundSampleFactors = m.RoundSampleFactors || strings.Contains(m.Description, "__round_sample_factors")
+	m.ShardUniqueValues = m.ShardUniqueValues || strings.Contains(m.Description, "__shard_unique_values")
+	if m.Kind != MetricKindCounter || len(m.HistogramBuckets) != 0 {
+		return
+	}
+	if i := strings.Index(m.Description, HistogramBucketsStartMark); i != -1 {
+		s := m.Description[i+len(HistogramBucketsStartMark):]
+		if i = strings.Index(s, HistogramBucketsEndMark); i != -1 {
+			s = s[:i]
+			buckets := make([]float32, 0, strings.Count(s, HistogramBucketsDelim)+1)
+			for i, j := 0, 1; i < len(s); {
+				for j < len(s) && s[j] != HistogramBucketsDelimC {
+					j++
+				}
+				if f, err := strconv.ParseFloat(s[i:j], 32); err == nil && !math.IsNaN(f) {
+					buckets = append(buckets, float32(f))
+				}
+				i = j + 1
+				j = i + 1
+			}
+			sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+			for i := range buckets {
+				if i == 0 || buckets[i] != buckets[i-1] {
+					m.HistogramBuckets = append(m.HistogramBuckets, buckets[i])
+				}
+			}
+		}
+	}
+}
+
+// CopyOptions is for clients unaware of options, so they can't change options by editing description,
+// histogram buckets are dropped if kind is changed from counter, so such clients still can change kind
+func (m *MetricMetaValue) CopyOptions(from *MetricMetaValue) {
+	m.OptionsVersion = from.OptionsVersion
+	m.RoundSampleFactors = from.RoundSampleFactors
+	m.ShardUniqueValues = from.ShardUniqueValues
+	m.HistogramBuckets = nil
+	if m.Kind == MetricKindCounter {
+		m.HistogramBuckets = from.HistogramBuckets
+	}
+}
+
 // 'APICompat' functions are expected to be used to handle user input, exists for backward compatibility
 func (m *MetricMetaValue) APICompatGetTag(tagNameOrID string) (tag MetricMetaTag, ok bool, legacyName bool) {
 	if res, ok := m.Name2Tag[tagNameOrID]; ok {
@@ -550,6 +732,9 @@ func (m *MetricsGroup) RestoreCachedInfo(builtin bool) error {
 		m.NamespaceID = BuiltinNamespaceIDDefault
 		m.Namespace = BuiltInNamespaceDefault[BuiltinNamespaceIDDefault]
 	}
+	if rolesErr := validateRoles(m.Roles); rolesErr != nil {
+		err = rolesErr
+	}
 	return err
 }
 
@@ -571,9 +756,193 @@ func (m *NamespaceMeta) RestoreCachedInfo(builtin bool) error {
 	if m.EffectiveWeight > MaxEffectiveNamespaceWeight {
 		m.EffectiveWeight = MaxEffectiveNamespaceWeight
 	}
+	if rolesErr := validateRoles(m.Roles); rolesErr != nil {
+		err = rolesErr
+	}
+	if m.QuotaRowsPerSec < 0 || m.QuotaBytesPerSec < 0 || m.QuotaCardinalityPerHour < 0 {
+		err = fmt.Errorf("quotas must not be negative")
+	}
 	return err
 }
 
+func (m *NamespaceMeta) HasQuota() bool {
+	return m.QuotaRowsPerSec > 0 || m.QuotaBytesPerSec > 0 || m.QuotaCardinalityPerHour > 0
+}
+
+// RoleLevel returns 0 for unknown role, so that comparison with any known role fails
+func RoleLevel(role string) int {
+	switch role {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleOwner:
+		return 3
+	}
+	return 0
+}
+
+func validateRoles(roles map[string]string) error {
+	for user, role := range roles {
+		if user == "" {
+			return fmt.Errorf("role %q is granted to empty user", role)
+		}
+		if RoleLevel(role) == 0 {
+			return fmt.Errorf("invalid role %q of user %q, must be one of %q, %q, %q", role, user, RoleViewer, RoleEditor, RoleOwner)
+		}
+	}
+	return nil
+}
+
+func (m *AlertRule) Validate() error {
+	if !ValidDashboardName(m.Name) {
+		return fmt.Errorf("invalid alert rule name: %q", m.Name)
+	}
+	if m.Expr == "" {
+		return fmt.Errorf("alert rule expression must be set")
+	}
+	if m.For < 0 || m.For > AlertRuleMaxFor {
+		return fmt.Errorf("alert rule 'for' must be from %d to %d seconds", 0, AlertRuleMaxFor)
+	}
+	if m.Interval < 0 || m.Interval > RuleMaxInterval {
+		return fmt.Errorf("alert rule interval must be from %d to %d seconds", 0, RuleMaxInterval)
+	}
+	for _, w := range m.Webhooks {
+		if !strings.HasPrefix(w, "http://") && !strings.HasPrefix(w, "https://") {
+			return fmt.Errorf("alert rule webhook %q must be http or https URL", w)
+		}
+	}
+	return nil
+}
+
+func (m *AlertRule) EffectiveInterval() int64 {
+	if m.Interval == 0 {
+		return RuleDefaultInterval
+	}
+	return m.Interval
+}
+
+func (m *RecordingRule) Validate() error {
+	if !ValidMetricName(mem.S(m.Name)) {
+		return fmt.Errorf("invalid recording rule metric name: %q", m.Name)
+	}
+	if m.Expr == "" {
+		return fmt.Errorf("recording rule expression must be set")
+	}
+	if m.Interval < 0 || m.Interval > RuleMaxInterval {
+		return fmt.Errorf("recording rule interval must be from %d to %d seconds", 0, RuleMaxInterval)
+	}
+	return nil
+}
+
+func (m *RecordingRule) EffectiveInterval() int64 {
+	if m.Interval == 0 {
+		return RuleDefaultInterval
+	}
+	return m.Interval
+}
+
+func (m *Annotation) Validate() error {
+	if !ValidDashboardName(m.Name) {
+		return fmt.Errorf("invalid annotation name: %q", m.Name)
+	}
+	if m.From <= 0 || m.To < m.From {
+		return fmt.Errorf("invalid annotation time range [%d, %d]", m.From, m.To)
+	}
+	if len(m.Text) > AnnotationMaxTextLen {
+		return fmt.Errorf("annotation text must not be longer than %d bytes", AnnotationMaxTextLen)
+	}
+	if m.Metric != "" && !ValidMetricName(mem.S(m.Metric)) {
+		return fmt.Errorf("invalid annotation metric name: %q", m.Metric)
+	}
+	if m.Namespace != "" && !ValidGroupName(m.Namespace) {
+		return fmt.Errorf("invalid annotation namespace name: %q", m.Namespace)
+	}
+	if m.Metric != "" && m.Namespace != "" {
+		if _, ns := SplitNamespace(m.Metric); ns != m.Namespace {
+			return fmt.Errorf("annotation metric %q is not in namespace %q", m.Metric, m.Namespace)
+		}
+	}
+	if len(m.Tags) != 0 && m.Metric == "" {
+		return fmt.Errorf("annotation tag filters require metric")
+	}
+	return nil
+}
+
+func (m *APIToken) Validate() error {
+	if !ValidDashboardName(m.Name) {
+		return fmt.Errorf("invalid API token name: %q", m.Name)
+	}
+	if len(m.Hash) != 2*sha256.Size {
+		return fmt.Errorf("invalid API token hash")
+	}
+	if m.ExpireTime == 0 {
+		return fmt.Errorf("API token expire time must be set")
+	}
+	for _, ns := range m.Namespaces {
+		if !ValidGroupName(ns) {
+			return fmt.Errorf("invalid API token namespace name: %q", ns)
+		}
+	}
+	return nil
+}
+
+func (m *APIToken) Expired(now time.Time) bool {
+	return now.Unix() >= int64(m.ExpireTime)
+}
+
+// deleted or too old annotation is not shown
+func (m *Annotation) Expired(now time.Time) bool {
+	return m.DeleteTime > 0 || m.To < now.Unix()-AnnotationMaxAgeOfEnd
+}
+
+// namespace of metrics annotation is shown on, empty for global annotations and metrics without namespace
+func (m *Annotation) EffectiveNamespace() string {
+	if m.Namespace != "" {
+		return m.Namespace
+	}
+	_, ns := SplitNamespace(m.Metric)
+	return ns
+}
+
+// Reports whether annotation overlaps [from, to) and is shown on metric,
+// "filterIn" is metric query filter by tag ID, nil metric matches annotations without filters only.
+func (m *Annotation) Matches(metric *MetricMetaValue, from, to int64, filterIn map[string][]string) bool {
+	if m.DeleteTime > 0 || m.To < from || m.From >= to {
+		return false
+	}
+	if metric == nil {
+		return m.Namespace == "" && m.Metric == ""
+	}
+	if m.Metric != "" && m.Metric != metric.Name {
+		return false
+	}
+	if m.Namespace != "" {
+		if _, ns := SplitNamespace(metric.Name); ns != m.Namespace {
+			return false
+		}
+	}
+	for k, v := range m.Tags {
+		tag, ok, _ := metric.APICompatGetTag(k)
+		if !ok {
+			return false
+		}
+		if vs := filterIn[TagID(tag.Index)]; len(vs) != 0 && !containsString(vs, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(s []string, v string) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *MetricsGroup) MetricIn(metric *MetricMetaValue) bool {
 	return !m.Disable && strings.HasPrefix(metric.Name, m.Name)
 }
@@ -650,6 +1019,23 @@ func ValidMetricKind(kind string) bool {
 	return false
 }
 
+func ValidMetricState(state string) bool {
+	switch state {
+	case MetricStateActive, MetricStateDeprecated, MetricStateDisabled, MetricStateDeleted:
+		return true
+	}
+	return false
+}
+
+// state change time is set when metric is saved, old is nil for new metrics
+func (m *MetricMetaValue) SetStateTime(old *MetricMetaValue, now time.Time) {
+	if old == nil || old.State != m.State {
+		m.StateTime = uint32(now.Unix())
+	} else {
+		m.StateTime = old.StateTime
+	}
+}
+
 func ValidRawKind(s string) bool {
 	// Do not change values, they are stored in DB
 	// uint:            interpret number bits as uint32, print as decimal number
@@ -1064,6 +1450,14 @@ func EventTypeToName(typ int32) string {
 		return "prom-config"
 	case NamespaceEvent:
 		return "namespace"
+	case AlertRuleEvent:
+		return "alert-rule"
+	case RecordingRuleEvent:
+		return "recording-rule"
+	case AnnotationEvent:
+		return "annotation"
+	case APITokenEvent:
+		return "api-token"
 	default:
 		return "unknown"
 	}
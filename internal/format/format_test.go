@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
@@ -257,3 +258,104 @@ func TestValidDashboardName(t *testing.T) {
 		})
 	}
 }
+
+func TestAnnotationMatches(t *testing.T) {
+	metric := &MetricMetaValue{
+		Name:       "ns" + NamespaceSeparator + "requests",
+		Kind:       MetricKindCounter,
+		Resolution: 1,
+		Tags:       []MetricMetaTag{{Name: "env"}, {Name: "service"}},
+	}
+	require.NoError(t, metric.RestoreCachedInfo())
+	tests := []struct {
+		name     string
+		a        Annotation
+		filterIn map[string][]string
+		want     bool
+	}{
+		{"global", Annotation{From: 100, To: 100}, nil, true},
+		{"before", Annotation{From: 50, To: 99}, nil, false},
+		{"after", Annotation{From: 200, To: 300}, nil, false},
+		{"deleted", Annotation{From: 100, To: 200, DeleteTime: 1}, nil, false},
+		{"namespace", Annotation{From: 100, To: 200, Namespace: "ns"}, nil, true},
+		{"other namespace", Annotation{From: 100, To: 200, Namespace: "other"}, nil, false},
+		{"metric", Annotation{From: 100, To: 200, Metric: metric.Name}, nil, true},
+		{"other metric", Annotation{From: 100, To: 200, Metric: "requests"}, nil, false},
+		{"tag not filtered", Annotation{From: 100, To: 200, Metric: metric.Name, Tags: map[string]string{"service": "api"}}, nil, true},
+		{"tag filtered in", Annotation{From: 100, To: 200, Metric: metric.Name, Tags: map[string]string{"service": "api"}}, map[string][]string{"1": {"api", "web"}}, true},
+		{"tag filtered out", Annotation{From: 100, To: 200, Metric: metric.Name, Tags: map[string]string{"1": "api"}}, map[string][]string{"1": {"web"}}, false},
+		{"unknown tag", Annotation{From: 100, To: 200, Metric: metric.Name, Tags: map[string]string{"host": "a"}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.a.Matches(metric, 100, 200, tt.filterIn))
+		})
+	}
+	require.False(t, (&Annotation{From: 100, To: 200, Namespace: "ns"}).Matches(nil, 100, 200, nil))
+}
+
+func TestAnnotationExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	require.False(t, (&Annotation{From: 100, To: now.Unix()}).Expired(now))
+	require.False(t, (&Annotation{From: 100, To: now.Unix() - AnnotationMaxAgeOfEnd}).Expired(now))
+	require.True(t, (&Annotation{From: 100, To: now.Unix() - AnnotationMaxAgeOfEnd - 1}).Expired(now))
+	require.True(t, (&Annotation{From: 100, To: now.Unix(), DeleteTime: 1}).Expired(now))
+	require.Equal(t, "ns", (&Annotation{Namespace: "ns", Metric: "ns:requests"}).EffectiveNamespace())
+	require.Equal(t, "ns", (&Annotation{Metric: "ns:requests"}).EffectiveNamespace())
+	require.Equal(t, "", (&Annotation{Metric: "requests"}).EffectiveNamespace())
+}
+
+func TestSamplingPolicyRestore(t *testing.T) {
+	metric := &MetricMetaValue{
+		Name:                "errors",
+		Resolution:          1,
+		Kind:                MetricKindCounter,
+		Tags:                []MetricMetaTag{{}, {Name: "status"}, {Name: "service"}},
+		SampleKeepTagID:     "1",
+		SampleKeepValues:    []string{"error"},
+		SampleStratifyTagID: "2",
+		SampleStratifyMin:   3,
+	}
+	require.NoError(t, metric.RestoreCachedInfo())
+	require.Equal(t, 1, metric.SampleKeepIndex)
+	require.Equal(t, 2, metric.SampleStratifyIndex)
+
+	metric.SampleKeepTagID = "5"
+	metric.SampleStratifyMin = 0
+	metric.SampleMinBudget = -1
+	err := metric.RestoreCachedInfo()
+	require.ErrorContains(t, err, "invalid sample_keep_tag_id")
+	require.ErrorContains(t, err, "sample_stratify_min must be positive")
+	require.ErrorContains(t, err, "sample_min_budget must not be negative")
+	require.Equal(t, -1, metric.SampleKeepIndex)
+	require.Equal(t, int64(0), metric.SampleMinBudget)
+}
+
+func TestMetricOptionsMigration(t *testing.T) {
+	metric := &MetricMetaValue{
+		Name:        "latency",
+		Kind:        MetricKindCounter,
+		Resolution:  1,
+		Description: "latency __round_sample_factors\n\nBuckets$0.5,0.1,1,1$",
+	}
+	require.NoError(t, metric.RestoreCachedInfo())
+	require.Equal(t, MetricOptionsVersion, metric.OptionsVersion)
+	require.True(t, metric.RoundSampleFactors)
+	require.False(t, metric.ShardUniqueValues)
+	require.Equal(t, []float32{0.1, 0.5, 1}, metric.HistogramBuckets)
+
+	// after migration description does not affect options
+	metric.Description = "__shard_unique_values"
+	require.NoError(t, metric.RestoreCachedInfo())
+	require.True(t, metric.RoundSampleFactors)
+	require.False(t, metric.ShardUniqueValues)
+	require.Equal(t, []float32{0.1, 0.5, 1}, metric.HistogramBuckets)
+
+	metric.Kind = MetricKindValue
+	require.ErrorContains(t, metric.RestoreCachedInfo(), "histogram_buckets require")
+	metric.Kind = MetricKindCounter
+	metric.HistogramBuckets = []float32{1, 0.5}
+	require.ErrorContains(t, metric.RestoreCachedInfo(), "histogram_buckets must be sorted")
+	metric.OptionsVersion = 2
+	require.ErrorContains(t, metric.RestoreCachedInfo(), "unknown options_version")
+}
@@ -37,6 +37,26 @@ func NewTagsCache(loader pcache.LoaderFunc, suffix string, dc *pcache.DiskCache)
 	return result
 }
 
+// NewTagsInverseCache caches tag value ID -> string mappings, keys are decimal IDs
+func NewTagsInverseCache(loader pcache.LoaderFunc, suffix string, dc *pcache.DiskCache) *pcache.Cache {
+	result := &pcache.Cache{
+		Loader:                  loader,
+		DiskCache:               dc,
+		DiskCacheNamespace:      data_model.TagValueInvertDiskNamespace + suffix,
+		MaxMemCacheSize:         data_model.MappingMaxMemCacheSize,
+		MaxDiskCacheSize:        data_model.MappingMaxDiskCacheSize,
+		SpreadCacheTTL:          true,
+		DefaultCacheTTL:         data_model.MappingCacheTTLMinimum,
+		DefaultNegativeCacheTTL: data_model.MappingNegativeCacheTTL,
+		LoadMinInterval:         data_model.MappingMinInterval,
+		Empty: func() pcache.Value {
+			var empty pcache.StringValue
+			return &empty
+		},
+	}
+	return result
+}
+
 func NewMapper(suffix string, pmcLoader pcache.LoaderFunc, dc *pcache.DiskCache, ac *AutoCreate, metricMapQueueSize int, mapCallback data_model.MapCallbackFunc) *Mapper {
 	tagValue := NewTagsCache(pmcLoader, suffix, dc)
 
@@ -84,10 +84,18 @@ func (mp *mapPipeline) doMap(args data_model.HandlerArgs, h *data_model.MappedMe
 		}
 	}
 	h.Key.Metric = h.MetricInfo.MetricID
-	if !h.MetricInfo.Visible {
+	switch {
+	case !h.MetricInfo.Visible:
 		h.IngestionStatus = format.TagValueIDSrcIngestionStatusErrMetricInvisible
 		return true
+	case h.MetricInfo.State == format.MetricStateDisabled:
+		h.IngestionStatus = format.TagValueIDSrcIngestionStatusErrMetricStateDisabled
+		return true
+	case h.MetricInfo.State == format.MetricStateDeleted:
+		h.IngestionStatus = format.TagValueIDSrcIngestionStatusErrMetricStateDeleted
+		return true
 	}
+	h.MetricDeprecated = h.MetricInfo.State == format.MetricStateDeprecated
 	if done = mp.mapTags(h, metric, true); done {
 		return done
 	}
@@ -311,6 +319,10 @@ func MapErrorFromHeader(m tlstatshouse.MetricBytes, h data_model.MappedMetricHea
 		return nil // not written
 	case format.TagValueIDSrcIngestionStatusErrMetricInvisible:
 		return fmt.Errorf("metric %q is disabled (envTag %d)", m.Name, envTag)
+	case format.TagValueIDSrcIngestionStatusErrMetricStateDisabled:
+		return fmt.Errorf("metric %q is in disabled state (envTag %d)", m.Name, envTag)
+	case format.TagValueIDSrcIngestionStatusErrMetricStateDeleted:
+		return fmt.Errorf("metric %q is deleted (envTag %d)", m.Name, envTag)
 	case format.TagValueIDSrcIngestionStatusErrLegacyProtocol:
 		return nil // not written
 	case format.TagValueIDSrcIngestionStatusErrMetricNameEncoding:
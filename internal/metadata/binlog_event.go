@@ -196,6 +196,18 @@ func applyCreateMappingEvent(conn sqlite.Conn, event tlmetadata.CreateMappingEve
 		sqlite.BlobString("$name", event.Key),
 		sqlite.Int64("$id", int64(event.Id)),
 	)
+	if err != nil {
+		return err
+	}
+	return insertMappingInfo(conn, event.Id, event.Metric, event.UpdatedAt)
+}
+
+// creation info is not part of bootstrap and put mapping, so mappings created that way have no info
+func insertMappingInfo(conn sqlite.Conn, id int32, metricName string, createdAt uint32) error {
+	_, err := conn.Exec("insert_mapping_info", "INSERT OR REPLACE INTO mappings_info (id, metric, created_at) VALUES ($id, $metric, $t)",
+		sqlite.Int64("$id", int64(id)),
+		sqlite.BlobString("$metric", metricName),
+		sqlite.Int64("$t", int64(createdAt)))
 	return err
 }
 
@@ -290,6 +302,9 @@ func getOrCreateMapping(conn sqlite.Conn, cache []byte, metricName, key string,
 		return tlmetadata.GetMappingResponse{}, cache, fmt.Errorf("failed to insert mapping: %w", err)
 	}
 	id = int32(idResp)
+	if err = insertMappingInfo(conn, id, metricName, pred); err != nil {
+		return tlmetadata.GetMappingResponse{}, cache, fmt.Errorf("failed to insert mapping info: %w", err)
+	}
 	event := tlmetadata.CreateMappingEvent{
 		Id:        id,
 		Key:       key,
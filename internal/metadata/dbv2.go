@@ -8,6 +8,7 @@ package metadata
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/vkcom/statshouse/internal/data_model"
@@ -67,6 +68,12 @@ CREATE TABLE IF NOT EXISTS mappings
     id   INTEGER PRIMARY KEY AUTOINCREMENT,
     name TEXT UNIQUE
 );
+CREATE TABLE IF NOT EXISTS mappings_info
+(
+    id         INTEGER PRIMARY KEY,
+    metric     TEXT NOT NULL, -- metric for which mapping was created
+    created_at INTEGER NOT NULL -- unix ts rounded to flood limit step
+);
 
 CREATE TABLE IF NOT EXISTS flood_limits
 (
@@ -157,6 +164,7 @@ const metricCountReadLimit int64 = 1000
 const metricBytesReadLimit int64 = 1024 * 1024
 const maxResetLimit = 100_00
 const entityHistoryMaxResponseSize = 1024 * 1024 * 4
+const searchMappingsScanLimit = 100_000
 
 func OpenDB(
 	path string,
@@ -499,6 +507,48 @@ func (db *DBV2) GetMappingByID(ctx context.Context, id int32) (string, bool, err
 	return res, isExists, err
 }
 
+// SearchMappings returns up to limit mappings with id > fromID, ordered by id, which names have prefix and match re (if not nil).
+// Scan is bounded, so page can contain fewer than limit mappings even if there are more, nextID is 0 when scan reached the end.
+func (db *DBV2) SearchMappings(ctx context.Context, prefix string, re *regexp.Regexp, fromID int32, limit int) (mappings []tlmetadata.MappingInfo, nextID int32, err error) {
+	err = db.eng.Do(ctx, "search_mappings", func(conn sqlite.Conn, cache []byte) ([]byte, error) {
+		rows := conn.Query("search_mappings", `SELECT m.id, m.name, IFNULL(i.metric, ''), IFNULL(i.created_at, 0) FROM mappings m
+LEFT JOIN mappings_info i ON i.id = m.id
+WHERE m.id > $from AND substr(m.name, 1, $plen) = $prefix ORDER BY m.id LIMIT $limit`,
+			sqlite.Int64("$from", int64(fromID)),
+			sqlite.Int64("$plen", int64(len(prefix))),
+			sqlite.BlobString("$prefix", prefix),
+			sqlite.Int64("$limit", searchMappingsScanLimit))
+		scanned := 0
+		var lastID int32
+		for rows.Next() {
+			id, _ := rows.ColumnInt64(0)
+			name, _ := rows.ColumnBlobString(1)
+			metric, _ := rows.ColumnBlobString(2)
+			createdAt, _ := rows.ColumnInt64(3)
+			scanned++
+			lastID = int32(id)
+			if re != nil && !re.MatchString(name) {
+				continue
+			}
+			mappings = append(mappings, tlmetadata.MappingInfo{
+				Id:        lastID,
+				Name:      name,
+				Metric:    metric,
+				CreatedAt: uint32(createdAt),
+			})
+			if len(mappings) >= limit {
+				nextID = lastID
+				break
+			}
+		}
+		if nextID == 0 && scanned >= searchMappingsScanLimit {
+			nextID = lastID
+		}
+		return cache, rows.Error()
+	})
+	return mappings, nextID, err
+}
+
 func getMappingByID(conn sqlite.Conn, id int32) (k string, isExists bool, err error) {
 	row := conn.Query("select_mapping_by_id", "SELECT name FROM mappings where id = $id", sqlite.Int64("$id", int64(id)))
 	if row.Next() {
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"sort"
 	"strconv"
 	"sync"
@@ -176,6 +177,16 @@ func Test_SaveMetric_WithBadName(t *testing.T) {
 	require.ErrorIs(t, err, errMetricIsExist)
 }
 
+func Test_SaveAnnotation_WithSameName(t *testing.T) {
+	path := t.TempDir()
+	db, _ := initD1b(t, path, "db", true, nil)
+	a, err := db.SaveEntity(context.Background(), "deploy", 0, 0, "{}", true, false, format.AnnotationEvent, "")
+	require.NoError(t, err)
+	b, err := db.SaveEntity(context.Background(), "deploy", 0, 0, "{}", true, false, format.AnnotationEvent, "")
+	require.NoError(t, err)
+	require.NotEqual(t, a.Id, b.Id)
+}
+
 func Test_SaveMetric_WithBadNamespace(t *testing.T) {
 	path := t.TempDir()
 	db, _ := initD1b(t, path, "db", true, nil)
@@ -501,6 +512,47 @@ func TestDB_GetKeyMapping(t *testing.T) {
 	})
 }
 
+func TestDB_SearchMappings(t *testing.T) {
+	path := t.TempDir()
+	db, _ := initD1b(t, path, "db", true, nil)
+	db.stepSec = 5
+	db.now = func() time.Time { return time.Unix(1641027722, 0) }
+	var ids []int32
+	for _, k := range []string{"host-1", "host-2", "dc-1", "host-3"} {
+		id, err := unpackGetMappingUnion(db.GetOrCreateMapping(context.Background(), "abc", k))
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	require.NoError(t, db.PutMapping(context.Background(), []string{"host-4"}, []int32{ids[3] + 1}))
+
+	t.Run("prefix with pages", func(t *testing.T) {
+		mappings, nextID, err := db.SearchMappings(context.Background(), "host-", nil, 0, 2)
+		require.NoError(t, err)
+		require.Equal(t, []tlmetadata.MappingInfo{
+			{Id: ids[0], Name: "host-1", Metric: "abc", CreatedAt: 1641027720},
+			{Id: ids[1], Name: "host-2", Metric: "abc", CreatedAt: 1641027720},
+		}, mappings)
+		require.Equal(t, ids[1], nextID)
+		mappings, nextID, err = db.SearchMappings(context.Background(), "host-", nil, nextID, 2)
+		require.NoError(t, err)
+		require.Equal(t, []tlmetadata.MappingInfo{
+			{Id: ids[3], Name: "host-3", Metric: "abc", CreatedAt: 1641027720},
+			{Id: ids[3] + 1, Name: "host-4"}, // put mapping has no creation info
+		}, mappings)
+		mappings, nextID, err = db.SearchMappings(context.Background(), "host-", nil, nextID, 2)
+		require.NoError(t, err)
+		require.Empty(t, mappings)
+		require.Equal(t, int32(0), nextID)
+	})
+	t.Run("regex", func(t *testing.T) {
+		mappings, nextID, err := db.SearchMappings(context.Background(), "", regexp.MustCompile(`-[13]$`), 0, 10)
+		require.NoError(t, err)
+		require.Len(t, mappings, 3)
+		require.Equal(t, "dc-1", mappings[1].Name)
+		require.Equal(t, int32(0), nextID)
+	})
+}
+
 func TestDB_Bootstrap(t *testing.T) {
 	path := t.TempDir()
 	db, _ := initD1b(t, path, "db", true, nil)
@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
 	"sync"
 	"time"
 
@@ -26,6 +27,8 @@ import (
 
 const MaxBoostrapResponseSize = 1024 * 1024 // TODO move somewhere
 const longPollTimeout = time.Hour
+const searchMappingsDefaultLimit = 1000
+const searchMappingsMaxLimit = 100_000
 
 type Handler struct {
 	db *DBV2
@@ -303,6 +306,24 @@ func (h *Handler) ResetFlood2(ctx context.Context, args tlmetadata.ResetFlood2)
 	return tlmetadata.ResetFloodResponse2{BudgetBefore: int32(before), BudgetAfter: int32(after)}, args.Metric, err
 }
 
+func (h *Handler) SearchMappings(ctx context.Context, args tlmetadata.SearchMappings) (tlmetadata.SearchMappingsResponse, string, error) {
+	var re *regexp.Regexp
+	if args.Regex != "" {
+		var err error
+		if re, err = regexp.Compile(args.Regex); err != nil {
+			return tlmetadata.SearchMappingsResponse{}, "search_mappings", fmt.Errorf("failed to compile regex: %w", err)
+		}
+	}
+	limit := int(args.Limit)
+	if limit <= 0 {
+		limit = searchMappingsDefaultLimit
+	} else if limit > searchMappingsMaxLimit {
+		limit = searchMappingsMaxLimit
+	}
+	mappings, nextID, err := h.db.SearchMappings(ctx, args.Prefix, re, args.FromId, limit)
+	return tlmetadata.SearchMappingsResponse{Mappings: mappings, NextId: nextID}, "search_mappings", err
+}
+
 func (h *Handler) GetTagMappingBootstrap(ctx context.Context, args tlmetadata.GetTagMappingBootstrap) (tlstatshouse.GetTagMappingBootstrapResult, string, error) {
 	var ret tlstatshouse.GetTagMappingBootstrapResult
 
@@ -90,7 +90,7 @@ func resolveEntity(c sqlite.Conn, name string, id int64, oldVersion int64, newJs
 	if err != nil {
 		return namespaceID, err
 	}
-	if createEntity {
+	if createEntity && typ != format.AnnotationEvent { // annotation names are titles, not identifiers
 		err := checkCreateEntity(c, name, typ)
 		if err != nil {
 			return namespaceID, err
@@ -11,6 +11,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
@@ -31,7 +32,12 @@ type MetricsStorage struct {
 	metricsByID   map[int32]*format.MetricMetaValue
 	metricsByName map[string]*format.MetricMetaValue
 
-	dashboardByID map[int32]*format.DashboardMeta
+	dashboardByID  map[int32]*format.DashboardMeta
+	alertRuleByID  map[int32]*format.AlertRule
+	recordingByID  map[int32]*format.RecordingRule
+	annotationByID map[int32]*format.Annotation
+	apiTokenByID   map[int32]*format.APIToken
+	apiTokenByHash map[string]*format.APIToken
 
 	builtInGroup map[int32]*format.MetricsGroup
 	groupsByID   map[int32]*format.MetricsGroup
@@ -55,6 +61,11 @@ func MakeMetricsStorage(namespaceSuffix string, dc *pcache.DiskCache, applyPromC
 		metricsByID:      map[int32]*format.MetricMetaValue{},
 		metricsByName:    map[string]*format.MetricMetaValue{},
 		dashboardByID:    map[int32]*format.DashboardMeta{},
+		alertRuleByID:    map[int32]*format.AlertRule{},
+		recordingByID:    map[int32]*format.RecordingRule{},
+		annotationByID:   map[int32]*format.Annotation{},
+		apiTokenByID:     map[int32]*format.APIToken{},
+		apiTokenByHash:   map[string]*format.APIToken{},
 		builtInGroup:     map[int32]*format.MetricsGroup{},
 		groupsByID:       map[int32]*format.MetricsGroup{},
 		builtInNamespace: map[int32]*format.NamespaceMeta{},
@@ -139,6 +150,19 @@ func (ms *MetricsStorage) GetGroupByName(name string) *format.MetricsGroup {
 	return nil
 }
 
+// group metric with given name belongs to, metric itself may not exist yet
+func (ms *MetricsStorage) GetGroupByMetricName(name string) *format.MetricsGroup {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	metric := format.MetricMetaValue{Name: name}
+	for _, g := range ms.groupsByID {
+		if g.MetricIn(&metric) {
+			return g
+		}
+	}
+	return nil
+}
+
 func (ms *MetricsStorage) getMetaMetricByNameLocked(metricName string) *format.MetricMetaValue {
 	return ms.metricsByName[metricName]
 }
@@ -232,6 +256,90 @@ func (ms *MetricsStorage) GetDashboardList() []*format.DashboardMeta {
 	return li
 }
 
+func (ms *MetricsStorage) GetAlertRule(id int32) *format.AlertRule {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.alertRuleByID[id]
+}
+
+func (ms *MetricsStorage) GetAlertRuleList() []*format.AlertRule {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	li := make([]*format.AlertRule, 0, len(ms.alertRuleByID))
+	for _, v := range ms.alertRuleByID {
+		if v.DeleteTime > 0 {
+			continue
+		}
+		li = append(li, v)
+	}
+	return li
+}
+
+func (ms *MetricsStorage) GetRecordingRule(id int32) *format.RecordingRule {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.recordingByID[id]
+}
+
+func (ms *MetricsStorage) GetRecordingRuleList() []*format.RecordingRule {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	li := make([]*format.RecordingRule, 0, len(ms.recordingByID))
+	for _, v := range ms.recordingByID {
+		if v.DeleteTime > 0 {
+			continue
+		}
+		li = append(li, v)
+	}
+	return li
+}
+
+func (ms *MetricsStorage) GetAnnotation(id int32) *format.Annotation {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.annotationByID[id]
+}
+
+func (ms *MetricsStorage) GetAnnotationList() []*format.Annotation {
+	now := time.Now()
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	li := make([]*format.Annotation, 0, len(ms.annotationByID))
+	for _, v := range ms.annotationByID {
+		if v.Expired(now) {
+			continue
+		}
+		li = append(li, v)
+	}
+	return li
+}
+
+func (ms *MetricsStorage) GetAPIToken(id int32) *format.APIToken {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.apiTokenByID[id]
+}
+
+// revoked tokens are not returned
+func (ms *MetricsStorage) GetAPITokenByHash(hash string) *format.APIToken {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.apiTokenByHash[hash]
+}
+
+func (ms *MetricsStorage) GetAPITokenList() []*format.APIToken {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	li := make([]*format.APIToken, 0, len(ms.apiTokenByID))
+	for _, v := range ms.apiTokenByID {
+		if v.DeleteTime > 0 {
+			continue
+		}
+		li = append(li, v)
+	}
+	return li
+}
+
 func (ms *MetricsStorage) GetGroup(id int32) *format.MetricsGroup {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
@@ -360,6 +468,64 @@ func (ms *MetricsStorage) ApplyEvent(newEntries []tlmetadata.Event) {
 				DeleteTime:  e.Unused,
 			}
 			ms.dashboardByID[dash.DashboardID] = dash
+		case format.AlertRuleEvent:
+			value := &format.AlertRule{}
+			err := json.Unmarshal([]byte(e.Data), value)
+			if err != nil {
+				log.Printf("Cannot marshal alert rule %s: %v", e.Name, err)
+				continue
+			}
+			value.ID = int32(e.Id)
+			value.Name = e.Name
+			value.Version = e.Version
+			value.UpdateTime = e.UpdateTime
+			value.DeleteTime = e.Unused
+			ms.alertRuleByID[value.ID] = value
+		case format.RecordingRuleEvent:
+			value := &format.RecordingRule{}
+			err := json.Unmarshal([]byte(e.Data), value)
+			if err != nil {
+				log.Printf("Cannot marshal recording rule %s: %v", e.Name, err)
+				continue
+			}
+			value.ID = int32(e.Id)
+			value.Name = e.Name
+			value.Version = e.Version
+			value.UpdateTime = e.UpdateTime
+			value.DeleteTime = e.Unused
+			ms.recordingByID[value.ID] = value
+		case format.AnnotationEvent:
+			value := &format.Annotation{}
+			err := json.Unmarshal([]byte(e.Data), value)
+			if err != nil {
+				log.Printf("Cannot marshal annotation %s: %v", e.Name, err)
+				continue
+			}
+			value.ID = int32(e.Id)
+			value.Name = e.Name
+			value.Version = e.Version
+			value.UpdateTime = e.UpdateTime
+			value.DeleteTime = e.Unused
+			ms.annotationByID[value.ID] = value
+		case format.APITokenEvent:
+			value := &format.APIToken{}
+			err := json.Unmarshal([]byte(e.Data), value)
+			if err != nil {
+				log.Printf("Cannot marshal API token %s: %v", e.Name, err)
+				continue
+			}
+			value.ID = int32(e.Id)
+			value.Name = e.Name
+			value.Version = e.Version
+			value.UpdateTime = e.UpdateTime
+			value.DeleteTime = e.Unused
+			if old := ms.apiTokenByID[value.ID]; old != nil {
+				delete(ms.apiTokenByHash, old.Hash)
+			}
+			ms.apiTokenByID[value.ID] = value
+			if value.DeleteTime == 0 {
+				ms.apiTokenByHash[value.Hash] = value
+			}
 		case format.MetricsGroupEvent:
 			value := &format.MetricsGroup{}
 			err := json.Unmarshal([]byte(e.Data), value)
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"strconv"
 	"time"
 
 	"go4.org/mem"
@@ -91,6 +92,138 @@ func (l *MetricMetaLoader) SaveDashboard(ctx context.Context, value format.Dashb
 	}, nil
 }
 
+func (l *MetricMetaLoader) SaveAlertRule(ctx context.Context, value format.AlertRule, create, remove bool, metadata string) (format.AlertRule, error) {
+	if err := value.Validate(); err != nil {
+		return format.AlertRule{}, fmt.Errorf("invalid alert rule %w: %v", errorInvalidUserRequest, err)
+	}
+	ruleBytes, err := json.Marshal(value)
+	if err != nil {
+		return format.AlertRule{}, fmt.Errorf("faield to serialize alert rule: %w", err)
+	}
+	editMetricReq := tlmetadata.EditEntitynew{
+		Event: tlmetadata.Event{
+			Id:        int64(value.ID),
+			Name:      value.Name,
+			EventType: format.AlertRuleEvent,
+			Version:   value.Version,
+			Data:      string(ruleBytes),
+		},
+	}
+	editMetricReq.SetCreate(create)
+	editMetricReq.SetDelete(remove)
+	editMetricReq.Event.SetMetadata(metadata)
+	ctx, cancelFunc := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancelFunc()
+	event := tlmetadata.Event{}
+	err = l.client.EditEntitynew(ctx, editMetricReq, nil, &event)
+	if err != nil {
+		return format.AlertRule{}, fmt.Errorf("failed to edit alert rule: %w", err)
+	}
+	if event.Id < math.MinInt32 || event.Id > math.MaxInt32 {
+		return format.AlertRule{}, fmt.Errorf("alert rule ID %d assigned by metaengine does not fit into int32 for alert rule %q", event.Id, event.Name)
+	}
+	return alertRuleFromEvent(event)
+}
+
+func (l *MetricMetaLoader) SaveRecordingRule(ctx context.Context, value format.RecordingRule, create, remove bool, metadata string) (format.RecordingRule, error) {
+	if err := value.Validate(); err != nil {
+		return format.RecordingRule{}, fmt.Errorf("invalid recording rule %w: %v", errorInvalidUserRequest, err)
+	}
+	ruleBytes, err := json.Marshal(value)
+	if err != nil {
+		return format.RecordingRule{}, fmt.Errorf("faield to serialize recording rule: %w", err)
+	}
+	editMetricReq := tlmetadata.EditEntitynew{
+		Event: tlmetadata.Event{
+			Id:        int64(value.ID),
+			Name:      value.Name,
+			EventType: format.RecordingRuleEvent,
+			Version:   value.Version,
+			Data:      string(ruleBytes),
+		},
+	}
+	editMetricReq.SetCreate(create)
+	editMetricReq.SetDelete(remove)
+	editMetricReq.Event.SetMetadata(metadata)
+	ctx, cancelFunc := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancelFunc()
+	event := tlmetadata.Event{}
+	err = l.client.EditEntitynew(ctx, editMetricReq, nil, &event)
+	if err != nil {
+		return format.RecordingRule{}, fmt.Errorf("failed to edit recording rule: %w", err)
+	}
+	if event.Id < math.MinInt32 || event.Id > math.MaxInt32 {
+		return format.RecordingRule{}, fmt.Errorf("recording rule ID %d assigned by metaengine does not fit into int32 for recording rule %q", event.Id, event.Name)
+	}
+	return recordingRuleFromEvent(event)
+}
+
+func (l *MetricMetaLoader) SaveAnnotation(ctx context.Context, value format.Annotation, create, remove bool, metadata string) (format.Annotation, error) {
+	if err := value.Validate(); err != nil {
+		return format.Annotation{}, fmt.Errorf("invalid annotation %w: %v", errorInvalidUserRequest, err)
+	}
+	annotationBytes, err := json.Marshal(value)
+	if err != nil {
+		return format.Annotation{}, fmt.Errorf("faield to serialize annotation: %w", err)
+	}
+	editMetricReq := tlmetadata.EditEntitynew{
+		Event: tlmetadata.Event{
+			Id:        int64(value.ID),
+			Name:      value.Name,
+			EventType: format.AnnotationEvent,
+			Version:   value.Version,
+			Data:      string(annotationBytes),
+		},
+	}
+	editMetricReq.SetCreate(create)
+	editMetricReq.SetDelete(remove)
+	editMetricReq.Event.SetMetadata(metadata)
+	ctx, cancelFunc := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancelFunc()
+	event := tlmetadata.Event{}
+	err = l.client.EditEntitynew(ctx, editMetricReq, nil, &event)
+	if err != nil {
+		return format.Annotation{}, fmt.Errorf("failed to edit annotation: %w", err)
+	}
+	if event.Id < math.MinInt32 || event.Id > math.MaxInt32 {
+		return format.Annotation{}, fmt.Errorf("annotation ID %d assigned by metaengine does not fit into int32 for annotation %q", event.Id, event.Name)
+	}
+	return annotationFromEvent(event)
+}
+
+func (l *MetricMetaLoader) SaveAPIToken(ctx context.Context, value format.APIToken, create, remove bool, metadata string) (format.APIToken, error) {
+	if err := value.Validate(); err != nil {
+		return format.APIToken{}, fmt.Errorf("invalid API token %w: %v", errorInvalidUserRequest, err)
+	}
+	tokenBytes, err := json.Marshal(value)
+	if err != nil {
+		return format.APIToken{}, fmt.Errorf("faield to serialize API token: %w", err)
+	}
+	editMetricReq := tlmetadata.EditEntitynew{
+		Event: tlmetadata.Event{
+			Id:        int64(value.ID),
+			Name:      value.Name,
+			EventType: format.APITokenEvent,
+			Version:   value.Version,
+			Data:      string(tokenBytes),
+		},
+	}
+	editMetricReq.SetCreate(create)
+	editMetricReq.SetDelete(remove)
+	editMetricReq.Event.SetMetadata(metadata)
+	ctx, cancelFunc := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancelFunc()
+	event := tlmetadata.Event{}
+	err = l.client.EditEntitynew(ctx, editMetricReq, nil, &event)
+	if err != nil {
+		return format.APIToken{}, fmt.Errorf("failed to edit API token: %w", err)
+	}
+	if event.Id < math.MinInt32 || event.Id > math.MaxInt32 {
+		return format.APIToken{}, fmt.Errorf("API token ID %d assigned by metaengine does not fit into int32 for API token %q", event.Id, event.Name)
+	}
+	return apiTokenFromEvent(event)
+}
+
 func (l *MetricMetaLoader) SaveMetricsGroup(ctx context.Context, value format.MetricsGroup, create bool, metadata string) (g format.MetricsGroup, _ error) {
 	if err := value.RestoreCachedInfo(false); err != nil {
 		return g, err
@@ -221,6 +354,122 @@ func (l *MetricMetaLoader) GetDashboard(ctx context.Context, id int64, version i
 	return d, nil
 }
 
+func (l *MetricMetaLoader) GetMetricsGroup(ctx context.Context, id int64, version int64) (ret format.MetricsGroup, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	err = json.Unmarshal([]byte(entity.Data), &ret)
+	if err != nil {
+		return format.MetricsGroup{}, fmt.Errorf("failed to deserialize json group: %w", err)
+	}
+	ret.ID = int32(entity.Id)
+	ret.Name = entity.Name
+	ret.Version = entity.Version
+	ret.UpdateTime = entity.UpdateTime
+	return ret, nil
+}
+
+func (l *MetricMetaLoader) GetNamespace(ctx context.Context, id int64, version int64) (ret format.NamespaceMeta, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	err = json.Unmarshal([]byte(entity.Data), &ret)
+	if err != nil {
+		return format.NamespaceMeta{}, fmt.Errorf("failed to deserialize json namespace: %w", err)
+	}
+	ret.ID = int32(entity.Id)
+	ret.Name = entity.Name
+	ret.Version = entity.Version
+	ret.UpdateTime = entity.UpdateTime
+	return ret, nil
+}
+
+func (l *MetricMetaLoader) GetAlertRule(ctx context.Context, id int64, version int64) (ret format.AlertRule, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	return alertRuleFromEvent(entity)
+}
+
+func alertRuleFromEvent(event tlmetadata.Event) (ret format.AlertRule, err error) {
+	err = json.Unmarshal([]byte(event.Data), &ret)
+	if err != nil {
+		return format.AlertRule{}, fmt.Errorf("failed to deserialize json alert rule: %w", err)
+	}
+	ret.ID = int32(event.Id)
+	ret.Name = event.Name
+	ret.Version = event.Version
+	ret.UpdateTime = event.UpdateTime
+	ret.DeleteTime = event.Unused
+	return ret, nil
+}
+
+func (l *MetricMetaLoader) GetRecordingRule(ctx context.Context, id int64, version int64) (ret format.RecordingRule, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	return recordingRuleFromEvent(entity)
+}
+
+func recordingRuleFromEvent(event tlmetadata.Event) (ret format.RecordingRule, err error) {
+	err = json.Unmarshal([]byte(event.Data), &ret)
+	if err != nil {
+		return format.RecordingRule{}, fmt.Errorf("failed to deserialize json recording rule: %w", err)
+	}
+	ret.ID = int32(event.Id)
+	ret.Name = event.Name
+	ret.Version = event.Version
+	ret.UpdateTime = event.UpdateTime
+	ret.DeleteTime = event.Unused
+	return ret, nil
+}
+
+func (l *MetricMetaLoader) GetAnnotation(ctx context.Context, id int64, version int64) (ret format.Annotation, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	return annotationFromEvent(entity)
+}
+
+func (l *MetricMetaLoader) GetAPIToken(ctx context.Context, id int64, version int64) (ret format.APIToken, err error) {
+	entity, err := l.GetEntity(ctx, id, version)
+	if err != nil {
+		return ret, err
+	}
+	return apiTokenFromEvent(entity)
+}
+
+func apiTokenFromEvent(event tlmetadata.Event) (ret format.APIToken, err error) {
+	err = json.Unmarshal([]byte(event.Data), &ret)
+	if err != nil {
+		return format.APIToken{}, fmt.Errorf("failed to deserialize json API token: %w", err)
+	}
+	ret.ID = int32(event.Id)
+	ret.Name = event.Name
+	ret.Version = event.Version
+	ret.UpdateTime = event.UpdateTime
+	ret.DeleteTime = event.Unused
+	return ret, nil
+}
+
+func annotationFromEvent(event tlmetadata.Event) (ret format.Annotation, err error) {
+	err = json.Unmarshal([]byte(event.Data), &ret)
+	if err != nil {
+		return format.Annotation{}, fmt.Errorf("failed to deserialize json annotation: %w", err)
+	}
+	ret.ID = int32(event.Id)
+	ret.Name = event.Name
+	ret.Version = event.Version
+	ret.UpdateTime = event.UpdateTime
+	ret.DeleteTime = event.Unused
+	return ret, nil
+}
+
 func (l *MetricMetaLoader) GetEntity(ctx context.Context, id int64, version int64) (ret tlmetadata.Event, err error) {
 	err = l.client.GetEntity(ctx, tlmetadata.GetEntity{
 		Id:      id,
@@ -354,6 +603,37 @@ func (l *MetricMetaLoader) LoadOrCreateMapping(ctxParent context.Context, key st
 	return pcache.Int32ToValue(v), d, e
 }
 
+func (l *MetricMetaLoader) GetTagMappingInverse(ctx context.Context, id int32) (string, error) {
+	ctx, cancelFunc := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancelFunc()
+
+	resp := tlmetadata.GetInvertMappingResponse{}
+	err := l.client.GetInvertMapping(ctx, tlmetadata.GetInvertMapping{Id: id}, nil, &resp)
+	if err != nil {
+		return "", err
+	}
+	if resp.IsKeyNotExists() {
+		return "", fmt.Errorf("tag value for %d not found", id)
+	}
+	if r, ok := resp.AsGetInvertMappingResponse(); ok {
+		return r.Key, nil
+	}
+	return "", fmt.Errorf("can't parse response: %d", resp.TLTag())
+}
+
+// adapter for disk cache
+func (l *MetricMetaLoader) LoadTagMappingInverse(ctxParent context.Context, key string, _ interface{}) (pcache.Value, time.Duration, error) {
+	id, err := strconv.ParseInt(key, 10, 32)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%q is not a tag value ID", key)
+	}
+	v, err := l.GetTagMappingInverse(ctxParent, int32(id))
+	if err != nil {
+		return nil, 0, err
+	}
+	return pcache.StringToValue(v), 0, nil
+}
+
 func (l *MetricMetaLoader) SaveScrapeConfig(ctx context.Context, version int64, config string, metadata string) (tlmetadata.Event, error) {
 	editMetricReq := tlmetadata.EditEntitynew{
 		Event: tlmetadata.Event{
@@ -515,3 +795,20 @@ func (l *MetricMetaLoader) ResetFlood(ctx context.Context, metricName string, va
 	}
 	return true, resp.BudgetBefore, resp.BudgetAfter, err
 }
+
+// SearchMappings returns page of tag value mappings ordered by id, pass returned nextID as fromID to get next page, nextID is 0 after last page
+func (l *MetricMetaLoader) SearchMappings(ctx context.Context, prefix string, regex string, fromID int32, limit int32) (mappings []tlmetadata.MappingInfo, nextID int32, _ error) {
+	ctx, cancel := context.WithTimeout(ctx, l.loadTimeout)
+	defer cancel()
+	req := tlmetadata.SearchMappings{
+		Prefix: prefix,
+		Regex:  regex,
+		FromId: fromID,
+		Limit:  limit,
+	}
+	resp := tlmetadata.SearchMappingsResponse{}
+	if err := l.client.SearchMappings(ctx, req, nil, &resp); err != nil {
+		return nil, 0, fmt.Errorf("failed to search mappings: %w", err)
+	}
+	return resp.Mappings, resp.NextId, nil
+}
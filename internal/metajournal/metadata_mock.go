@@ -9,6 +9,7 @@ package metajournal
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
 	"github.com/vkcom/statshouse/internal/vkgo/rpc"
@@ -19,6 +20,7 @@ type MetadataMock struct {
 	dataMu  sync.RWMutex
 	Data    map[string]int32
 	i       int32
+	events  []tlmetadata.Event // saved entities, version is index + 1
 }
 
 func NewMetadataMock() *MetadataMock {
@@ -26,7 +28,8 @@ func NewMetadataMock() *MetadataMock {
 		Data: map[string]int32{},
 	}
 	m.handler = tlmetadata.Handler{
-		GetMapping: m.handleGetMapping,
+		GetMapping:    m.handleGetMapping,
+		EditEntitynew: m.handleEditEntity,
 	}
 	return m
 }
@@ -46,3 +49,29 @@ func (m *MetadataMock) handleGetMapping(ctx context.Context, args tlmetadata.Get
 	m.Data[args.Key] = m.i
 	return tlmetadata.GetMappingResponseCreated{Id: m.i}.AsUnion(), nil
 }
+
+func (m *MetadataMock) handleEditEntity(ctx context.Context, args tlmetadata.EditEntitynew) (tlmetadata.Event, error) {
+	m.dataMu.Lock()
+	defer m.dataMu.Unlock()
+	e := args.Event
+	if args.IsSetCreate() {
+		e.Id = int64(len(m.events) + 1)
+	}
+	e.Version = int64(len(m.events) + 1)
+	e.UpdateTime = uint32(time.Now().Unix())
+	if args.IsSetDelete() {
+		e.Unused = e.UpdateTime // delete time
+	}
+	m.events = append(m.events, e)
+	return e, nil
+}
+
+// LoadJournal can be used as MetricsStorageLoader, returns entities saved after lastVersion
+func (m *MetadataMock) LoadJournal(ctx context.Context, lastVersion int64, returnIfEmpty bool) ([]tlmetadata.Event, int64, error) {
+	m.dataMu.RLock()
+	defer m.dataMu.RUnlock()
+	if lastVersion >= int64(len(m.events)) {
+		return nil, lastVersion, nil
+	}
+	return append([]tlmetadata.Event(nil), m.events[lastVersion:]...), int64(len(m.events)), nil
+}
@@ -0,0 +1,437 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package metajournal
+
+// Schema is declarative description of namespaces, groups, metrics and dashboards kept in files (for example in git).
+// Entities are matched with journal by name, missing are created, different are updated with version
+// seen during planning, so concurrent edit made through UI fails the sync instead of being overwritten.
+// Entities absent from schema are never removed.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+const (
+	SchemaKindNamespace = "namespace"
+	SchemaKindGroup     = "group"
+	SchemaKindMetric    = "metric"
+	SchemaKindDashboard = "dashboard"
+)
+
+type Schema struct {
+	Namespaces []format.NamespaceMeta   `json:"namespaces,omitempty"`
+	Groups     []SchemaGroup            `json:"groups,omitempty"`
+	Metrics    []format.MetricMetaValue `json:"metrics,omitempty"`
+	Dashboards []format.DashboardMeta   `json:"dashboards,omitempty"`
+}
+
+// IDs differ between clusters, so group references namespace by name
+type SchemaGroup struct {
+	format.MetricsGroup
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type SchemaChange struct {
+	Kind    string   `json:"kind"`
+	Name    string   `json:"name"`
+	Create  bool     `json:"create"`
+	Version int64    `json:"version"`          // expected journal version of updated entity
+	Fields  []string `json:"fields,omitempty"` // changed by update
+
+	entity interface{}
+}
+
+type SchemaSaver interface {
+	SaveNamespace(ctx context.Context, value format.NamespaceMeta, create bool, metadata string) (format.NamespaceMeta, error)
+	SaveMetricsGroup(ctx context.Context, value format.MetricsGroup, create bool, metadata string) (format.MetricsGroup, error)
+	SaveMetric(ctx context.Context, value format.MetricMetaValue, metadata string) (format.MetricMetaValue, error)
+	SaveDashboard(ctx context.Context, value format.DashboardMeta, create, remove bool, metadata string) (format.DashboardMeta, error)
+}
+
+func (c SchemaChange) String() string {
+	if c.Create {
+		return fmt.Sprintf("+ %s %q", c.Kind, c.Name)
+	}
+	return fmt.Sprintf("~ %s %q (%s)", c.Kind, c.Name, strings.Join(c.Fields, ", "))
+}
+
+// LoadSchemaDir reads all *.yaml, *.yml and *.json files in dir and its subdirectories, each file is part of schema
+func LoadSchemaDir(dir string) (Schema, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json":
+			if !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Schema{}, err
+	}
+	sort.Strings(files)
+	var res Schema
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return Schema{}, err
+		}
+		part, err := ParseSchema(data, strings.ToLower(filepath.Ext(f)) != ".json")
+		if err != nil {
+			return Schema{}, fmt.Errorf("%s: %w", f, err)
+		}
+		res.Namespaces = append(res.Namespaces, part.Namespaces...)
+		res.Groups = append(res.Groups, part.Groups...)
+		res.Metrics = append(res.Metrics, part.Metrics...)
+		res.Dashboards = append(res.Dashboards, part.Dashboards...)
+	}
+	return res, res.validate()
+}
+
+// ParseSchema uses JSON field names for YAML as well, so the same entity can be copied between them
+func ParseSchema(data []byte, isYAML bool) (Schema, error) {
+	var res Schema
+	if isYAML {
+		var v interface{}
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return res, err
+		}
+		v, err := yamlToJSONValue(v)
+		if err != nil {
+			return res, err
+		}
+		if data, err = json.Marshal(v); err != nil {
+			return res, err
+		}
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields() // catch typos in field names
+	err := decoder.Decode(&res)
+	return res, err
+}
+
+func yamlToJSONValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			var err error
+			if res[fmt.Sprint(k)], err = yamlToJSONValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case []interface{}:
+		res := make([]interface{}, len(x))
+		for i, e := range x {
+			var err error
+			if res[i], err = yamlToJSONValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	default:
+		return v, nil
+	}
+}
+
+func (s *Schema) validate() error {
+	seen := map[string]bool{}
+	check := func(kind string, name string) error {
+		if name == "" {
+			return fmt.Errorf("%s without name", kind)
+		}
+		key := kind + " " + name
+		if seen[key] {
+			return fmt.Errorf("duplicate %s %q", kind, name)
+		}
+		seen[key] = true
+		return nil
+	}
+	for _, v := range s.Namespaces {
+		if err := check(SchemaKindNamespace, v.Name); err != nil {
+			return err
+		}
+	}
+	for _, v := range s.Groups {
+		if err := check(SchemaKindGroup, v.Name); err != nil {
+			return err
+		}
+	}
+	for _, v := range s.Metrics {
+		if err := check(SchemaKindMetric, v.Name); err != nil {
+			return err
+		}
+	}
+	for _, v := range s.Dashboards {
+		if err := check(SchemaKindDashboard, v.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PlanSchema returns changes required to bring storage to schema, in order they must be applied
+func PlanSchema(storage *MetricsStorage, schema Schema) ([]SchemaChange, error) {
+	if err := schema.validate(); err != nil {
+		return nil, err
+	}
+	var res []SchemaChange
+	add := func(kind string, name string, cur interface{}, version int64, declared interface{}) error {
+		c := SchemaChange{Kind: kind, Name: name, Create: cur == nil, entity: declared}
+		if !c.Create {
+			var err error
+			if c.Fields, err = schemaDiff(cur, declared); err != nil {
+				return fmt.Errorf("%s %q: %w", kind, name, err)
+			}
+			if len(c.Fields) == 0 {
+				return nil
+			}
+			c.Version = version
+		}
+		res = append(res, c)
+		return nil
+	}
+	for _, v := range schema.Namespaces {
+		if err := v.RestoreCachedInfo(false); err != nil {
+			return nil, fmt.Errorf("namespace %q: %w", v.Name, err)
+		}
+		var cur interface{}
+		var version int64
+		if c := storage.GetNamespaceByName(v.Name); c != nil {
+			v.ID, version = c.ID, c.Version
+			cur = schemaNamespace(*c)
+		} else {
+			v.ID = 0 // file may be copied from another cluster
+		}
+		if err := add(SchemaKindNamespace, v.Name, cur, version, schemaNamespace(v)); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range schema.Groups {
+		if err := v.RestoreCachedInfo(false); err != nil {
+			return nil, fmt.Errorf("group %q: %w", v.Name, err)
+		}
+		var cur interface{}
+		var version int64
+		if c := storage.GetGroupByName(v.Name); c != nil {
+			v.ID, version = c.ID, c.Version
+			g := SchemaGroup{MetricsGroup: *c}
+			if ns := storage.GetNamespace(c.NamespaceID); ns != nil && ns.ID != format.BuiltinNamespaceIDDefault {
+				g.Namespace = ns.Name
+			}
+			cur = schemaGroup(g)
+		} else {
+			v.ID = 0
+		}
+		if err := add(SchemaKindGroup, v.Name, cur, version, schemaGroup(v)); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range schema.Metrics {
+		if v.Resolution == 0 {
+			v.Resolution = 1 // UI default, so files need not repeat it
+		}
+		c := storage.GetMetaMetricByName(v.Name)
+		if c != nil && v.OptionsVersion == 0 { // file is unaware of options, so they must not change with description
+			v.CopyOptions(c)
+		}
+		if err := v.RestoreCachedInfo(); err != nil {
+			return nil, fmt.Errorf("metric %q: %w", v.Name, err)
+		}
+		var cur interface{}
+		var version int64
+		// tag value IDs differ between clusters and only API maps values to them, so schema can keep sample_keep_values, but not change them
+		v.SampleKeepValueIDs = nil
+		if len(v.SampleKeepValues) != 0 {
+			if c == nil || c.SampleKeepTagID != v.SampleKeepTagID || !reflect.DeepEqual(c.SampleKeepValues, v.SampleKeepValues) {
+				return nil, fmt.Errorf("metric %q: sample_keep_values can be changed only by editing metric", v.Name)
+			}
+			v.SampleKeepValueIDs = c.SampleKeepValueIDs
+		}
+		if c != nil {
+			if c.MetricID < 0 {
+				return nil, fmt.Errorf("metric %q is builtin", v.Name)
+			}
+			v.MetricID, version = c.MetricID, c.Version
+			cur = schemaMetric(*c)
+		} else {
+			v.MetricID = 0
+		}
+		if err := add(SchemaKindMetric, v.Name, cur, version, schemaMetric(v)); err != nil {
+			return nil, err
+		}
+	}
+	dashboards := map[string]*format.DashboardMeta{}
+	for _, d := range storage.GetDashboardList() {
+		if prev, ok := dashboards[d.Name]; ok && prev != nil {
+			dashboards[d.Name] = nil // ambiguous
+			continue
+		}
+		if _, ok := dashboards[d.Name]; !ok {
+			dashboards[d.Name] = d
+		}
+	}
+	for _, v := range schema.Dashboards {
+		var cur interface{}
+		var version int64
+		c, ok := dashboards[v.Name]
+		if ok && c == nil {
+			return nil, fmt.Errorf("dashboard name %q is not unique", v.Name)
+		}
+		if c != nil {
+			v.DashboardID, version = c.DashboardID, c.Version
+			cur = schemaDashboard(*c)
+		} else {
+			v.DashboardID = 0
+		}
+		if err := add(SchemaKindDashboard, v.Name, cur, version, schemaDashboard(v)); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// ApplySchema saves planned changes, returns journal version after the last one
+func ApplySchema(ctx context.Context, saver SchemaSaver, storage *MetricsStorage, plan []SchemaChange, metadata string) (version int64, _ error) {
+	namespaceIDs := map[string]int32{} // created during apply are not yet in storage
+	namespaceID := func(name string) (int32, error) {
+		if name == "" {
+			return format.BuiltinNamespaceIDDefault, nil
+		}
+		if id, ok := namespaceIDs[name]; ok {
+			return id, nil
+		}
+		if ns := storage.GetNamespaceByName(name); ns != nil {
+			return ns.ID, nil
+		}
+		return 0, fmt.Errorf("namespace %q not found", name)
+	}
+	for _, c := range plan {
+		var err error
+		switch e := c.entity.(type) {
+		case format.NamespaceMeta:
+			e.Version = c.Version
+			if e, err = saver.SaveNamespace(ctx, e, c.Create, metadata); err == nil {
+				namespaceIDs[e.Name] = e.ID
+				version = e.Version
+			}
+		case SchemaGroup:
+			e.Version = c.Version
+			if e.NamespaceID, err = namespaceID(e.Namespace); err == nil {
+				var g format.MetricsGroup
+				if g, err = saver.SaveMetricsGroup(ctx, e.MetricsGroup, c.Create, metadata); err == nil {
+					version = g.Version
+				}
+			}
+		case format.MetricMetaValue:
+			e.Version = c.Version
+			if i := strings.Index(e.Name, format.NamespaceSeparator); i >= 0 {
+				e.NamespaceID, err = namespaceID(e.Name[:i])
+			} else {
+				e.NamespaceID = format.BuiltinNamespaceIDDefault
+			}
+			if cur := storage.GetMetaMetric(e.MetricID); cur != nil && !c.Create {
+				e.PurgeTime = cur.PurgeTime
+				e.SetStateTime(cur, time.Now())
+			} else {
+				e.SetStateTime(nil, time.Now())
+			}
+			if err == nil {
+				if e, err = saver.SaveMetric(ctx, e, metadata); err == nil {
+					version = e.Version
+				}
+			}
+		case format.DashboardMeta:
+			e.Version = c.Version
+			if e, err = saver.SaveDashboard(ctx, e, c.Create, false, metadata); err == nil {
+				version = e.Version
+			}
+		default:
+			err = fmt.Errorf("change was not planned by PlanSchema")
+		}
+		if err != nil {
+			return version, fmt.Errorf("failed to apply %s: %w", c.String(), err)
+		}
+	}
+	return version, nil
+}
+
+// fields assigned by journal or aggregator are not part of schema
+
+func schemaNamespace(v format.NamespaceMeta) format.NamespaceMeta {
+	v.Version, v.UpdateTime = 0, 0
+	return v
+}
+
+func schemaGroup(v SchemaGroup) SchemaGroup {
+	v.Version, v.UpdateTime, v.NamespaceID = 0, 0, 0
+	return v
+}
+
+func schemaMetric(v format.MetricMetaValue) format.MetricMetaValue {
+	v.Version, v.UpdateTime, v.NamespaceID, v.PurgeTime, v.StateTime = 0, 0, 0, 0, 0
+	for len(v.Tags) != 0 && emptySchemaTag(v.Tags[len(v.Tags)-1]) {
+		v.Tags = v.Tags[:len(v.Tags)-1] // UI saves all tags, schema files usually list only used
+	}
+	return v
+}
+
+func emptySchemaTag(t format.MetricMetaTag) bool {
+	return t.Name == "" && t.Description == "" && !t.Raw && t.RawKind == "" && len(t.ID2Value) == 0 && len(t.ValueComments) == 0
+}
+
+func schemaDashboard(v format.DashboardMeta) format.DashboardMeta {
+	v.Version, v.UpdateTime = 0, 0
+	return v
+}
+
+// names of top level JSON fields which differ
+func schemaDiff(cur interface{}, declared interface{}) ([]string, error) {
+	var a, b map[string]interface{}
+	for _, x := range []struct {
+		v   interface{}
+		res *map[string]interface{}
+	}{{cur, &a}, {declared, &b}} {
+		data, err := json.Marshal(x.v)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(data, x.res); err != nil {
+			return nil, err
+		}
+	}
+	var res []string
+	for k, v := range a {
+		if !reflect.DeepEqual(v, b[k]) {
+			res = append(res, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			res = append(res, k)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
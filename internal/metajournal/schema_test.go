@@ -0,0 +1,208 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package metajournal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlmetadata"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+const testSchemaYAML = `
+namespaces:
+  - name: team
+    weight: 2
+groups:
+  - name: team_api
+    namespace: team
+    weight: 1
+metrics:
+  - name: "team:requests"
+    kind: counter
+    description: requests by method
+    tags:
+      - description: environment
+      - name: method
+  - name: "team:errors"
+    kind: counter
+`
+
+const testSchemaJSON = `{"dashboards": [{"name": "Team", "data": {"searchParams": [["s", "team:requests"]]}}]}`
+
+type testSchemaSaver struct {
+	saved []string
+	ids   int32
+}
+
+func (s *testSchemaSaver) save(kind string, name string, version int64, create bool) {
+	if create {
+		s.saved = append(s.saved, "create "+kind+" "+name)
+	} else {
+		s.saved = append(s.saved, fmt.Sprintf("update %s %s %d", kind, name, version))
+	}
+}
+
+func (s *testSchemaSaver) SaveNamespace(_ context.Context, value format.NamespaceMeta, create bool, _ string) (format.NamespaceMeta, error) {
+	s.save(SchemaKindNamespace, value.Name, value.Version, create)
+	s.ids++
+	value.ID = 1000 + s.ids
+	return value, nil
+}
+
+func (s *testSchemaSaver) SaveMetricsGroup(_ context.Context, value format.MetricsGroup, create bool, _ string) (format.MetricsGroup, error) {
+	s.save(SchemaKindGroup, value.Name, value.Version, create)
+	if value.NamespaceID != 1001 {
+		panic("namespace created during apply must be resolved")
+	}
+	return value, nil
+}
+
+func (s *testSchemaSaver) SaveMetric(_ context.Context, value format.MetricMetaValue, _ string) (format.MetricMetaValue, error) {
+	s.save(SchemaKindMetric, value.Name, value.Version, value.MetricID == 0)
+	return value, nil
+}
+
+func (s *testSchemaSaver) SaveDashboard(_ context.Context, value format.DashboardMeta, create, _ bool, _ string) (format.DashboardMeta, error) {
+	s.save(SchemaKindDashboard, value.Name, value.Version, create)
+	return value, nil
+}
+
+func TestSchemaSync(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "team.yaml"), []byte(testSchemaYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.JSON"), []byte(testSchemaJSON), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("ignored"), 0o644))
+	schema, err := LoadSchemaDir(dir)
+	require.NoError(t, err)
+	require.Len(t, schema.Namespaces, 1)
+	require.Len(t, schema.Groups, 1)
+	require.Equal(t, "team", schema.Groups[0].Namespace)
+	require.Len(t, schema.Metrics, 2)
+	require.Len(t, schema.Dashboards, 1)
+
+	var events []tlmetadata.Event
+	m := newMetricStorage(func(ctx context.Context, lastVersion int64, returnIfEmpty bool) ([]tlmetadata.Event, int64, error) {
+		return events, int64(len(events)), nil
+	})
+	addEvent := func(id int64, name string, typ int32, data any) {
+		b, err := json.Marshal(data)
+		require.NoError(t, err)
+		events = append(events, tlmetadata.Event{Id: id, Name: name, EventType: typ, Version: int64(len(events) + 1), Data: string(b)})
+	}
+	// "team:requests" is the same as in schema except padding tags saved by UI, "team:errors" differs
+	addEvent(1, "team:requests", format.MetricEvent, format.MetricMetaValue{
+		Kind:        format.MetricKindCounter,
+		Description: "requests by method",
+		Resolution:  1,
+		Tags:        []format.MetricMetaTag{{Description: "environment"}, {Name: "method"}, {}, {}},
+	})
+	addEvent(2, "team:errors", format.MetricEvent, format.MetricMetaValue{Kind: format.MetricKindValue, Resolution: 1})
+	addEvent(3, "Team", format.DashboardEvent, map[string]interface{}{"searchParams": [][]string{{"s", "team:requests"}}})
+	require.NoError(t, m.journal.updateJournal(nil))
+
+	plan, err := PlanSchema(m, schema)
+	require.NoError(t, err)
+	var lines []string
+	for _, c := range plan {
+		lines = append(lines, c.String())
+	}
+	require.Equal(t, []string{
+		`+ namespace "team"`,
+		`+ group "team_api"`,
+		`~ metric "team:errors" (kind)`,
+	}, lines)
+	require.Equal(t, int64(2), plan[2].Version)
+
+	saver := &testSchemaSaver{}
+	_, err = ApplySchema(context.Background(), saver, m, plan, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"create namespace team",
+		"create group team_api",
+		"update metric team:errors 2",
+	}, saver.saved)
+
+	schema.Metrics = append(schema.Metrics, schema.Metrics[0])
+	_, err = PlanSchema(m, schema)
+	require.Error(t, err) // duplicate name
+}
+
+func TestSchemaSyncSampleKeepValues(t *testing.T) {
+	var events []tlmetadata.Event
+	m := newMetricStorage(func(ctx context.Context, lastVersion int64, returnIfEmpty bool) ([]tlmetadata.Event, int64, error) {
+		return events, int64(len(events)), nil
+	})
+	b, err := json.Marshal(format.MetricMetaValue{
+		Kind:               format.MetricKindCounter,
+		Resolution:         1,
+		Tags:               []format.MetricMetaTag{{}, {Name: "status"}},
+		SampleKeepTagID:    "1",
+		SampleKeepValues:   []string{"error"},
+		SampleKeepValueIDs: []int32{7},
+	})
+	require.NoError(t, err)
+	events = append(events, tlmetadata.Event{Id: 1, Name: "errors", EventType: format.MetricEvent, Version: 1, Data: string(b)})
+	require.NoError(t, m.journal.updateJournal(nil))
+
+	metric := format.MetricMetaValue{
+		Name:               "errors",
+		Kind:               format.MetricKindCounter,
+		Tags:               []format.MetricMetaTag{{}, {Name: "status"}},
+		SampleKeepTagID:    "1",
+		SampleKeepValues:   []string{"error"},
+		SampleKeepValueIDs: []int32{99}, // from another cluster
+	}
+	plan, err := PlanSchema(m, Schema{Metrics: []format.MetricMetaValue{metric}})
+	require.NoError(t, err)
+	require.Empty(t, plan) // IDs of this cluster are kept
+
+	metric.SampleKeepValues = []string{"error", "fatal"}
+	_, err = PlanSchema(m, Schema{Metrics: []format.MetricMetaValue{metric}})
+	require.Error(t, err)
+
+	metric.SampleKeepTagID, metric.SampleKeepValues = "", nil
+	plan, err = PlanSchema(m, Schema{Metrics: []format.MetricMetaValue{metric}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1) // policy can be removed
+}
+
+func TestSchemaSyncOptions(t *testing.T) {
+	var events []tlmetadata.Event
+	m := newMetricStorage(func(ctx context.Context, lastVersion int64, returnIfEmpty bool) ([]tlmetadata.Event, int64, error) {
+		return events, int64(len(events)), nil
+	})
+	b, err := json.Marshal(format.MetricMetaValue{
+		Kind:        format.MetricKindCounter,
+		Resolution:  1,
+		Description: "latency __round_sample_factors",
+	})
+	require.NoError(t, err)
+	events = append(events, tlmetadata.Event{Id: 1, Name: "latency", EventType: format.MetricEvent, Version: 1, Data: string(b)})
+	require.NoError(t, m.journal.updateJournal(nil))
+
+	// file without options_version can't change options by editing description
+	metric := format.MetricMetaValue{Name: "latency", Kind: format.MetricKindCounter, Description: "latency"}
+	plan, err := PlanSchema(m, Schema{Metrics: []format.MetricMetaValue{metric}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	require.Equal(t, `~ metric "latency" (description)`, plan[0].String())
+	require.True(t, plan[0].entity.(format.MetricMetaValue).RoundSampleFactors)
+
+	metric.OptionsVersion = format.MetricOptionsVersion
+	plan, err = PlanSchema(m, Schema{Metrics: []format.MetricMetaValue{metric}})
+	require.NoError(t, err)
+	require.Len(t, plan, 1)
+	require.False(t, plan[0].entity.(format.MetricMetaValue).RoundSampleFactors)
+}
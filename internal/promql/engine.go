@@ -101,6 +101,8 @@ type evaluator struct {
 	opt Options
 	ast parser.Expr
 	ars map[parser.Expr]parser.Expr // ast reductions
+	ets map[parser.Expr]*exprTimescale
+	et  *exprTimescale // being evaluated
 	t   data_model.Timescale
 	r   int64 // matrix selector range
 	hh  hash.Hash64
@@ -233,8 +235,8 @@ func (ng Engine) newEvaluator(ctx context.Context, qry Query) (evaluator, error)
 		ev.ast = v
 	}
 	ev.opt.Offsets = normalizeOffsets(append(ev.opt.Offsets, 0))
+	ev.prepareSubqueries(qry)
 	// match metrics
-	var maxRange int64
 	parser.Inspect(ev.ast, func(node parser.Node, path []parser.Node) error {
 		switch e := node.(type) {
 		case *parser.VectorSelector:
@@ -246,20 +248,19 @@ func (ng Engine) newEvaluator(ctx context.Context, qry Query) (evaluator, error)
 			} else {
 				e.Offsets = []int64{e.OriginalOffset}
 			}
+			for _, p := range path {
+				if sq, ok := p.(*parser.SubqueryExpr); ok {
+					for i := range e.Offsets {
+						e.Offsets[i] += sq.OriginalOffset
+					}
+				}
+			}
 			if err = ev.bindVariables(e); err != nil {
 				return err
 			}
 			if err = ev.matchMetrics(e, path); err != nil {
 				return err
 			}
-		case *parser.MatrixSelector:
-			if maxRange < e.Range {
-				maxRange = e.Range
-			}
-		case *parser.SubqueryExpr:
-			if maxRange < e.Range {
-				maxRange = e.Range
-			}
 		}
 		return err
 	})
@@ -267,6 +268,7 @@ func (ng Engine) newEvaluator(ctx context.Context, qry Query) (evaluator, error)
 		return evaluator{}, err
 	}
 	// widen time range to accommodate range selectors and ensure instant query won't return empty result
+	maxRange := exprRange(ev.ast)
 	qry.Start -= maxRange
 	if qry.Options.Mode == data_model.InstantQuery {
 		maxRange -= 5 * 60 // 5 times larger than maximum metric resolution (and scrape interval)
@@ -289,6 +291,9 @@ func (ng Engine) newEvaluator(ctx context.Context, qry Query) (evaluator, error)
 	if err != nil || ev.t.Empty() {
 		return evaluator{}, err
 	}
+	if err = ev.initSubqueryTimescales(qry); err != nil {
+		return evaluator{}, err
+	}
 	// evaluate reduction rules
 	ev.ars = make(map[parser.Expr]parser.Expr)
 	stepMin := ev.t.LODs[len(ev.t.LODs)-1].Step
@@ -299,7 +304,7 @@ func (ng Engine) newEvaluator(ctx context.Context, qry Query) (evaluator, error)
 			if s.GroupBy != nil {
 				grouped = true
 			}
-			if !grouped && len(s.What) <= 1 {
+			if !grouped && len(s.What) <= 1 && !ev.hasSubquery(nodes) {
 				if ar, ok := evalReductionRules(s, nodes, stepMin); ok {
 					s.What = ar.what
 					s.GroupBy = ar.groupBy
@@ -454,10 +459,13 @@ func (ev *evaluator) matchMetrics(sel *parser.VectorSelector, path []parser.Node
 			}
 		}
 	}
-	for i := len(path); i != 0; i-- {
-		if e, ok := path[i-1].(*parser.Call); ok && e.Func.Name == "histogram_quantile" {
-			sel.GroupBy = append(sel.GroupBy, format.LETagName)
-			break
+	for i := len(path); sel.Histogram == "" && i != 0; i-- {
+		if e, ok := path[i-1].(*parser.Call); ok {
+			switch e.Func.Name {
+			case "histogram_count", "histogram_sum", "histogram_fraction", "histogram_quantile":
+				sel.GroupBy = append(sel.GroupBy, format.LETagName)
+				sel.Histogram = e.Func.Name
+			}
 		}
 	}
 	return nil
@@ -537,6 +545,9 @@ func (ev *evaluator) eval(expr parser.Expr) (res []Series, err error) {
 	if ev.ctx.Err() != nil {
 		return nil, ev.ctx.Err()
 	}
+	if s, ok := ev.ets[expr]; ok && s != ev.et {
+		return ev.evalSubquery(expr, s)
+	}
 	if e, ok := ev.ars[expr]; ok {
 		if ev.trace != nil && ev.debug {
 			ev.tracef("replace %s with %s", string(expr.Type()), string(e.Type()))
@@ -1068,6 +1079,9 @@ func (ev *evaluator) buildSeriesQuery(ctx context.Context, sel *parser.VectorSel
 		}
 	}
 	var prefixSum bool
+	if len(whats) == 0 && metric.HasPercentiles {
+		whats, prefixSum = ev.histogramWhats(sel.Histogram)
+	}
 	if len(whats) == 0 {
 		var what data_model.DigestWhat
 		if metric.Kind == format.MetricKindCounter {
@@ -1677,6 +1691,31 @@ func normalizeOffsets(s []int64) []int64 {
 	return res[:i+1]
 }
 
+// value_p metrics are histograms stored as t-digest, count and sum are selected directly,
+// fraction is interpolated between digest percentiles
+func (ev *evaluator) histogramWhats(fn string) ([]SelectorWhat, bool) {
+	switch fn {
+	case "histogram_count":
+		if ev.opt.Compat {
+			return []SelectorWhat{{Digest: data_model.DigestCountRaw}}, true
+		}
+		return []SelectorWhat{{Digest: data_model.DigestCount}}, false
+	case "histogram_sum":
+		if ev.opt.Compat {
+			return []SelectorWhat{{Digest: data_model.DigestSumRaw}}, true
+		}
+		return []SelectorWhat{{Digest: data_model.DigestSum}}, false
+	case "histogram_fraction":
+		res := make([]SelectorWhat, 0, len(digestQuantiles))
+		for _, q := range digestQuantiles {
+			res = append(res, SelectorWhat{Digest: q.what})
+		}
+		return res, false
+	default:
+		return nil, false
+	}
+}
+
 func parseSelectorWhat(str string) (data_model.DigestWhat, string, bool) {
 	var digestWhat, queryFunc string
 	if i := strings.Index(str, ":"); i != -1 {
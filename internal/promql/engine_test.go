@@ -0,0 +1,248 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package promql
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse-go"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+// every metric value is equal to the time it was written at
+type testHandler struct {
+	metric  *format.MetricMetaValue
+	queries []SeriesQuery
+}
+
+func (h *testHandler) GetHostName(int32) string                     { return "" }
+func (h *testHandler) GetTagValue(TagValueQuery) string             { return "" }
+func (h *testHandler) GetTagValueID(TagValueIDQuery) (int32, error) { return 0, ErrNotFound }
+func (h *testHandler) Alloc(n int) *[]float64                       { s := make([]float64, n); return &s }
+func (h *testHandler) Free(*[]float64)                              {}
+
+func (h *testHandler) MatchMetrics(_ context.Context, matcher *labels.Matcher, _ string) ([]*format.MetricMetaValue, error) {
+	if matcher.Matches(h.metric.Name) {
+		return []*format.MetricMetaValue{h.metric}, nil
+	}
+	return nil, nil
+}
+
+func (h *testHandler) QuerySeries(_ context.Context, qry *SeriesQuery) (Series, func(), error) {
+	h.queries = append(h.queries, *qry)
+	v := h.Alloc(len(qry.Timescale.Time))
+	for i, t := range qry.Timescale.Time {
+		(*v)[i] = float64(t - qry.Offset)
+	}
+	return Series{Data: []SeriesData{{Values: v}}, Meta: SeriesMeta{Metric: qry.Metric}}, func() {}, nil
+}
+
+func (h *testHandler) QueryTagValueIDs(context.Context, TagValuesQuery) ([]int32, error) {
+	return nil, nil
+}
+
+func (h *testHandler) QueryStringTop(context.Context, TagValuesQuery) ([]string, error) {
+	return nil, nil
+}
+
+func execTestQuery(t *testing.T, expr string, start, end, step int64) (*TimeSeries, *testHandler) {
+	h := &testHandler{metric: &format.MetricMetaValue{Name: "x", Kind: format.MetricKindValue}}
+	v, cleanup, err := NewEngine(h, time.UTC, 0).Exec(context.Background(), Query{
+		Start:   start,
+		End:     end,
+		Step:    step,
+		Expr:    expr,
+		Options: Options{TimeNow: end},
+	})
+	require.NoError(t, err)
+	defer cleanup()
+	res, ok := v.(*TimeSeries)
+	require.True(t, ok)
+	require.Len(t, res.Series.Data, 1)
+	return res, h
+}
+
+func TestAtModifier(t *testing.T) {
+	const at = 1700000000
+	for _, tt := range []struct {
+		expr string
+		want float64
+	}{
+		{"x @ 1700000000", at},
+		{"x @ end()", at + 7199},
+		{"x @ start()", at + 3600},
+		{"x @ 1700000000 offset 1h", at - 3600},
+		{"max_over_time(x[10m] @ 1700000000)", at},
+		{"min_over_time(x[10m:1m] @ 1700000000)", at - 560}, // subquery steps are aligned to multiples of step
+		{"x @ 1700000000 - x @ 1699999000", 1000},
+	} {
+		t.Run(tt.expr, func(t *testing.T) {
+			res, h := execTestQuery(t, tt.expr, at+3600, at+7200, 60)
+			require.NotEmpty(t, res.Time)
+			for _, v := range *res.Series.Data[0].Values {
+				require.Equal(t, tt.want, v)
+			}
+			for _, qry := range h.queries {
+				require.LessOrEqual(t, qry.Timescale.Time[len(qry.Timescale.Time)-1], int64(at+7199))
+			}
+		})
+	}
+}
+
+func TestSubquery(t *testing.T) {
+	const start = 1700000000
+	res, h := execTestQuery(t, "max_over_time(x[10m:5m])", start, start+3600, 60)
+	for i, v := range *res.Series.Data[0].Values {
+		if res.Time[i] >= start {
+			require.Equal(t, float64(res.Time[i]-res.Time[i]%300), v)
+		}
+	}
+	require.Len(t, h.queries, 1)
+	require.Equal(t, int64(300), h.queries[0].Timescale.LODs[0].Step)
+
+	res, h = execTestQuery(t, "max_over_time(x[10m:] offset 1h)", start, start+3600, 60)
+	for i, v := range *res.Series.Data[0].Values {
+		if res.Time[i] >= start {
+			require.Equal(t, float64(res.Time[i]-3600), v)
+		}
+	}
+	require.Len(t, h.queries, 1)
+	require.Equal(t, int64(3600), h.queries[0].Offset)
+	require.LessOrEqual(t, h.queries[0].Timescale.Time[0], int64(start-600))
+}
+
+func TestResampleTime(t *testing.T) {
+	xs := make([]int, 5)
+	resampleTime(xs, []int64{0, 60, 120, 180, 240}, []int64{60, 180})
+	require.Equal(t, []int{-1, 0, 0, 1, 1}, xs)
+}
+
+// "le" histogram with buckets (0, 1], (1, 2], (2, +Inf] of 1, 2 and 1 observations,
+// value_p metric with 100 observations uniformly distributed on [0, 100]
+type histogramTestHandler struct {
+	testHandler
+	metrics []*format.MetricMetaValue
+}
+
+func newHistogramTestHandler(t *testing.T) *histogramTestHandler {
+	le := &format.MetricMetaValue{
+		Name:             "latency_bucket",
+		Kind:             format.MetricKindCounter,
+		Resolution:       1,
+		HistogramBuckets: []float32{1, 2, float32(math.Inf(1))},
+		Tags:             make([]format.MetricMetaTag, format.MaxTags),
+	}
+	le.Tags[format.LETagIndex].Name = format.LETagName
+	size := &format.MetricMetaValue{Name: "size", Kind: format.MetricKindValuePercentiles, Resolution: 1}
+	for _, m := range []*format.MetricMetaValue{le, size} {
+		require.NoError(t, m.RestoreCachedInfo())
+	}
+	return &histogramTestHandler{metrics: []*format.MetricMetaValue{le, size}}
+}
+
+func (h *histogramTestHandler) MatchMetrics(_ context.Context, matcher *labels.Matcher, _ string) ([]*format.MetricMetaValue, error) {
+	var res []*format.MetricMetaValue
+	for _, m := range h.metrics {
+		if matcher.Matches(m.Name) {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+func (h *histogramTestHandler) QuerySeries(_ context.Context, qry *SeriesQuery) (Series, func(), error) {
+	res := Series{Meta: SeriesMeta{Metric: qry.Metric}}
+	add := func(v float64, what SelectorWhat) int {
+		s := h.Alloc(len(qry.Timescale.Time))
+		for i := range *s {
+			(*s)[i] = v
+		}
+		res.Data = append(res.Data, SeriesData{Values: s, What: what})
+		return len(res.Data) - 1
+	}
+	if !qry.Metric.HasPercentiles {
+		for i, count := range []float64{1, 2, 1} {
+			x := add(count, qry.Whats[0])
+			res.AddTagAt(x, &SeriesTag{
+				Metric: qry.Metric,
+				Index:  format.LETagIndex + SeriesTagIndexOffset,
+				ID:     format.TagID(format.LETagIndex),
+				Name:   format.LETagName,
+				Value:  statshouse.LexEncode(qry.Metric.HistogramBuckets[i]),
+			})
+		}
+		return res, func() {}, nil
+	}
+	for _, what := range qry.Whats {
+		switch what.Digest {
+		case data_model.DigestCount:
+			add(100, what)
+		case data_model.DigestSum:
+			add(5000, what)
+		default:
+			for _, q := range digestQuantiles {
+				if q.what == what.Digest {
+					add(q.q*100, what)
+				}
+			}
+		}
+	}
+	return res, func() {}, nil
+}
+
+func TestHistogramFunctions(t *testing.T) {
+	const start = 1700000000
+	for _, tt := range []struct {
+		expr string
+		want float64
+	}{
+		{"histogram_count(latency_bucket)", 4},
+		{"histogram_fraction(0, 1.5, latency_bucket)", 0.5},
+		{"histogram_fraction(1, 2, latency_bucket)", 0.5},
+		{"histogram_count(size)", 100},
+		{"histogram_sum(size)", 5000},
+		{"histogram_fraction(25, 75, size)", 0.5},
+	} {
+		t.Run(tt.expr, func(t *testing.T) {
+			v, cleanup, err := NewEngine(newHistogramTestHandler(t), time.UTC, 0).Exec(context.Background(), Query{
+				Start:   start,
+				End:     start + 3600,
+				Step:    60,
+				Expr:    tt.expr,
+				Options: Options{TimeNow: start + 3600},
+			})
+			require.NoError(t, err)
+			defer cleanup()
+			res, ok := v.(*TimeSeries)
+			require.True(t, ok)
+			require.Len(t, res.Series.Data, 1)
+			for i, v := range *res.Series.Data[0].Values {
+				if res.Time[i] >= start {
+					require.InDelta(t, tt.want, v, 1e-9)
+				}
+			}
+		})
+	}
+}
+
+func TestHistogramSumNotStored(t *testing.T) {
+	_, _, err := NewEngine(newHistogramTestHandler(t), time.UTC, 0).Exec(context.Background(), Query{
+		Start:   1700000000,
+		End:     1700003600,
+		Step:    60,
+		Expr:    "histogram_sum(latency_bucket)",
+		Options: Options{TimeNow: 1700003600},
+	})
+	require.ErrorContains(t, err, `use "latency_sum" metric value instead`)
+}
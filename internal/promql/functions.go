@@ -532,26 +532,26 @@ var calls map[string]callFunc
 
 func init() {
 	calls = map[string]callFunc{
-		"abs":              simpleCall(math.Abs),
-		"absent":           funcAbsent,
-		"absent_over_time": funcAbsentOverTime,
-		"ceil":             simpleCall(math.Ceil),
-		"changes":          overTimeCall(funcChanges, true, 0),
-		"clamp":            funcClamp,
-		"clamp_max":        funcClampMax,
-		"clamp_min":        funcClampMin,
-		"day_of_month":     timeCall(time.Time.Day),
-		"day_of_week":      timeCall(time.Time.Weekday),
-		"day_of_year":      timeCall(time.Time.YearDay),
-		"days_in_month":    timeCall(func(t time.Time) int { return 32 - time.Date(t.Year(), t.Month(), 32, 0, 0, 0, 0, t.Location()).Day() }),
-		"delta":            seriesCall(funcDelta),
-		"deriv":            seriesCall(funcDeriv),
-		"exp":              simpleCall(math.Exp),
-		"floor":            simpleCall(math.Floor),
-		// "histogram_count": ?
-		// "histogram_sum": ?
-		// "histogram_fraction": ?
+		"abs":                simpleCall(math.Abs),
+		"absent":             funcAbsent,
+		"absent_over_time":   funcAbsentOverTime,
+		"ceil":               simpleCall(math.Ceil),
+		"changes":            overTimeCall(funcChanges, true, 0),
+		"clamp":              funcClamp,
+		"clamp_max":          funcClampMax,
+		"clamp_min":          funcClampMin,
+		"day_of_month":       timeCall(time.Time.Day),
+		"day_of_week":        timeCall(time.Time.Weekday),
+		"day_of_year":        timeCall(time.Time.YearDay),
+		"days_in_month":      timeCall(func(t time.Time) int { return 32 - time.Date(t.Year(), t.Month(), 32, 0, 0, 0, 0, t.Location()).Day() }),
+		"delta":              seriesCall(funcDelta),
+		"deriv":              seriesCall(funcDeriv),
+		"exp":                simpleCall(math.Exp),
+		"floor":              simpleCall(math.Floor),
+		"histogram_count":    funcHistogramCount,
+		"histogram_fraction": funcHistogramFraction,
 		"histogram_quantile": funcHistogramQuantile,
+		"histogram_sum":      funcHistogramSum,
 		"holt_winters":       funcHoltWinters,
 		"hour":               timeCall(time.Time.Hour),
 		"idelta":             seriesCall(funcIdelta),
@@ -1144,6 +1144,10 @@ func funcHistogramQuantile(ev *evaluator, args parser.Expressions) ([]Series, er
 	if len(args) != 2 {
 		return nil, fmt.Errorf("invalid argument count in histogram_quantile(): expected 2, got %d", len(args))
 	}
+	ql, ok := args[0].(*parser.NumberLiteral)
+	if !ok {
+		return nil, fmt.Errorf("histogram_quantile(): quantile must be a number literal")
+	}
 	res, err := ev.eval(args[1])
 	if err != nil {
 		return nil, err
@@ -1162,7 +1166,7 @@ func funcHistogramQuantile(ev *evaluator, args parser.Expressions) ([]Series, er
 					s[i] = NilValue
 				}
 			} else {
-				q := args[0].(*parser.NumberLiteral).Val // quantile
+				q := ql.Val // quantile
 				for j := range s {
 					var total float64 // total count
 					for k := 0; k < len(h.buckets); k++ {
@@ -1180,7 +1184,7 @@ func funcHistogramQuantile(ev *evaluator, args parser.Expressions) ([]Series, er
 					var hi float64    // bucket upper bound count
 					var x int         // bucket upper bound index
 					rank := q * total // quantile corresponding count
-					buckets := res[i].Meta.Metric.HistorgamBuckets
+					buckets := res[i].Meta.Metric.HistogramBuckets
 					for k := 0; x < len(buckets) && k < len(h.buckets); x++ {
 						if buckets[x] == h.buckets[k].le {
 							v := (*d[h.buckets[k].x].Values)[j]
@@ -1224,6 +1228,209 @@ func funcHistogramQuantile(ev *evaluator, args parser.Expressions) ([]Series, er
 	return res, nil
 }
 
+// value_p metric percentiles, in ascending order
+var digestQuantiles = [...]struct {
+	what data_model.DigestWhat
+	q    float64
+}{
+	{data_model.DigestMin, 0},
+	{data_model.DigestP0_1, 0.001},
+	{data_model.DigestP1, 0.01},
+	{data_model.DigestP5, 0.05},
+	{data_model.DigestP10, 0.1},
+	{data_model.DigestP25, 0.25},
+	{data_model.DigestP50, 0.5},
+	{data_model.DigestP75, 0.75},
+	{data_model.DigestP90, 0.9},
+	{data_model.DigestP95, 0.95},
+	{data_model.DigestP99, 0.99},
+	{data_model.DigestP999, 0.999},
+	{data_model.DigestMax, 1},
+}
+
+func funcHistogramCount(ev *evaluator, args parser.Expressions) ([]Series, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("invalid argument count in histogram_count(): expected 1, got %d", len(args))
+	}
+	return ev.evalHistogram("histogram_count", args[0], func(h *histogram, j int) float64 {
+		var res float64
+		var n int
+		for k := range h.buckets {
+			if v := h.valueAt(k, j); !math.IsNaN(v) {
+				res += v
+				n++
+			}
+		}
+		if n == 0 {
+			return NilValue
+		}
+		return res
+	}, nil)
+}
+
+// "le" histograms do not store sum, exact sum of Prometheus histogram is stored in "_sum" metric value
+func funcHistogramSum(ev *evaluator, args parser.Expressions) ([]Series, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("invalid argument count in histogram_sum(): expected 1, got %d", len(args))
+	}
+	return ev.evalHistogram("histogram_sum", args[0], nil, nil)
+}
+
+// value_p metrics percentiles are interpolated linearly, they are not additive,
+// so value_p metric should be grouped by selector (not by aggregation) before taking fraction
+func funcHistogramFraction(ev *evaluator, args parser.Expressions) ([]Series, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("invalid argument count in histogram_fraction(): expected 3, got %d", len(args))
+	}
+	lowerLit, ok := args[0].(*parser.NumberLiteral)
+	if !ok {
+		return nil, fmt.Errorf("histogram_fraction(): lower bound must be a number literal")
+	}
+	upperLit, ok := args[1].(*parser.NumberLiteral)
+	if !ok {
+		return nil, fmt.Errorf("histogram_fraction(): upper bound must be a number literal")
+	}
+	lower, upper := lowerLit.Val, upperLit.Val
+	return ev.evalHistogram("histogram_fraction", args[2], func(h *histogram, j int) float64 {
+		var total float64 // total count
+		var count float64 // count inside [lower, upper]
+		for k := range h.buckets {
+			if v := h.valueAt(k, j); !math.IsNaN(v) {
+				lo, hi := h.bounds(k)
+				total += v
+				count += v * math.Max(0, bucketFraction(lo, hi, upper)-bucketFraction(lo, hi, lower))
+			}
+		}
+		if total == 0 {
+			return NilValue
+		}
+		return count / total
+	}, func(d *digest, j int) float64 {
+		var vs [len(digestQuantiles)]float64
+		for i, x := range d.xs {
+			if x == -1 {
+				vs[i] = NilValue
+			} else {
+				vs[i] = (*d.Data[x].Values)[j]
+			}
+		}
+		lo, ok := digestRank(vs[:], lower)
+		if !ok {
+			return NilValue
+		}
+		hi, _ := digestRank(vs[:], upper)
+		return math.Max(0, hi-lo)
+	})
+}
+
+// evaluates "histogram_*" function argument, "le" histograms are reduced by "buckets" function,
+// value_p metrics by "digest" function, nil "digest" means result was selected by query (see "histogramWhats"),
+// nil "buckets" means function can not be computed from "le" buckets
+func (ev *evaluator) evalHistogram(fn string, expr parser.Expr, buckets func(h *histogram, j int) float64, digest func(d *digest, j int) float64) ([]Series, error) {
+	res, err := ev.eval(expr)
+	if err != nil {
+		return nil, err
+	}
+	for i := range res {
+		if res[i].empty() {
+			continue
+		}
+		if res[i].Meta.Metric != nil && res[i].Meta.Metric.HasPercentiles {
+			if digest == nil {
+				continue
+			}
+			ds, err := res[i].digests(ev)
+			if err != nil {
+				return nil, fmt.Errorf("failed to restore digest: %v", err)
+			}
+			sr := ev.newSeries(len(ds), res[i].Meta)
+			for k := range ds {
+				d := &ds[k]
+				var x int // first series found
+				for d.xs[x] == -1 {
+					x++
+				}
+				s := *res[i].Data[d.xs[x]].Values
+				for j := range s {
+					s[j] = digest(d, j)
+				}
+				ev.freeAll(d.data()[1:])
+				sr.appendAll(d.seriesAt(x))
+			}
+			res[i] = sr
+			continue
+		}
+		if buckets == nil {
+			if res[i].Meta.Metric == nil {
+				return nil, fmt.Errorf("%s() is not supported for \"le\" histograms", fn)
+			}
+			name := res[i].Meta.Metric.Name
+			if family, ok := strings.CutSuffix(name, "_bucket"); ok {
+				return nil, fmt.Errorf("%s() is not supported for \"le\" histogram %q, use %q metric value instead", fn, name, family+"_sum")
+			}
+			return nil, fmt.Errorf("%s() is not supported for \"le\" histogram %q", fn, name)
+		}
+		hs, err := res[i].histograms(ev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore histogram: %v", err)
+		}
+		sr := ev.newSeries(len(hs), res[i].Meta)
+		for k := range hs {
+			h := &hs[k]
+			s := *res[i].Data[h.buckets[0].x].Values
+			for j := range s {
+				s[j] = buckets(h, j)
+			}
+			ev.freeAll(h.data()[1:])
+			sr.appendAll(h.seriesAt(0))
+		}
+		res[i] = sr
+	}
+	return res, nil
+}
+
+// fraction of bucket (lo, hi] observations not greater than "x", observations are assumed
+// to be evenly distributed inside bucket, infinite bucket observations are at its finite bound
+func bucketFraction(lo, hi, x float64) float64 {
+	switch {
+	case x >= hi:
+		return 1
+	case x <= lo:
+		return 0
+	case math.IsInf(hi, 1):
+		return 1
+	case math.IsInf(lo, -1):
+		return 0
+	default:
+		return (x - lo) / (hi - lo)
+	}
+}
+
+// fraction of observations not greater than "x", cumulative distribution function
+// is interpolated linearly between percentiles, "vs" are indexed as "digestQuantiles"
+func digestRank(vs []float64, x float64) (float64, bool) {
+	var (
+		n     int
+		prevV float64
+		prevQ float64
+	)
+	for i, v := range vs {
+		if math.IsNaN(v) || (n != 0 && v < prevV) {
+			continue
+		}
+		q := digestQuantiles[i].q
+		if x < v {
+			if n == 0 {
+				return 0, true
+			}
+			return prevQ + (q-prevQ)*(x-prevV)/(v-prevV), true
+		}
+		prevV, prevQ = v, q
+		n++
+	}
+	return prevQ, n != 0
+}
+
 func funcLabelJoin(ev *evaluator, args parser.Expressions) ([]Series, error) {
 	if len(args) < 3 {
 		return nil, fmt.Errorf("invalid argument count in label_join(): expected at least 3, got %d", len(args))
@@ -12,6 +12,8 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"pgregory.net/rapid"
+
+	"github.com/vkcom/statshouse/internal/promql/parser"
 )
 
 func testWindow(t require.TestingT, values []float64, step, width int64, strict bool) {
@@ -136,3 +138,51 @@ func TestWindowRandom(t *testing.T) {
 		testWindow(t, values, step, width, strict)
 	})
 }
+
+func TestBucketFraction(t *testing.T) {
+	require.Equal(t, 0.0, bucketFraction(0.1, 0.5, 0.1))
+	require.InDelta(t, 0.5, bucketFraction(0.1, 0.5, 0.3), 1e-9)
+	require.Equal(t, 1.0, bucketFraction(0.1, 0.5, 0.5))
+	require.Equal(t, 1.0, bucketFraction(0.5, math.Inf(1), 0.6))
+	require.Equal(t, 0.0, bucketFraction(math.Inf(-1), -1, -2))
+}
+
+func TestHistogramArgNotLiteral(t *testing.T) {
+	vec := &parser.VectorSelector{Name: "latency"}
+	_, err := funcHistogramFraction(nil, parser.Expressions{vec, &parser.NumberLiteral{Val: 1}, vec})
+	require.Error(t, err)
+	_, err = funcHistogramFraction(nil, parser.Expressions{&parser.NumberLiteral{Val: 0}, vec, vec})
+	require.Error(t, err)
+	_, err = funcHistogramQuantile(nil, parser.Expressions{vec, vec})
+	require.Error(t, err)
+}
+
+func TestDigestRank(t *testing.T) {
+	vs := make([]float64, len(digestQuantiles))
+	for i := range vs {
+		vs[i] = digestQuantiles[i].q * 100 // uniform distribution on [0, 100]
+	}
+	for _, x := range []float64{0, 0.05, 1, 10, 30, 50, 99.95, 100} {
+		v, ok := digestRank(vs, x)
+		require.True(t, ok)
+		require.InDelta(t, x/100, v, 1e-9)
+	}
+	v, _ := digestRank(vs, -1)
+	require.Equal(t, 0.0, v)
+	v, _ = digestRank(vs, 101)
+	require.Equal(t, 1.0, v)
+	// constant value
+	for i := range vs {
+		vs[i] = 5
+	}
+	v, _ = digestRank(vs, 4)
+	require.Equal(t, 0.0, v)
+	v, _ = digestRank(vs, 5)
+	require.Equal(t, 1.0, v)
+	// no data
+	for i := range vs {
+		vs[i] = NilValue
+	}
+	_, ok := digestRank(vs, 5)
+	require.False(t, ok)
+}
@@ -208,6 +208,7 @@ type VectorSelector struct {
 	MaxHostMatchers []*labels.Matcher
 	OmitNameTag     bool
 	Offsets         []int64
+	Histogram       string // enclosing "histogram_*" function name
 }
 
 // TestStmt is an internal helper statement that allows execution
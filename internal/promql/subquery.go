@@ -0,0 +1,315 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package promql
+
+// Expressions with "@" modifier and subqueries with explicit step are evaluated on their own timescale,
+// then resampled to the timescale of enclosing expression. Expression with "@" modifier is evaluated
+// as instant query at "@" time, its value is the same for all points. Subquery enclosing function is
+// evaluated with subquery step, its value at each point is the last one calculated up to this point.
+// Subquery offset is added to offsets of all its selectors.
+
+import (
+	"math"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/promql/parser"
+)
+
+const atLookback = 5 * 60 // same as Prometheus lookback delta
+
+const (
+	exprVarying = iota // depends on evaluation time
+	exprConst          // has no selectors
+	exprFixed          // all selectors are fixed with the same "@" modifier
+)
+
+// functions which depend on evaluation time even if arguments do not
+var atModifierUnsafeFunctions = map[string]bool{
+	"days_in_month":  true,
+	"day_of_month":   true,
+	"day_of_week":    true,
+	"day_of_year":    true,
+	"hour":           true,
+	"minute":         true,
+	"month":          true,
+	"year":           true,
+	"predict_linear": true,
+	"time":           true,
+	"timestamp":      true,
+}
+
+type exprTimescale struct {
+	t     data_model.Timescale
+	at    int64 // "@" modifier value, seconds
+	fixed bool  // evaluated at "at"
+	step  int64 // subquery step
+}
+
+func (ev *evaluator) prepareSubqueries(qry Query) {
+	ev.ets = make(map[parser.Expr]*exprTimescale)
+	// resolve "start()" and "end()"
+	parser.Inspect(ev.ast, func(node parser.Node, _ []parser.Node) error {
+		switch e := node.(type) {
+		case *parser.VectorSelector:
+			e.Timestamp = resolveAtModifier(e.Timestamp, e.StartOrEnd, qry)
+		case *parser.SubqueryExpr:
+			e.Timestamp = resolveAtModifier(e.Timestamp, e.StartOrEnd, qry)
+		}
+		return nil
+	})
+	if kind, at := ev.markFixedExprs(ev.ast); kind == exprFixed {
+		ev.ets[ev.ast] = &exprTimescale{at: at, fixed: true}
+	}
+	if qry.Options.Mode == data_model.PointQuery {
+		return // single point, step does not matter
+	}
+	parser.Inspect(ev.ast, func(node parser.Node, path []parser.Node) error {
+		e, ok := node.(*parser.SubqueryExpr)
+		if !ok || e.Step == 0 || len(path) == 0 {
+			return nil
+		}
+		call, ok := path[len(path)-1].(*parser.Call)
+		if !ok {
+			return nil
+		}
+		for _, p := range path {
+			if p, ok := p.(parser.Expr); ok && ev.ets[p] != nil && ev.ets[p].fixed {
+				return nil // subquery step is taken into account by "@" modifier timescale
+			}
+		}
+		if s, ok := ev.ets[call]; !ok || e.Step < s.step {
+			ev.ets[call] = &exprTimescale{step: e.Step}
+		}
+		return nil
+	})
+}
+
+func resolveAtModifier(ts *int64, startOrEnd parser.ItemType, qry Query) *int64 {
+	var v int64
+	switch startOrEnd {
+	case parser.START:
+		v = qry.Start * 1000
+	case parser.END:
+		v = (qry.End - 1) * 1000 // end is exclusive
+	default:
+		return ts
+	}
+	return &v
+}
+
+// marks subexpressions which do not depend on evaluation time, returns expression kind and "@" modifier value
+func (ev *evaluator) markFixedExprs(node parser.Node) (int, int64) {
+	var at int64
+	switch e := node.(type) {
+	case *parser.VectorSelector:
+		if e.Timestamp == nil {
+			return exprVarying, 0
+		}
+		return exprFixed, int64(math.Floor(float64(*e.Timestamp) / 1000))
+	case *parser.MatrixSelector:
+		return ev.markFixedExprs(e.VectorSelector)
+	case *parser.SubqueryExpr:
+		kind, exprAt := ev.markFixedExprs(e.Expr)
+		if e.Timestamp == nil {
+			return kind, exprAt
+		}
+		at = int64(math.Floor(float64(*e.Timestamp) / 1000))
+		if kind == exprFixed && exprAt != at {
+			ev.markFixedExpr(e.Expr, exprAt)
+		}
+		return exprFixed, at
+	case *parser.NumberLiteral, *parser.StringLiteral:
+		return exprConst, 0
+	}
+	children := parser.Children(node)
+	kinds := make([]int, len(children))
+	ats := make([]int64, len(children))
+	kind := exprConst
+	for i, c := range children {
+		kinds[i], ats[i] = ev.markFixedExprs(c)
+		switch {
+		case kinds[i] == exprVarying:
+			kind = exprVarying
+		case kinds[i] == exprFixed && kind == exprConst:
+			kind, at = exprFixed, ats[i]
+		case kinds[i] == exprFixed && ats[i] != at:
+			kind = exprVarying
+		}
+	}
+	if e, ok := node.(*parser.Call); ok && atModifierUnsafeFunctions[e.Func.Name] {
+		kind = exprVarying
+	}
+	if kind == exprVarying {
+		for i, c := range children {
+			if kinds[i] == exprFixed {
+				ev.markFixedExpr(c, ats[i])
+			}
+		}
+	}
+	return kind, at
+}
+
+func (ev *evaluator) markFixedExpr(node parser.Node, at int64) {
+	if e, ok := node.(parser.Expr); ok && e.Type() != parser.ValueTypeMatrix {
+		ev.ets[e] = &exprTimescale{at: at, fixed: true}
+	}
+}
+
+// reduction rules assume evaluator timescale, expressions evaluated on their own are not reduced
+func (ev *evaluator) hasSubquery(path []parser.Node) bool {
+	for _, p := range path {
+		if e, ok := p.(parser.Expr); ok && ev.ets[e] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// maximum sum of range selector and subquery ranges on the path from expression to selector
+func exprRange(node parser.Node) int64 {
+	var res int64
+	parser.Inspect(node, func(node parser.Node, path []parser.Node) error {
+		if _, ok := node.(*parser.VectorSelector); !ok {
+			return nil
+		}
+		var v int64
+		for _, p := range path {
+			switch e := p.(type) {
+			case *parser.MatrixSelector:
+				v += e.Range
+			case *parser.SubqueryExpr:
+				v += e.Range
+			}
+		}
+		if res < v {
+			res = v
+		}
+		return nil
+	})
+	return res
+}
+
+// minimum subquery step
+func exprStep(node parser.Node) int64 {
+	var res int64
+	parser.Inspect(node, func(node parser.Node, _ []parser.Node) error {
+		if e, ok := node.(*parser.SubqueryExpr); ok && e.Step != 0 && (res == 0 || e.Step < res) {
+			res = e.Step
+		}
+		return nil
+	})
+	return res
+}
+
+func (ev *evaluator) initSubqueryTimescales(qry Query) error {
+	for e, s := range ev.ets {
+		args := data_model.GetTimescaleArgs{
+			QueryStat: ev.QueryStat,
+			Version:   qry.Options.Version,
+			TimeNow:   qry.Options.TimeNow,
+			Location:  ev.location,
+			UTCOffset: ev.utcOffset,
+		}
+		if s.fixed {
+			args.Start = s.at - exprRange(e) - atLookback
+			args.End = s.at + 1
+			args.Step = exprStep(e)
+			args.Mode = data_model.InstantQuery
+		} else {
+			args.Start = qry.Start - exprRange(e)
+			args.End = qry.End
+			args.Step = s.step
+			args.Mode = data_model.RangeQuery
+		}
+		var err error
+		if s.t, err = data_model.GetTimescale(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ev *evaluator) evalSubquery(expr parser.Expr, s *exprTimescale) ([]Series, error) {
+	if s.t.Empty() {
+		return make([]Series, len(ev.opt.Offsets)), nil
+	}
+	sub := *ev
+	sub.t = s.t
+	sub.et = s
+	sub.r = 0
+	sub.opt.Mode = data_model.RangeQuery
+	if s.fixed {
+		sub.opt.Mode = data_model.InstantQuery
+	}
+	sub.tags = make(map[*format.MetricMetaValue][]map[int64]map[int32]string)
+	sub.stop = make(map[*format.MetricMetaValue]map[int64][]string)
+	sub.allocMap = nil
+	sub.freeList = nil
+	sub.reuseList = nil
+	sub.cancellationList = nil
+	defer sub.cancel() // result is copied into evaluator buffers
+	res, err := sub.eval(expr)
+	ev.dataAccessDuration = sub.dataAccessDuration
+	if err != nil {
+		return nil, err
+	}
+	// index of subquery point for each point
+	xs := make([]int, len(ev.time()))
+	if !s.fixed {
+		resampleTime(xs, ev.time(), s.t.Time)
+	}
+	for i := range res {
+		for j := range res[i].Data {
+			d := &res[i].Data[j]
+			if d.Values == nil {
+				continue
+			}
+			src := *d.Values
+			if s.fixed {
+				x := len(src) - 1
+				for x >= 0 && math.IsNaN(src[x]) {
+					x--
+				}
+				for k := range xs {
+					xs[k] = x
+				}
+			}
+			d.Values = ev.alloc()
+			for k, x := range xs {
+				if x < 0 {
+					(*d.Values)[k] = NilValue
+				} else {
+					(*d.Values)[k] = src[x]
+				}
+			}
+			for z, h := range d.MinMaxHost {
+				if len(h) == 0 {
+					continue
+				}
+				d.MinMaxHost[z] = make([]int32, len(xs))
+				for k, x := range xs {
+					if x >= 0 {
+						d.MinMaxHost[z][k] = h[x]
+					}
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// sets "xs" to index of last "src" time not greater than "dst" time, -1 if not found
+func resampleTime(xs []int, dst []int64, src []int64) {
+	x := -1
+	for k, t := range dst {
+		for x+1 < len(src) && src[x+1] <= t {
+			x++
+		}
+		xs[k] = x
+	}
+}
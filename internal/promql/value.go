@@ -77,6 +77,11 @@ type bucket struct {
 	le float32 // decoded "le" tag value
 }
 
+type digest struct {
+	*Series
+	xs [len(digestQuantiles)]int // series index by "digestQuantiles" index, -1 if not found
+}
+
 type hashOptions struct {
 	on    bool
 	tags  []string
@@ -209,7 +214,7 @@ func (sr *Series) histograms(ev *evaluator) ([]histogram, error) {
 	if sr.Meta.Metric == nil {
 		return nil, fmt.Errorf("metric meta not found")
 	}
-	if len(sr.Meta.Metric.HistorgamBuckets) == 0 {
+	if len(sr.Meta.Metric.HistogramBuckets) == 0 {
 		return nil, fmt.Errorf("histogram meta not found")
 	}
 	m, _, err := sr.group(ev, hashOptions{
@@ -221,7 +226,7 @@ func (sr *Series) histograms(ev *evaluator) ([]histogram, error) {
 	}
 	var res []histogram
 	for _, xs := range m {
-		buckets := make([]bucket, 0, len(sr.Meta.Metric.HistorgamBuckets))
+		buckets := make([]bucket, 0, len(sr.Meta.Metric.HistogramBuckets))
 		for _, x := range xs {
 			if t, ok := sr.Data[x].Tags.Get(labels.BucketLabel); ok {
 				var le float32
@@ -251,6 +256,37 @@ func (sr *Series) histograms(ev *evaluator) ([]histogram, error) {
 	return res, nil
 }
 
+func (sr *Series) digests(ev *evaluator) ([]digest, error) {
+	m, _, err := sr.group(ev, hashOptions{
+		tags: []string{LabelWhat},
+		on:   false, // group excluding LabelWhat
+	})
+	if err != nil {
+		return nil, err
+	}
+	var res []digest
+	for _, xs := range m {
+		d := digest{Series: sr}
+		for i := range d.xs {
+			d.xs[i] = -1
+		}
+		var n int
+		for _, x := range xs {
+			for i := range digestQuantiles {
+				if sr.Data[x].What.Digest == digestQuantiles[i].what {
+					d.xs[i] = x
+					n++
+					break
+				}
+			}
+		}
+		if n != 0 {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
 func (sr *Series) scalar() bool {
 	if len(sr.Data) != 1 {
 		return false
@@ -277,6 +313,25 @@ func (h *histogram) seriesAt(x int) Series {
 	}
 }
 
+func (h *histogram) valueAt(k, j int) float64 {
+	return (*h.Data[h.buckets[k].x].Values)[j]
+}
+
+// bucket "k" lower and upper bounds, first bucket starts at zero unless its upper bound is not positive
+func (h *histogram) bounds(k int) (float64, float64) {
+	le := h.buckets[k].le
+	s := h.Meta.Metric.HistogramBuckets
+	x := sort.Search(len(s), func(i int) bool { return s[i] >= le })
+	switch {
+	case x != 0:
+		return float64(s[x-1]), float64(le)
+	case le > 0:
+		return 0, float64(le)
+	default:
+		return math.Inf(-1), float64(le)
+	}
+}
+
 func (h *histogram) data() []SeriesData {
 	res := make([]SeriesData, 0, len(h.buckets))
 	for _, b := range h.buckets {
@@ -285,6 +340,26 @@ func (h *histogram) data() []SeriesData {
 	return res
 }
 
+func (d *digest) seriesAt(i int) Series {
+	data := d.Data[d.xs[i] : d.xs[i]+1]
+	data[0].Tags.remove(LabelWhat)
+	data[0].What = SelectorWhat{}
+	return Series{
+		Data: data,
+		Meta: d.Meta,
+	}
+}
+
+func (d *digest) data() []SeriesData {
+	res := make([]SeriesData, 0, len(d.xs))
+	for _, x := range d.xs {
+		if x != -1 {
+			res = append(res, d.Data[x])
+		}
+	}
+	return res
+}
+
 type secondsFormat struct {
 	n int32  // number of seconds
 	s string // corresponding format string
@@ -0,0 +1,471 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/vkcom/statshouse-go"
+	"github.com/vkcom/statshouse/internal/agent"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+const (
+	OTLPHTTPPath = "/v1/metrics"
+	OTLPGRPCPath = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+
+	otlpMaxRequestSize = 64 << 20
+	otlpSeriesTTL      = time.Hour // cumulative state of series not seen for this long is forgotten
+
+	grpcStatusOK              = 0
+	grpcStatusInvalidArgument = 3
+	grpcStatusUnimplemented   = 12
+)
+
+// OTLP receives OpenTelemetry metrics over OTLP/HTTP (protobuf and JSON encodings)
+// and OTLP/gRPC (served over cleartext HTTP/2 on the same port).
+//
+// Resource, scope and data point attributes become tags, cumulative sums and histograms
+// are converted to deltas per series, so only the first data point of each series is lost.
+type OTLP struct {
+	handler Handler
+
+	mu          sync.Mutex
+	series      map[uint64]*otlpSeries
+	hash        hash.Hash64
+	lastCleanup time.Time
+
+	batchSizeOK   *agent.BuiltInItemValue
+	batchSizeErr  *agent.BuiltInItemValue
+	packetSizeOK  *agent.BuiltInItemValue
+	packetSizeErr *agent.BuiltInItemValue
+
+	StatRequestsTotalOK  atomic.Uint64
+	StatRequestsTotalErr atomic.Uint64
+}
+
+type otlpSeries struct {
+	start   uint64 // start_time_unix_nano of the last data point, change means counter reset
+	value   float64
+	sum     float64
+	buckets []float64
+	seen    time.Time
+}
+
+func MakeOTLP(ag *agent.Agent, h Handler) *OTLP {
+	return &OTLP{
+		handler:       h,
+		series:        map[uint64]*otlpSeries{},
+		hash:          fnv.New64a(),
+		lastCleanup:   time.Now(),
+		batchSizeOK:   createBatchSizeValue(ag, format.TagValueIDPacketFormatOTLP, format.TagValueIDAgentReceiveStatusOK),
+		batchSizeErr:  createBatchSizeValue(ag, format.TagValueIDPacketFormatOTLP, format.TagValueIDAgentReceiveStatusError),
+		packetSizeOK:  createPacketSizeValue(ag, format.TagValueIDPacketFormatOTLP, format.TagValueIDAgentReceiveStatusOK),
+		packetSizeErr: createPacketSizeValue(ag, format.TagValueIDPacketFormatOTLP, format.TagValueIDAgentReceiveStatusError),
+	}
+}
+
+// Serve blocks until listener is closed
+func (r *OTLP) Serve(ln net.Listener) error {
+	server := http.Server{Handler: h2c.NewHandler(r, &http2.Server{})}
+	err := server.Serve(ln)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (r *OTLP) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	contentType := req.Header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "application/grpc") {
+		r.serveGRPC(w, req)
+		return
+	}
+	if req.URL.Path != OTLPHTTPPath {
+		http.Error(w, "unknown path, OTLP metrics are accepted at "+OTLPHTTPPath, http.StatusNotFound)
+		return
+	}
+	body, err := readOTLPBody(req.Body, req.Header.Get("Content-Encoding"))
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	isJSON := strings.HasPrefix(contentType, "application/json")
+	if err = r.handleRequest(body, isJSON); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// ExportMetricsServiceResponse with no partial_success is empty message in both encodings
+	if isJSON {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// gRPC message is prefixed by 1 byte compression flag and 4 bytes big-endian length,
+// status is reported in trailers, so HTTP status is always 200
+func (r *OTLP) serveGRPC(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	status, err := grpcStatusOK, error(nil)
+	defer func() {
+		if err != nil {
+			w.Header().Set("Grpc-Message", err.Error())
+		}
+		w.Header().Set("Grpc-Status", strconv.Itoa(status))
+	}()
+	if req.URL.Path != OTLPGRPCPath {
+		status, err = grpcStatusUnimplemented, fmt.Errorf("unknown method %q", req.URL.Path)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, otlpMaxRequestSize))
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		status = grpcStatusInvalidArgument
+		return
+	}
+	for len(body) != 0 {
+		if len(body) < 5 {
+			status, err = grpcStatusInvalidArgument, fmt.Errorf("truncated gRPC message header")
+			break
+		}
+		compressed := body[0] != 0
+		n := binary.BigEndian.Uint32(body[1:5])
+		if uint64(len(body)-5) < uint64(n) {
+			status, err = grpcStatusInvalidArgument, fmt.Errorf("truncated gRPC message body")
+			break
+		}
+		msg := body[5 : 5+n]
+		body = body[5+n:]
+		if compressed {
+			if msg, err = readOTLPBody(bytes.NewReader(msg), req.Header.Get("Grpc-Encoding")); err != nil {
+				status = grpcStatusInvalidArgument
+				break
+			}
+		}
+		if err = r.handleRequest(msg, false); err != nil {
+			status = grpcStatusInvalidArgument
+			w.WriteHeader(http.StatusOK)
+			return // already counted by handleRequest
+		}
+	}
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	_, _ = w.Write([]byte{0, 0, 0, 0, 0}) // empty ExportMetricsServiceResponse
+}
+
+func readOTLPBody(body io.Reader, encoding string) ([]byte, error) {
+	body = io.LimitReader(body, otlpMaxRequestSize)
+	switch encoding {
+	case "", "identity":
+	case "gzip":
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		body = io.LimitReader(zr, otlpMaxRequestSize)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", encoding)
+	}
+	return io.ReadAll(body)
+}
+
+func (r *OTLP) handleRequest(pkt []byte, isJSON bool) error {
+	var req otlpRequest
+	var err error
+	if isJSON {
+		var data []byte
+		if data, err = otlpCamelCaseKeys(pkt); err == nil {
+			err = json.Unmarshal(data, &req)
+		}
+	} else {
+		err = otlpUnmarshalRequest(pkt, &req)
+	}
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		r.handler.HandleParseError(pkt, err)
+		setValueSize(r.batchSizeErr, len(pkt))
+		setValueSize(r.packetSizeErr, len(pkt))
+		return err
+	}
+	r.StatRequestsTotalOK.Inc()
+	setValueSize(r.batchSizeOK, len(pkt))
+	setValueSize(r.packetSizeOK, len(pkt))
+	r.handleMetrics(&req, time.Now())
+	return nil
+}
+
+func (r *OTLP) handleMetrics(req *otlpRequest, now time.Time) {
+	var b tlstatshouse.MetricBytes
+	var common []otlpKeyValue
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			common = append(append(common[:0], rm.Resource.Attributes...), sm.Scope.Attributes...)
+			for i := range sm.Metrics {
+				r.handleMetric(&b, &sm.Metrics[i], common, now)
+			}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if now.Sub(r.lastCleanup) > otlpSeriesTTL {
+		for k, s := range r.series {
+			if now.Sub(s.seen) > otlpSeriesTTL {
+				delete(r.series, k)
+			}
+		}
+		r.lastCleanup = now
+	}
+}
+
+func (r *OTLP) handleMetric(b *tlstatshouse.MetricBytes, m *otlpMetric, common []otlpKeyValue, now time.Time) {
+	name := sanitizeName(m.Name, true)
+	switch {
+	case m.Gauge != nil:
+		for i := range m.Gauge.DataPoints {
+			p := &m.Gauge.DataPoints[i]
+			r.resetMetric(b, name, "", common, p.Attributes, uint64(p.TimeUnixNano))
+			setMetricValue(b, p.value())
+			r.emit(b, m.Description)
+		}
+	case m.Sum != nil:
+		for i := range m.Sum.DataPoints {
+			p := &m.Sum.DataPoints[i]
+			v := p.value()
+			if !m.Sum.IsMonotonic { // up-down counters have gauge semantic
+				r.resetMetric(b, name, "", common, p.Attributes, uint64(p.TimeUnixNano))
+				setMetricValue(b, v)
+				r.emit(b, m.Description)
+				continue
+			}
+			if m.Sum.AggregationTemporality == otlpTemporalityCumulative {
+				var ok bool
+				if v, _, ok = r.cumulativeDelta(name, common, p.Attributes, uint64(p.StartTimeUnixNano), v, 0, nil, now); !ok {
+					continue
+				}
+			}
+			if v > 0 {
+				r.resetMetric(b, name, "", common, p.Attributes, uint64(p.TimeUnixNano))
+				b.SetCounter(v)
+				r.emit(b, m.Description)
+			}
+		}
+	case m.Histogram != nil:
+		for i := range m.Histogram.DataPoints {
+			r.handleHistogram(b, name, m.Description, m.Histogram.AggregationTemporality, &m.Histogram.DataPoints[i], common, now)
+		}
+	case m.ExponentialHistogram != nil:
+		for i := range m.ExponentialHistogram.DataPoints {
+			r.handleExponentialHistogram(b, name, m.Description, m.ExponentialHistogram.AggregationTemporality, &m.ExponentialHistogram.DataPoints[i], common, now)
+		}
+	}
+}
+
+// Explicit bucket histograms are written the same way as scraped Prometheus histograms,
+// "_bucket" counter with "le" tag and "_sum" with count and sum, so histogram_quantile works on them
+func (r *OTLP) handleHistogram(b *tlstatshouse.MetricBytes, name string, description string, temporality otlpTemporality, p *otlpHistogramDataPoint, common []otlpKeyValue, now time.Time) {
+	count, sum := float64(p.Count), float64(p.Sum)
+	buckets := make([]float64, len(p.BucketCounts))
+	for i, c := range p.BucketCounts {
+		buckets[i] = float64(c)
+	}
+	if temporality == otlpTemporalityCumulative {
+		var ok bool
+		if count, sum, ok = r.cumulativeDelta(name, common, p.Attributes, uint64(p.StartTimeUnixNano), count, sum, buckets, now); !ok {
+			return
+		}
+	}
+	if len(buckets) != 0 && len(buckets) == len(p.ExplicitBounds)+1 {
+		bounds := make([]string, len(buckets))
+		tags := make([]string, len(buckets))
+		for i := range buckets {
+			bound := math.Inf(1)
+			if i < len(p.ExplicitBounds) {
+				bound = float64(p.ExplicitBounds[i])
+			}
+			bounds[i] = strconv.FormatFloat(bound, 'f', -1, 64)
+			tags[i] = strconv.FormatInt(int64(statshouse.LexEncode(float32(bound))), 10)
+		}
+		descriptionB := histogramBucketsDescription(description, bounds)
+		for i, v := range buckets {
+			if v > 0 {
+				r.resetMetric(b, name, "_bucket", common, p.Attributes, uint64(p.TimeUnixNano))
+				b.Tags = appendTag(b.Tags, format.LETagName, tags[i])
+				b.SetCounter(v)
+				r.emit(b, descriptionB)
+			}
+		}
+	}
+	if count > 0 {
+		r.resetMetric(b, name, "_sum", common, p.Attributes, uint64(p.TimeUnixNano))
+		b.SetCounter(count)
+		setMetricValue(b, sum)
+		r.emit(b, description)
+	}
+}
+
+// Exponential histogram bucket boundaries depend on scale which can change between data points,
+// so they cannot be mapped to stable "le" tag values. Instead each non-empty bucket is written
+// as a single value (bucket midpoint) with counter set to bucket population.
+func (r *OTLP) handleExponentialHistogram(b *tlstatshouse.MetricBytes, name string, description string, temporality otlpTemporality, p *otlpExponentialHistogramDataPoint, common []otlpKeyValue, now time.Time) {
+	base := math.Exp2(math.Exp2(-float64(p.Scale)))
+	values := make([]float64, 0, 1+len(p.Positive.BucketCounts)+len(p.Negative.BucketCounts))
+	counts := make([]float64, 0, cap(values))
+	values = append(values, 0)
+	counts = append(counts, float64(p.ZeroCount))
+	for i, c := range p.Positive.BucketCounts {
+		lo := math.Pow(base, float64(int(p.Positive.Offset)+i))
+		values = append(values, (lo+lo*base)/2)
+		counts = append(counts, float64(c))
+	}
+	for i, c := range p.Negative.BucketCounts {
+		lo := math.Pow(base, float64(int(p.Negative.Offset)+i))
+		values = append(values, -(lo+lo*base)/2)
+		counts = append(counts, float64(c))
+	}
+	if temporality == otlpTemporalityCumulative {
+		// layout of buckets depends on scale and offsets, so they are part of series identity
+		key := append(p.Attributes[:len(p.Attributes):len(p.Attributes)],
+			otlpKeyValue{Key: "\x00scale", Value: otlpAnyValue{StringValue: otlpLayoutString(p)}})
+		if _, _, ok := r.cumulativeDelta(name, common, key, uint64(p.StartTimeUnixNano), 0, 0, counts, now); !ok {
+			return
+		}
+	}
+	for i, c := range counts {
+		if c > 0 && format.ValidFloatValue(values[i]) {
+			r.resetMetric(b, name, "", common, p.Attributes, uint64(p.TimeUnixNano))
+			b.SetCounter(c)
+			setMetricValue(b, values[i])
+			r.emit(b, description)
+		}
+	}
+}
+
+func otlpLayoutString(p *otlpExponentialHistogramDataPoint) *string {
+	s := fmt.Sprintf("%d,%d,%d,%d,%d", p.Scale, p.Positive.Offset, len(p.Positive.BucketCounts), p.Negative.Offset, len(p.Negative.BucketCounts))
+	return &s
+}
+
+// cumulativeDelta replaces value, sum and buckets with difference from previous data point of the same series.
+// Returns false for the first data point of a series, because there is nothing to subtract from it.
+func (r *OTLP) cumulativeDelta(name string, common []otlpKeyValue, attrs []otlpKeyValue, start uint64, value float64, sum float64, buckets []float64, now time.Time) (float64, float64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hash.Write([]byte(name))
+	for _, attrs := range [2][]otlpKeyValue{common, attrs} {
+		for _, kv := range attrs {
+			v, _ := kv.Value.String()
+			r.hash.Write([]byte{0})
+			r.hash.Write([]byte(kv.Key))
+			r.hash.Write([]byte{0})
+			r.hash.Write([]byte(v))
+		}
+	}
+	hashSum := r.hash.Sum64()
+	r.hash.Reset()
+	prev := r.series[hashSum]
+	if prev == nil {
+		r.series[hashSum] = &otlpSeries{start: start, value: value, sum: sum, buckets: append([]float64(nil), buckets...), seen: now}
+		return 0, 0, false
+	}
+	reset := (start != 0 && start != prev.start) || value < prev.value || len(buckets) != len(prev.buckets)
+	for i := 0; i < len(buckets) && !reset; i++ {
+		reset = buckets[i] < prev.buckets[i]
+	}
+	dv, ds := value, sum
+	if !reset {
+		dv -= prev.value
+		ds -= prev.sum
+	}
+	for i := range buckets {
+		v := buckets[i]
+		if !reset {
+			buckets[i] -= prev.buckets[i]
+		}
+		if i < len(prev.buckets) {
+			prev.buckets[i] = v
+		} else {
+			prev.buckets = append(prev.buckets, v)
+		}
+	}
+	prev.buckets = prev.buckets[:len(buckets)]
+	prev.start, prev.value, prev.sum, prev.seen = start, value, sum, now
+	return dv, ds, true
+}
+
+func (r *OTLP) resetMetric(b *tlstatshouse.MetricBytes, name string, suffix string, common []otlpKeyValue, attrs []otlpKeyValue, timeUnixNano uint64) {
+	b.Reset()
+	b.Name = append(append(b.Name[:0], name...), suffix...)
+	for _, attrs := range [2][]otlpKeyValue{common, attrs} {
+		for _, kv := range attrs {
+			if v, ok := kv.Value.String(); ok {
+				b.Tags = appendTag(b.Tags, sanitizeName(kv.Key, false), v)
+			}
+		}
+	}
+	if ts := timeUnixNano / uint64(time.Second); ts != 0 && ts <= math.MaxUint32 {
+		b.SetTs(uint32(ts))
+	}
+}
+
+func (r *OTLP) emit(b *tlstatshouse.MetricBytes, description string) {
+	r.handler.HandleMetrics(data_model.HandlerArgs{
+		MetricBytes: b,
+		Description: description,
+	})
+}
+
+func histogramBucketsDescription(description string, buckets []string) string {
+	var sb strings.Builder
+	if len(description) != 0 {
+		sb.WriteString(description)
+		sb.WriteByte('\n')
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(format.HistogramBucketsStartMark)
+	for i, s := range buckets {
+		if i != 0 {
+			sb.WriteByte(format.HistogramBucketsDelimC)
+		}
+		sb.WriteString(s)
+	}
+	sb.WriteByte(format.HistogramBucketsEndMarkC)
+	return sb.String()
+}
@@ -0,0 +1,998 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+// Hand-written decoders for subset of opentelemetry/proto/collector/metrics/v1
+// ExportMetricsServiceRequest, we do not want to depend on generated OTLP code.
+// Field numbers are from opentelemetry/proto/metrics/v1/metrics.proto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	otlpTemporalityUnspecified = 0
+	otlpTemporalityDelta       = 1
+	otlpTemporalityCumulative  = 2
+)
+
+type otlpRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name       string         `json:"name"`
+	Version    string         `json:"version"`
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string      `json:"stringValue"`
+	BoolValue   *bool        `json:"boolValue"`
+	IntValue    *otlpInt64   `json:"intValue"`
+	DoubleValue *otlpFloat64 `json:"doubleValue"`
+}
+
+type otlpMetric struct {
+	Name                 string                    `json:"name"`
+	Description          string                    `json:"description"`
+	Unit                 string                    `json:"unit"`
+	Gauge                *otlpGauge                `json:"gauge"`
+	Sum                  *otlpSum                  `json:"sum"`
+	Histogram            *otlpHistogram            `json:"histogram"`
+	ExponentialHistogram *otlpExponentialHistogram `json:"exponentialHistogram"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality otlpTemporality       `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality otlpTemporality          `json:"aggregationTemporality"`
+}
+
+type otlpExponentialHistogram struct {
+	DataPoints             []otlpExponentialHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality otlpTemporality                     `json:"aggregationTemporality"`
+}
+
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      otlpUint64     `json:"timeUnixNano"`
+	AsDouble          *otlpFloat64   `json:"asDouble"`
+	AsInt             *otlpInt64     `json:"asInt"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      otlpUint64     `json:"timeUnixNano"`
+	Count             otlpUint64     `json:"count"`
+	Sum               otlpFloat64    `json:"sum"`
+	BucketCounts      []otlpUint64   `json:"bucketCounts"`
+	ExplicitBounds    []otlpFloat64  `json:"explicitBounds"`
+}
+
+type otlpExponentialHistogramDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      otlpUint64     `json:"timeUnixNano"`
+	Count             otlpUint64     `json:"count"`
+	Sum               otlpFloat64    `json:"sum"`
+	Scale             int32          `json:"scale"`
+	ZeroCount         otlpUint64     `json:"zeroCount"`
+	Positive          otlpBuckets    `json:"positive"`
+	Negative          otlpBuckets    `json:"negative"`
+}
+
+type otlpBuckets struct {
+	Offset       int32        `json:"offset"`
+	BucketCounts []otlpUint64 `json:"bucketCounts"`
+}
+
+// JSON encoding of OTLP follows proto3 JSON mapping, where 64-bit integers are strings,
+// special float values are strings and enums can be either names or numbers.
+
+type otlpUint64 uint64
+type otlpInt64 int64
+type otlpFloat64 float64
+type otlpTemporality int32
+
+func (v *otlpUint64) UnmarshalJSON(data []byte) error {
+	r, err := strconv.ParseUint(otlpUnquote(data), 10, 64)
+	*v = otlpUint64(r)
+	return err
+}
+
+func (v *otlpInt64) UnmarshalJSON(data []byte) error {
+	r, err := strconv.ParseInt(otlpUnquote(data), 10, 64)
+	*v = otlpInt64(r)
+	return err
+}
+
+func (v *otlpFloat64) UnmarshalJSON(data []byte) error {
+	s := otlpUnquote(data)
+	switch s {
+	case "NaN":
+		*v = otlpFloat64(math.NaN())
+	case "Infinity":
+		*v = otlpFloat64(math.Inf(1))
+	case "-Infinity":
+		*v = otlpFloat64(math.Inf(-1))
+	default:
+		r, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		*v = otlpFloat64(r)
+	}
+	return nil
+}
+
+func (v *otlpTemporality) UnmarshalJSON(data []byte) error {
+	switch s := otlpUnquote(data); s {
+	case "AGGREGATION_TEMPORALITY_UNSPECIFIED":
+		*v = otlpTemporalityUnspecified
+	case "AGGREGATION_TEMPORALITY_DELTA":
+		*v = otlpTemporalityDelta
+	case "AGGREGATION_TEMPORALITY_CUMULATIVE":
+		*v = otlpTemporalityCumulative
+	default:
+		r, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid aggregation temporality %q", s)
+		}
+		*v = otlpTemporality(r)
+	}
+	return nil
+}
+
+// OTLP/JSON allows both lowerCamelCase and original proto field names. encoding/json matches
+// names case-insensitively, so we only convert names with underscores, like "resource_metrics".
+// There are no maps with arbitrary keys in OTLP, attribute keys are values of "key" field.
+func otlpCamelCaseKeys(data []byte) ([]byte, error) {
+	if !bytes.ContainsRune(data, '_') {
+		return data, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	type container struct {
+		object bool
+		n      int // tokens written, keys and values alternate in objects
+	}
+	var (
+		res   = make([]byte, 0, len(data))
+		stack []container
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			stack = stack[:len(stack)-1]
+			res = append(res, byte(d))
+			continue
+		}
+		if len(stack) != 0 {
+			c := &stack[len(stack)-1]
+			switch {
+			case c.object && c.n%2 == 0:
+				if c.n != 0 {
+					res = append(res, ',')
+				}
+				c.n++
+				res = strconv.AppendQuote(res, otlpCamelCase(tok.(string)))
+				continue
+			case c.object:
+				res = append(res, ':')
+			case c.n != 0:
+				res = append(res, ',')
+			}
+			c.n++
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			stack = append(stack, container{object: v == '{'})
+			res = append(res, byte(v))
+		case string:
+			b, _ := json.Marshal(v)
+			res = append(res, b...)
+		case json.Number:
+			res = append(res, v...)
+		case bool:
+			res = strconv.AppendBool(res, v)
+		case nil:
+			res = append(res, "null"...)
+		}
+	}
+}
+
+func otlpCamelCase(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+	var sb strings.Builder
+	up := false
+	for _, c := range s {
+		switch {
+		case c == '_':
+			up = true
+		case up:
+			sb.WriteRune(unicode.ToUpper(c))
+			up = false
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+func otlpUnquote(data []byte) string {
+	s := string(data)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func (v otlpAnyValue) String() (string, bool) {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue, true
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue), true
+	case v.IntValue != nil:
+		return strconv.FormatInt(int64(*v.IntValue), 10), true
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(float64(*v.DoubleValue), 'f', -1, 64), true
+	}
+	return "", false // arrays, key-value lists and bytes are not supported as tag values
+}
+
+func (p *otlpNumberDataPoint) value() float64 {
+	switch {
+	case p.AsDouble != nil:
+		return float64(*p.AsDouble)
+	case p.AsInt != nil:
+		return float64(*p.AsInt)
+	}
+	return 0
+}
+
+func protoReadMessage(buf []byte) (data []byte, rest []byte, err error) {
+	data, n := protowire.ConsumeBytes(buf)
+	if n < 0 {
+		return nil, buf, protobufError(n)
+	}
+	return data, buf[n:], nil
+}
+
+func protoReadFixed64(buf []byte, result *uint64) ([]byte, error) {
+	data, n := protowire.ConsumeFixed64(buf)
+	if n < 0 {
+		return buf, protobufError(n)
+	}
+	*result = data
+	return buf[n:], nil
+}
+
+func protoReadVarint(buf []byte, result *uint64) ([]byte, error) {
+	data, n := protowire.ConsumeVarint(buf)
+	if n < 0 {
+		return buf, protobufError(n)
+	}
+	*result = data
+	return buf[n:], nil
+}
+
+func protoReadPackedFixed64(buf []byte, result *[]otlpUint64) ([]byte, error) {
+	data, n := protowire.ConsumeBytes(buf)
+	if n < 0 {
+		return buf, protobufError(n)
+	}
+	if len(data)%8 != 0 {
+		return buf, fmt.Errorf("packed fixed64 is not multiple of 8")
+	}
+	for i := 0; i < len(data); i += 8 {
+		*result = append(*result, otlpUint64(binary.LittleEndian.Uint64(data[i:])))
+	}
+	return buf[n:], nil
+}
+
+func protoReadPackedDouble(buf []byte, result *[]otlpFloat64) ([]byte, error) {
+	data, n := protowire.ConsumeBytes(buf)
+	if n < 0 {
+		return buf, protobufError(n)
+	}
+	if len(data)%8 != 0 {
+		return buf, fmt.Errorf("packed double is not multiple of 8")
+	}
+	for i := 0; i < len(data); i += 8 {
+		*result = append(*result, otlpFloat64(math.Float64frombits(binary.LittleEndian.Uint64(data[i:]))))
+	}
+	return buf[n:], nil
+}
+
+func protoReadPackedVarUint64(buf []byte, result *[]otlpUint64) ([]byte, error) {
+	data, n := protowire.ConsumeBytes(buf)
+	if n < 0 {
+		return buf, protobufError(n)
+	}
+	for len(data) != 0 {
+		v, m := protowire.ConsumeVarint(data)
+		if m < 0 {
+			return buf, protobufError(m)
+		}
+		*result = append(*result, otlpUint64(v))
+		data = data[m:]
+	}
+	return buf[n:], nil
+}
+
+// message ExportMetricsServiceRequest { repeated ResourceMetrics resource_metrics = 1; }
+func otlpUnmarshalRequest(buf []byte, r *otlpRequest) error {
+	r.ResourceMetrics = r.ResourceMetrics[:0]
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		if f == 1 && t == protowire.BytesType {
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.ResourceMetrics = append(r.ResourceMetrics, otlpResourceMetrics{})
+			if err = otlpUnmarshalResourceMetrics(data, &r.ResourceMetrics[len(r.ResourceMetrics)-1]); err != nil {
+				return err
+			}
+			continue
+		}
+		if buf, err = protoSkipField(buf, f, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// message ResourceMetrics { Resource resource = 1; repeated ScopeMetrics scope_metrics = 2; }
+// deprecated instrumentation_library_metrics = 1000 has the same layout as ScopeMetrics
+func otlpUnmarshalResourceMetrics(buf []byte, r *otlpResourceMetrics) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if r.Resource.Attributes, err = otlpUnmarshalAttributesOf(data, 1, r.Resource.Attributes); err != nil {
+				return err
+			}
+		case (f == 2 || f == 1000) && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.ScopeMetrics = append(r.ScopeMetrics, otlpScopeMetrics{})
+			if err = otlpUnmarshalScopeMetrics(data, &r.ScopeMetrics[len(r.ScopeMetrics)-1]); err != nil {
+				return err
+			}
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message ScopeMetrics { InstrumentationScope scope = 1; repeated Metric metrics = 2; }
+// message InstrumentationScope { string name = 1; string version = 2; repeated KeyValue attributes = 3; }
+func otlpUnmarshalScopeMetrics(buf []byte, r *otlpScopeMetrics) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if err = otlpUnmarshalScope(data, &r.Scope); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.Metrics = append(r.Metrics, otlpMetric{})
+			if err = otlpUnmarshalMetric(data, &r.Metrics[len(r.Metrics)-1]); err != nil {
+				return err
+			}
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func otlpUnmarshalScope(buf []byte, r *otlpScope) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.Name = string(data)
+		case f == 2 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.Version = string(data)
+		case f == 3 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if r.Attributes, err = otlpAppendKeyValue(data, r.Attributes); err != nil {
+				return err
+			}
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reads all "repeated KeyValue" entries with field number attrField from message
+func otlpUnmarshalAttributesOf(buf []byte, attrField protowire.Number, attrs []otlpKeyValue) ([]otlpKeyValue, error) {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return attrs, err
+		}
+		if f == attrField && t == protowire.BytesType {
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return attrs, err
+			}
+			if attrs, err = otlpAppendKeyValue(data, attrs); err != nil {
+				return attrs, err
+			}
+			continue
+		}
+		if buf, err = protoSkipField(buf, f, t); err != nil {
+			return attrs, err
+		}
+	}
+	return attrs, nil
+}
+
+// message KeyValue { string key = 1; AnyValue value = 2; }
+// message AnyValue { oneof value { string string_value = 1; bool bool_value = 2; int64 int_value = 3; double double_value = 4; ... } }
+func otlpAppendKeyValue(buf []byte, attrs []otlpKeyValue) ([]otlpKeyValue, error) {
+	var kv otlpKeyValue
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return attrs, err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return attrs, err
+			}
+			kv.Key = string(data)
+		case f == 2 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return attrs, err
+			}
+			if err = otlpUnmarshalAnyValue(data, &kv.Value); err != nil {
+				return attrs, err
+			}
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return attrs, err
+			}
+		}
+	}
+	return append(attrs, kv), nil
+}
+
+func otlpUnmarshalAnyValue(buf []byte, r *otlpAnyValue) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			s := string(data)
+			r.StringValue = &s
+		case f == 2 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			b := u != 0
+			r.BoolValue = &b
+		case f == 3 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			i := otlpInt64(u)
+			r.IntValue = &i
+		case f == 4 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			d := otlpFloat64(math.Float64frombits(u))
+			r.DoubleValue = &d
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message Metric { string name = 1; string description = 2; string unit = 3;
+// oneof data { Gauge gauge = 5; Sum sum = 7; Histogram histogram = 9; ExponentialHistogram exponential_histogram = 10; Summary summary = 11; } }
+func otlpUnmarshalMetric(buf []byte, r *otlpMetric) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		if t != protowire.BytesType {
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+			continue
+		}
+		if data, buf, err = protoReadMessage(buf); err != nil {
+			return err
+		}
+		switch f {
+		case 1:
+			r.Name = string(data)
+		case 2:
+			r.Description = string(data)
+		case 3:
+			r.Unit = string(data)
+		case 5:
+			r.Gauge = &otlpGauge{}
+			var unused otlpTemporality
+			if err = otlpUnmarshalNumberDataPoints(data, &r.Gauge.DataPoints, &unused, nil); err != nil {
+				return err
+			}
+		case 7:
+			r.Sum = &otlpSum{}
+			if err = otlpUnmarshalNumberDataPoints(data, &r.Sum.DataPoints, &r.Sum.AggregationTemporality, &r.Sum.IsMonotonic); err != nil {
+				return err
+			}
+		case 9:
+			r.Histogram = &otlpHistogram{}
+			if err = otlpUnmarshalHistogram(data, r.Histogram); err != nil {
+				return err
+			}
+		case 10:
+			r.ExponentialHistogram = &otlpExponentialHistogram{}
+			if err = otlpUnmarshalExponentialHistogram(data, r.ExponentialHistogram); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message Gauge { repeated NumberDataPoint data_points = 1; }
+// message Sum { repeated NumberDataPoint data_points = 1; AggregationTemporality aggregation_temporality = 2; bool is_monotonic = 3; }
+func otlpUnmarshalNumberDataPoints(buf []byte, points *[]otlpNumberDataPoint, temporality *otlpTemporality, isMonotonic *bool) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			*points = append(*points, otlpNumberDataPoint{})
+			if err = otlpUnmarshalNumberDataPoint(data, &(*points)[len(*points)-1]); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			*temporality = otlpTemporality(u)
+		case f == 3 && t == protowire.VarintType && isMonotonic != nil:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			*isMonotonic = u != 0
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message NumberDataPoint { repeated KeyValue attributes = 7; fixed64 start_time_unix_nano = 2; fixed64 time_unix_nano = 3;
+// oneof value { double as_double = 4; sfixed64 as_int = 6; } }
+func otlpUnmarshalNumberDataPoint(buf []byte, r *otlpNumberDataPoint) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 7 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if r.Attributes, err = otlpAppendKeyValue(data, r.Attributes); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.StartTimeUnixNano = otlpUint64(u)
+		case f == 3 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.TimeUnixNano = otlpUint64(u)
+		case f == 4 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			v := otlpFloat64(math.Float64frombits(u))
+			r.AsDouble = &v
+		case f == 6 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			v := otlpInt64(u)
+			r.AsInt = &v
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message Histogram { repeated HistogramDataPoint data_points = 1; AggregationTemporality aggregation_temporality = 2; }
+func otlpUnmarshalHistogram(buf []byte, r *otlpHistogram) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.DataPoints = append(r.DataPoints, otlpHistogramDataPoint{})
+			if err = otlpUnmarshalHistogramDataPoint(data, &r.DataPoints[len(r.DataPoints)-1]); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			r.AggregationTemporality = otlpTemporality(u)
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message HistogramDataPoint { repeated KeyValue attributes = 9; fixed64 start_time_unix_nano = 2; fixed64 time_unix_nano = 3;
+// fixed64 count = 4; optional double sum = 5; repeated fixed64 bucket_counts = 6; repeated double explicit_bounds = 7; }
+func otlpUnmarshalHistogramDataPoint(buf []byte, r *otlpHistogramDataPoint) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 9 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if r.Attributes, err = otlpAppendKeyValue(data, r.Attributes); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.StartTimeUnixNano = otlpUint64(u)
+		case f == 3 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.TimeUnixNano = otlpUint64(u)
+		case f == 4 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.Count = otlpUint64(u)
+		case f == 5 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.Sum = otlpFloat64(math.Float64frombits(u))
+		case f == 6 && t == protowire.BytesType:
+			if buf, err = protoReadPackedFixed64(buf, &r.BucketCounts); err != nil {
+				return err
+			}
+		case f == 6 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.BucketCounts = append(r.BucketCounts, otlpUint64(u))
+		case f == 7 && t == protowire.BytesType:
+			if buf, err = protoReadPackedDouble(buf, &r.ExplicitBounds); err != nil {
+				return err
+			}
+		case f == 7 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.ExplicitBounds = append(r.ExplicitBounds, otlpFloat64(math.Float64frombits(u)))
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message ExponentialHistogram { repeated ExponentialHistogramDataPoint data_points = 1; AggregationTemporality aggregation_temporality = 2; }
+func otlpUnmarshalExponentialHistogram(buf []byte, r *otlpExponentialHistogram) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			r.DataPoints = append(r.DataPoints, otlpExponentialHistogramDataPoint{})
+			if err = otlpUnmarshalExponentialHistogramDataPoint(data, &r.DataPoints[len(r.DataPoints)-1]); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			r.AggregationTemporality = otlpTemporality(u)
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message ExponentialHistogramDataPoint { repeated KeyValue attributes = 1; fixed64 start_time_unix_nano = 2; fixed64 time_unix_nano = 3;
+// fixed64 count = 4; optional double sum = 5; sint32 scale = 6; fixed64 zero_count = 7; Buckets positive = 8; Buckets negative = 9; }
+func otlpUnmarshalExponentialHistogramDataPoint(buf []byte, r *otlpExponentialHistogramDataPoint) error {
+	var f protowire.Number
+	var t protowire.Type
+	var data []byte
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			if r.Attributes, err = otlpAppendKeyValue(data, r.Attributes); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.StartTimeUnixNano = otlpUint64(u)
+		case f == 3 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.TimeUnixNano = otlpUint64(u)
+		case f == 4 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.Count = otlpUint64(u)
+		case f == 5 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.Sum = otlpFloat64(math.Float64frombits(u))
+		case f == 6 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			r.Scale = int32(protowire.DecodeZigZag(u))
+		case f == 7 && t == protowire.Fixed64Type:
+			if buf, err = protoReadFixed64(buf, &u); err != nil {
+				return err
+			}
+			r.ZeroCount = otlpUint64(u)
+		case (f == 8 || f == 9) && t == protowire.BytesType:
+			if data, buf, err = protoReadMessage(buf); err != nil {
+				return err
+			}
+			b := &r.Positive
+			if f == 9 {
+				b = &r.Negative
+			}
+			if err = otlpUnmarshalBuckets(data, b); err != nil {
+				return err
+			}
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// message Buckets { sint32 offset = 1; repeated uint64 bucket_counts = 2; }
+func otlpUnmarshalBuckets(buf []byte, r *otlpBuckets) error {
+	var f protowire.Number
+	var t protowire.Type
+	var u uint64
+	var err error
+	for len(buf) != 0 {
+		if f, t, buf, err = protoReadTag(buf); err != nil {
+			return err
+		}
+		switch {
+		case f == 1 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			r.Offset = int32(protowire.DecodeZigZag(u))
+		case f == 2 && t == protowire.BytesType:
+			if buf, err = protoReadPackedVarUint64(buf, &r.BucketCounts); err != nil {
+				return err
+			}
+		case f == 2 && t == protowire.VarintType:
+			if buf, err = protoReadVarint(buf, &u); err != nil {
+				return err
+			}
+			r.BucketCounts = append(r.BucketCounts, otlpUint64(u))
+		default:
+			if buf, err = protoSkipField(buf, f, t); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/vkcom/statshouse-go"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+type otlpCollected struct {
+	name    string
+	tags    map[string]string
+	counter float64
+	value   []float64
+	ts      uint32
+}
+
+func otlpCollector(res *[]otlpCollected) CallbackHandler {
+	return CallbackHandler{
+		Metrics: func(m *tlstatshouse.MetricBytes, cb data_model.MapCallbackFunc) (h data_model.MappedMetricHeader, done bool) {
+			c := otlpCollected{name: string(m.Name), tags: map[string]string{}, counter: m.Counter, ts: m.Ts}
+			c.value = append(c.value, m.Value...)
+			for _, t := range m.Tags {
+				c.tags[string(t.Key)] = string(t.Value)
+			}
+			*res = append(*res, c)
+			return h, true
+		},
+	}
+}
+
+func otlpAppendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func otlpAppendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func otlpAppendFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+func otlpAppendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func otlpKV(key string, value string) []byte {
+	return otlpAppendMessage(otlpAppendString(nil, 1, key), 2, otlpAppendString(nil, 1, value))
+}
+
+func otlpRequestBytes(metrics ...[]byte) []byte {
+	resource := otlpAppendMessage(nil, 1, otlpKV("service.name", "billing"))
+	var scope []byte
+	scope = otlpAppendMessage(scope, 1, otlpAppendMessage(otlpAppendString(nil, 1, "lib"), 3, otlpKV("scope.attr", "s")))
+	for _, m := range metrics {
+		scope = otlpAppendMessage(scope, 2, m)
+	}
+	rm := otlpAppendMessage(otlpAppendMessage(nil, 1, resource), 2, scope)
+	return otlpAppendMessage(nil, 1, rm)
+}
+
+func otlpSumMetric(name string, temporality uint64, start uint64, value float64) []byte {
+	var dp []byte
+	dp = otlpAppendMessage(dp, 7, otlpKV("method", "GET"))
+	dp = otlpAppendFixed64(dp, 2, start)
+	dp = otlpAppendFixed64(dp, 3, 1700000000*1e9)
+	dp = otlpAppendFixed64(dp, 4, math.Float64bits(value))
+	var sum []byte
+	sum = otlpAppendMessage(sum, 1, dp)
+	sum = otlpAppendVarint(sum, 2, temporality)
+	sum = otlpAppendVarint(sum, 3, 1)
+	return otlpAppendMessage(otlpAppendString(nil, 1, name), 7, sum)
+}
+
+func TestOTLPGaugeAttributes(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	var dp []byte
+	dp = otlpAppendMessage(dp, 7, otlpKV("host.name", "h1"))
+	dp = otlpAppendFixed64(dp, 3, 1700000000*1e9)
+	dp = otlpAppendFixed64(dp, 6, uint64(42)) // as_int
+	gauge := otlpAppendMessage(otlpAppendString(nil, 1, "process.memory.usage"), 5, otlpAppendMessage(nil, 1, dp))
+	require.NoError(t, r.handleRequest(otlpRequestBytes(gauge), false))
+	require.Len(t, res, 1)
+	require.Equal(t, "process_memory_usage", res[0].name)
+	require.Equal(t, map[string]string{"service_name": "billing", "scope_attr": "s", "host_name": "h1"}, res[0].tags)
+	require.Equal(t, []float64{42}, res[0].value)
+	require.Equal(t, uint32(1700000000), res[0].ts)
+}
+
+func TestOTLPCumulativeSum(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpSumMetric("requests", otlpTemporalityCumulative, 1, 10)), false))
+	require.Len(t, res, 0) // first point of cumulative series only initializes state
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpSumMetric("requests", otlpTemporalityCumulative, 1, 25)), false))
+	require.Len(t, res, 1)
+	require.Equal(t, 15.0, res[0].counter)
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpSumMetric("requests", otlpTemporalityCumulative, 2, 3)), false))
+	require.Len(t, res, 2)
+	require.Equal(t, 3.0, res[1].counter) // start time changed, counter was reset
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpSumMetric("requests", otlpTemporalityDelta, 0, 7)), false))
+	require.Len(t, res, 3)
+	require.Equal(t, 7.0, res[2].counter)
+}
+
+func TestOTLPHistogram(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	var counts, bounds []byte
+	for _, c := range []uint64{1, 0, 2} {
+		counts = protowire.AppendFixed64(counts, c)
+	}
+	for _, b := range []float64{0.1, 1} {
+		bounds = protowire.AppendFixed64(bounds, math.Float64bits(b))
+	}
+	var dp []byte
+	dp = otlpAppendFixed64(dp, 4, 3)
+	dp = otlpAppendFixed64(dp, 5, math.Float64bits(7.5))
+	dp = otlpAppendMessage(dp, 6, counts)
+	dp = otlpAppendMessage(dp, 7, bounds)
+	h := otlpAppendVarint(otlpAppendMessage(nil, 1, dp), 2, otlpTemporalityDelta)
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpAppendMessage(otlpAppendString(nil, 1, "latency"), 9, h)), false))
+	require.Len(t, res, 3)
+	require.Equal(t, "latency_bucket", res[0].name)
+	require.Equal(t, strconv.Itoa(int(statshouse.LexEncode(0.1))), res[0].tags[format.LETagName])
+	require.Equal(t, 1.0, res[0].counter)
+	require.Equal(t, strconv.Itoa(int(statshouse.LexEncode(float32(math.Inf(1))))), res[1].tags[format.LETagName])
+	require.Equal(t, 2.0, res[1].counter)
+	require.Equal(t, "latency_sum", res[2].name)
+	require.Equal(t, 3.0, res[2].counter)
+	require.Equal(t, []float64{7.5}, res[2].value)
+}
+
+func TestOTLPExponentialHistogram(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	var positive []byte
+	positive = otlpAppendVarint(positive, 1, protowire.EncodeZigZag(0))
+	positive = otlpAppendMessage(positive, 2, protowire.AppendVarint(protowire.AppendVarint(nil, 4), 0))
+	var dp []byte
+	dp = otlpAppendFixed64(dp, 4, 5)
+	dp = otlpAppendVarint(dp, 6, protowire.EncodeZigZag(0)) // scale 0, base 2
+	dp = otlpAppendFixed64(dp, 7, 1)
+	dp = otlpAppendMessage(dp, 8, positive)
+	h := otlpAppendVarint(otlpAppendMessage(nil, 1, dp), 2, otlpTemporalityDelta)
+	require.NoError(t, r.handleRequest(otlpRequestBytes(otlpAppendMessage(otlpAppendString(nil, 1, "size"), 10, h)), false))
+	require.Len(t, res, 2)
+	require.Equal(t, []float64{0}, res[0].value)
+	require.Equal(t, 1.0, res[0].counter)
+	require.Equal(t, []float64{1.5}, res[1].value) // bucket (1, 2]
+	require.Equal(t, 4.0, res[1].counter)
+}
+
+func TestOTLPJSON(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	body := `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"api"}}]},
+"scopeMetrics":[{"scope":{"name":"x"},"metrics":[{"name":"jobs","sum":{"aggregationTemporality":"AGGREGATION_TEMPORALITY_DELTA","isMonotonic":true,
+"dataPoints":[{"attributes":[{"key":"ok","value":{"boolValue":true}}],"timeUnixNano":"1700000000000000000","asInt":"5"}]}}]}]}]}`
+	req := httptest.NewRequest(http.MethodPost, OTLPHTTPPath, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, res, 1)
+	require.Equal(t, "jobs", res[0].name)
+	require.Equal(t, 5.0, res[0].counter)
+	require.Equal(t, map[string]string{"service_name": "api", "ok": "true"}, res[0].tags)
+}
+
+func TestOTLPGRPC(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	msg := otlpRequestBytes(otlpSumMetric("requests", otlpTemporalityDelta, 0, 2))
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	frame = append(frame, msg...)
+	req := httptest.NewRequest(http.MethodPost, OTLPGRPCPath, bytes.NewReader(frame))
+	req.Header.Set("Content-Type", "application/grpc")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "0", w.Result().Trailer.Get("Grpc-Status"))
+	require.Len(t, res, 1)
+
+	req = httptest.NewRequest(http.MethodPost, OTLPGRPCPath, bytes.NewReader([]byte{0, 0, 0, 0, 9, 1}))
+	req.Header.Set("Content-Type", "application/grpc")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, strconv.Itoa(grpcStatusInvalidArgument), w.Result().Trailer.Get("Grpc-Status"))
+	require.Equal(t, uint64(1), r.StatRequestsTotalErr.Load(), "error must be counted once")
+}
+
+func TestOTLPJSONProtoNames(t *testing.T) {
+	var res []otlpCollected
+	r := MakeOTLP(nil, otlpCollector(&res))
+	body := `{"resource_metrics":[{"resource":{"attributes":[{"key":"service_name","value":{"string_value":"api"}}]},
+"scope_metrics":[{"metrics":[{"name":"jobs","sum":{"aggregation_temporality":1,"is_monotonic":true,
+"data_points":[{"time_unix_nano":"1700000000000000000","as_double":2.5}]}}]}]}]}`
+	require.NoError(t, r.handleRequest([]byte(body), true))
+	require.Len(t, res, 1)
+	require.Equal(t, 2.5, res[0].counter)
+	require.Equal(t, map[string]string{"service_name": "api"}, res[0].tags)
+	require.Equal(t, uint64(0), r.StatRequestsTotalErr.Load())
+
+	require.Error(t, r.handleRequest([]byte(`{"resource_metrics":[}`), true))
+	require.Equal(t, uint64(1), r.StatRequestsTotalErr.Load())
+}
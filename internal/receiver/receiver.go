@@ -12,6 +12,7 @@ import (
 	"errors"
 	"math"
 	"net"
+	"strings"
 	"syscall"
 
 	"go.uber.org/atomic"
@@ -78,6 +79,8 @@ type UDP struct {
 	batchSizeJSONErr     *agent.BuiltInItemValue
 	batchSizeProtobufOK  *agent.BuiltInItemValue
 	batchSizeProtobufErr *agent.BuiltInItemValue
+	batchSizeStatsDOK    *agent.BuiltInItemValue
+	batchSizeStatsDErr   *agent.BuiltInItemValue
 
 	packetSizeTLOK        *agent.BuiltInItemValue
 	packetSizeTLErr       *agent.BuiltInItemValue
@@ -87,6 +90,8 @@ type UDP struct {
 	packetSizeJSONErr     *agent.BuiltInItemValue
 	packetSizeProtobufOK  *agent.BuiltInItemValue
 	packetSizeProtobufErr *agent.BuiltInItemValue
+	packetSizeStatsDOK    *agent.BuiltInItemValue
+	packetSizeStatsDErr   *agent.BuiltInItemValue
 	packetSizeLegacyErr   *agent.BuiltInItemValue
 	packetSizeEmptyErr    *agent.BuiltInItemValue
 }
@@ -136,6 +141,8 @@ func ListenUDP(network string, address string, bufferSize int, reusePort bool, b
 		batchSizeJSONErr:      createBatchSizeValue(bm, format.TagValueIDPacketFormatJSON, format.TagValueIDAgentReceiveStatusError),
 		batchSizeProtobufOK:   createBatchSizeValue(bm, format.TagValueIDPacketFormatProtobuf, format.TagValueIDAgentReceiveStatusOK),
 		batchSizeProtobufErr:  createBatchSizeValue(bm, format.TagValueIDPacketFormatProtobuf, format.TagValueIDAgentReceiveStatusError),
+		batchSizeStatsDOK:     createBatchSizeValue(bm, format.TagValueIDPacketFormatStatsD, format.TagValueIDAgentReceiveStatusOK),
+		batchSizeStatsDErr:    createBatchSizeValue(bm, format.TagValueIDPacketFormatStatsD, format.TagValueIDAgentReceiveStatusError),
 		packetSizeTLOK:        createPacketSizeValue(bm, format.TagValueIDPacketFormatTL, format.TagValueIDAgentReceiveStatusOK),
 		packetSizeTLErr:       createPacketSizeValue(bm, format.TagValueIDPacketFormatTL, format.TagValueIDAgentReceiveStatusError),
 		packetSizeMsgPackOK:   createPacketSizeValue(bm, format.TagValueIDPacketFormatMsgPack, format.TagValueIDAgentReceiveStatusOK),
@@ -144,6 +151,8 @@ func ListenUDP(network string, address string, bufferSize int, reusePort bool, b
 		packetSizeJSONErr:     createPacketSizeValue(bm, format.TagValueIDPacketFormatJSON, format.TagValueIDAgentReceiveStatusError),
 		packetSizeProtobufOK:  createPacketSizeValue(bm, format.TagValueIDPacketFormatProtobuf, format.TagValueIDAgentReceiveStatusOK),
 		packetSizeProtobufErr: createPacketSizeValue(bm, format.TagValueIDPacketFormatProtobuf, format.TagValueIDAgentReceiveStatusError),
+		packetSizeStatsDOK:    createPacketSizeValue(bm, format.TagValueIDPacketFormatStatsD, format.TagValueIDAgentReceiveStatusOK),
+		packetSizeStatsDErr:   createPacketSizeValue(bm, format.TagValueIDPacketFormatStatsD, format.TagValueIDAgentReceiveStatusError),
 		packetSizeLegacyErr:   createPacketSizeValue(bm, format.TagValueIDPacketFormatLegacy, format.TagValueIDAgentReceiveStatusError),
 		packetSizeEmptyErr:    createPacketSizeValue(bm, format.TagValueIDPacketFormatEmpty, format.TagValueIDAgentReceiveStatusError),
 	}, nil
@@ -202,6 +211,24 @@ outer:
 				continue outer
 			}
 			setValueSize(u.packetSizeJSONOK, pktLen)
+		case statsdLooksLikeLine(pkt): // before legacy, because StatsD metric name can start with "SH"
+			errLen := 0 // malformed lines are dropped one by one, the rest of packet is processed
+			errs := statsdUnmarshalBatch(&batch, pkt, func(line []byte, err error) {
+				errLen += len(line)
+				setValueSize(u.batchSizeStatsDErr, len(line))
+				h.HandleParseError(line, err)
+			})
+			u.handleMetrics(h, batch)
+			if len(batch.Metrics) != 0 {
+				setValueSize(u.batchSizeStatsDOK, pktLen-errLen)
+			}
+			if errs != 0 { // packet is counted once, as error if any line failed
+				u.statBatchesTotalErr.Inc()
+				setValueSize(u.packetSizeStatsDErr, pktLen)
+				continue outer
+			}
+			u.statBatchesTotalOK.Inc()
+			setValueSize(u.packetSizeStatsDOK, pktLen)
 		case bytes.HasPrefix(pkt, legacyPacketPrefix):
 			setValueSize(u.packetSizeLegacyErr, pktLen)
 		case msgpackLooksLikeMap(pkt):
@@ -245,12 +272,14 @@ func (u *UDP) handleMetricsBatch(h Handler, b tlstatshouse.AddMetricsBatchBytes,
 		return false
 	}
 	u.statBatchesTotalOK.Inc()
+	u.handleMetrics(h, b)
+	return true
+}
 
+func (u *UDP) handleMetrics(h Handler, b tlstatshouse.AddMetricsBatchBytes) {
 	for i := range b.Metrics {
 		_, _ = h.HandleMetrics(data_model.HandlerArgs{MetricBytes: &b.Metrics[i]}) // might move out metric, if needs to
 	}
-
-	return true
 }
 
 func createBatchSizeValue(ag *agent.Agent, formatTagValueID int32, statusTagValueID int32) *agent.BuiltInItemValue {
@@ -291,3 +320,21 @@ func (u *UDP) ReceiveBufferSize() int {
 	})
 	return res
 }
+
+// OpenTelemetry and StatsD names are dot-separated, while metric and tag names allow only letters, digits and underscores
+func sanitizeName(s string, allowNamespace bool) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_':
+			sb.WriteByte(c)
+		case c == format.NamespaceSeparatorRune && allowNamespace:
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
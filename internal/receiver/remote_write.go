@@ -0,0 +1,510 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"go.uber.org/atomic"
+
+	"github.com/vkcom/statshouse/internal/agent"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+const (
+	PromRemoteWritePath = "/api/v1/write"
+
+	remoteWriteMaxRequestSize = 64 << 20
+	remoteWriteSeriesTTL      = time.Hour // state of series not seen for this long is forgotten
+	remoteWriteMaxPending     = 8         // incomplete histogram snapshots kept per series
+)
+
+// PromRemoteWrite accepts snappy-compressed prometheus.WriteRequest pushed by Prometheus or vmagent.
+//
+// Counters and histograms are converted to deltas the same way as scraped ones, so the first sample
+// of each series only initializes state. Metric types are taken from metadata sent by Prometheus,
+// until metadata arrives types are guessed by "_total", "_bucket", "_sum" and "_count" suffixes.
+// "__scrape_namespace__" label sets metric namespace, like it does for scrape targets.
+//
+// Prometheus shards series between concurrent requests, so buckets of a single histogram can arrive
+// in different requests. Buckets are collected by sample timestamp and written when complete.
+type PromRemoteWrite struct {
+	handler Handler
+
+	mu          sync.Mutex // protects all state, requests are processed one by one
+	hash        hash.Hash64
+	metadata    map[string]remoteWriteMetadata // metric family name -> metadata
+	families    map[string]time.Time           // histogram families seen with "_bucket" series, for requests without metadata
+	counters    map[uint64]*remoteWriteCounter
+	histograms  map[uint64]*remoteWriteHistogram
+	lastCleanup time.Time
+	metric      tlstatshouse.MetricBytes
+
+	batchSizeOK   *agent.BuiltInItemValue
+	batchSizeErr  *agent.BuiltInItemValue
+	packetSizeOK  *agent.BuiltInItemValue
+	packetSizeErr *agent.BuiltInItemValue
+
+	StatRequestsTotalOK  atomic.Uint64
+	StatRequestsTotalErr atomic.Uint64
+}
+
+type remoteWriteMetadata struct {
+	prompb.MetricMetadata
+	seen time.Time // Prometheus resends metadata periodically, so metadata of removed families is forgotten
+}
+
+type remoteWriteCounter struct {
+	scrapeCounter
+	seen time.Time
+}
+
+type remoteWriteHistogram struct {
+	scrapeHistogram
+	bounds  []float64 // sorted "le" values
+	les     []string  // "le" label values in bounds order
+	prev    *scrapeHistogramSeries
+	prevTs  int64
+	pending map[int64]*remoteWriteSnapshot // sample timestamp -> snapshot being collected
+	seen    time.Time
+}
+
+type remoteWriteSnapshot struct {
+	scrapeHistogramSeries // NaN in place of buckets not received yet
+	received              int
+	hasSum                bool
+	hasCount              bool
+}
+
+// what kind of series, with metric family name for histogram parts
+type remoteWriteKind int
+
+const (
+	remoteWriteGauge remoteWriteKind = iota
+	remoteWriteCounterKind
+	remoteWriteBucket
+	remoteWriteSum
+	remoteWriteCount
+)
+
+func MakePromRemoteWrite(ag *agent.Agent, h Handler) *PromRemoteWrite {
+	return &PromRemoteWrite{
+		handler:       h,
+		hash:          fnv.New64(),
+		metadata:      map[string]remoteWriteMetadata{},
+		families:      map[string]time.Time{},
+		counters:      map[uint64]*remoteWriteCounter{},
+		histograms:    map[uint64]*remoteWriteHistogram{},
+		lastCleanup:   time.Now(),
+		batchSizeOK:   createBatchSizeValue(ag, format.TagValueIDPacketFormatPromRemoteWrite, format.TagValueIDAgentReceiveStatusOK),
+		batchSizeErr:  createBatchSizeValue(ag, format.TagValueIDPacketFormatPromRemoteWrite, format.TagValueIDAgentReceiveStatusError),
+		packetSizeOK:  createPacketSizeValue(ag, format.TagValueIDPacketFormatPromRemoteWrite, format.TagValueIDAgentReceiveStatusOK),
+		packetSizeErr: createPacketSizeValue(ag, format.TagValueIDPacketFormatPromRemoteWrite, format.TagValueIDAgentReceiveStatusError),
+	}
+}
+
+// Serve blocks until listener is closed
+func (r *PromRemoteWrite) Serve(ln net.Listener) error {
+	server := http.Server{Handler: r}
+	err := server.Serve(ln)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+func (r *PromRemoteWrite) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "only POST method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.URL.Path != PromRemoteWritePath {
+		http.Error(w, "unknown path, remote write is accepted at "+PromRemoteWritePath, http.StatusNotFound)
+		return
+	}
+	if enc := req.Header.Get("Content-Encoding"); enc != "" && enc != "snappy" {
+		http.Error(w, fmt.Sprintf("unsupported encoding %q", enc), http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, remoteWriteMaxRequestSize))
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = r.handleRequest(body, time.Now()); err != nil {
+		// 4xx responses are not retried by Prometheus, which is what we want for malformed data
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *PromRemoteWrite) handleRequest(body []byte, now time.Time) error {
+	var req prompb.WriteRequest
+	pkt, err := snappy.Decode(nil, body)
+	if err == nil {
+		err = req.Unmarshal(pkt)
+	}
+	if err != nil {
+		r.StatRequestsTotalErr.Inc()
+		r.handler.HandleParseError(body, err)
+		setValueSize(r.batchSizeErr, len(body))
+		setValueSize(r.packetSizeErr, len(body))
+		return err
+	}
+	r.StatRequestsTotalOK.Inc()
+	setValueSize(r.batchSizeOK, len(pkt))
+	setValueSize(r.packetSizeOK, len(body))
+	r.handleWriteRequest(&req, now)
+	return nil
+}
+
+func (r *PromRemoteWrite) handleWriteRequest(req *prompb.WriteRequest, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, md := range req.Metadata {
+		r.metadata[md.MetricFamilyName] = remoteWriteMetadata{MetricMetadata: md, seen: now}
+	}
+	// histogram "_sum" and "_count" without metadata can be told from counters only if "_bucket" was seen
+	for i := range req.Timeseries {
+		name, le := remoteWriteNameLE(req.Timeseries[i].Labels)
+		if family := strings.TrimSuffix(name, "_bucket"); le != "" && family != name {
+			r.families[family] = now
+		}
+	}
+	touched := map[*remoteWriteHistogram]bool{}
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		name, le := remoteWriteNameLE(ts.Labels)
+		if name == "" || len(ts.Samples) == 0 {
+			continue
+		}
+		namespace := ""
+		for _, l := range ts.Labels {
+			if l.Name == format.ScrapeNamespaceTagName {
+				namespace = l.Value
+			}
+		}
+		kind, family := r.seriesKind(name, le)
+		description := r.metadata[family].Help
+		switch kind {
+		case remoteWriteGauge:
+			for _, s := range ts.Samples {
+				b := &r.metric
+				b.Reset()
+				setMetricName(b, namespace, name)
+				remoteWriteAppendTags(b, ts.Labels)
+				setMetricValue(b, s.Value)
+				remoteWriteSetTs(b, s.Timestamp)
+				r.emit(b, description)
+			}
+		case remoteWriteCounterKind:
+			hashSum := r.seriesHash(namespace, name, ts.Labels, false)
+			c := r.counters[hashSum]
+			samples := ts.Samples
+			if c == nil {
+				c = &remoteWriteCounter{scrapeCounter: scrapeCounter{
+					name:        metricFullName(namespace, name),
+					description: description,
+					tags:        remoteWriteTags(ts.Labels, false),
+					value:       samples[0].Value,
+				}}
+				r.counters[hashSum] = c
+				samples = samples[1:]
+			}
+			c.seen = now
+			for _, s := range samples {
+				if v := c.delta(s.Value); v > 0 {
+					b := &r.metric
+					b.Reset()
+					c.setMetric(b, v)
+					remoteWriteSetTs(b, s.Timestamp)
+					r.emit(b, c.description)
+				}
+			}
+		default:
+			hashSum := r.seriesHash(namespace, family, ts.Labels, true)
+			h := r.histograms[hashSum]
+			if h == nil {
+				h = &remoteWriteHistogram{
+					scrapeHistogram: scrapeHistogram{
+						nameB:        metricFullName(namespace, family+"_bucket"),
+						nameS:        metricFullName(namespace, family+"_sum"),
+						tags:         remoteWriteTags(ts.Labels, true),
+						descriptionS: description,
+					},
+					pending: map[int64]*remoteWriteSnapshot{},
+				}
+				r.histograms[hashSum] = h
+			}
+			h.seen = now
+			touched[h] = true
+			for _, s := range ts.Samples {
+				h.set(kind, le, s)
+			}
+		}
+	}
+	for h := range touched {
+		h.flush(r)
+	}
+	if now.Sub(r.lastCleanup) > remoteWriteSeriesTTL {
+		for k, c := range r.counters {
+			if now.Sub(c.seen) > remoteWriteSeriesTTL {
+				delete(r.counters, k)
+			}
+		}
+		for k, h := range r.histograms {
+			if now.Sub(h.seen) > remoteWriteSeriesTTL {
+				delete(r.histograms, k)
+			}
+		}
+		for k, md := range r.metadata {
+			if now.Sub(md.seen) > remoteWriteSeriesTTL {
+				delete(r.metadata, k)
+			}
+		}
+		for k, seen := range r.families {
+			if now.Sub(seen) > remoteWriteSeriesTTL {
+				delete(r.families, k)
+			}
+		}
+		r.lastCleanup = now
+	}
+}
+
+func (r *PromRemoteWrite) seriesKind(name string, le string) (remoteWriteKind, string) {
+	if md, ok := r.metadata[name]; ok {
+		if md.Type == prompb.MetricMetadata_COUNTER {
+			return remoteWriteCounterKind, name
+		}
+		return remoteWriteGauge, name
+	}
+	for _, suffix := range [...]string{"_bucket", "_sum", "_count", "_total"} {
+		family := strings.TrimSuffix(name, suffix)
+		if family == name {
+			continue
+		}
+		md, ok := r.metadata[family]
+		_, bucketSeen := r.families[family]
+		histogram := md.Type == prompb.MetricMetadata_HISTOGRAM || (!ok && bucketSeen)
+		switch {
+		case suffix == "_bucket" && histogram && le != "":
+			return remoteWriteBucket, family
+		case suffix == "_sum" && histogram:
+			return remoteWriteSum, family
+		case suffix == "_count" && histogram:
+			return remoteWriteCount, family
+		case !ok && suffix != "_bucket", md.Type == prompb.MetricMetadata_COUNTER, md.Type == prompb.MetricMetadata_SUMMARY:
+			return remoteWriteCounterKind, family
+		}
+		return remoteWriteGauge, family
+	}
+	return remoteWriteGauge, name
+}
+
+func (r *PromRemoteWrite) seriesHash(namespace string, name string, l []prompb.Label, skipLE bool) uint64 {
+	r.hash.Write([]byte(namespace))
+	r.hash.Write([]byte{0})
+	r.hash.Write([]byte(name))
+	for _, v := range l {
+		if v.Name == labels.MetricName || (skipLE && v.Name == labels.BucketLabel) {
+			continue
+		}
+		r.hash.Write([]byte{0})
+		r.hash.Write([]byte(v.Name))
+		r.hash.Write([]byte{0})
+		r.hash.Write([]byte(v.Value))
+	}
+	hashSum := r.hash.Sum64()
+	r.hash.Reset()
+	return hashSum
+}
+
+func (r *PromRemoteWrite) emit(b *tlstatshouse.MetricBytes, description string) {
+	r.handler.HandleMetrics(data_model.HandlerArgs{
+		MetricBytes: b,
+		Description: description,
+	})
+}
+
+func (h *remoteWriteHistogram) set(kind remoteWriteKind, le string, s prompb.Sample) {
+	if kind == remoteWriteBucket {
+		bound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return
+		}
+		i := sort.SearchFloat64s(h.bounds, bound)
+		if i == len(h.bounds) || h.bounds[i] != bound {
+			// new bucket, last written snapshot is no longer comparable
+			h.bounds = insertAt(h.bounds, i, bound)
+			h.les = insertAt(h.les, i, le)
+			h.setBuckets(h.les)
+			h.prev = nil
+			for _, snap := range h.pending {
+				snap.bucket = insertAt(snap.bucket, i, math.NaN())
+			}
+		}
+		if snap := h.snapshot(s.Timestamp); snap != nil {
+			if math.IsNaN(snap.bucket[i]) {
+				snap.received++
+			}
+			snap.bucket[i] = s.Value
+		}
+		return
+	}
+	if snap := h.snapshot(s.Timestamp); snap != nil {
+		switch kind {
+		case remoteWriteSum:
+			snap.sum, snap.hasSum = s.Value, true
+		case remoteWriteCount:
+			snap.count, snap.hasCount = s.Value, true
+		}
+	}
+}
+
+func (h *remoteWriteHistogram) snapshot(ts int64) *remoteWriteSnapshot {
+	if h.prev != nil && ts <= h.prevTs {
+		return nil // late sample of already written snapshot
+	}
+	snap := h.pending[ts]
+	if snap == nil {
+		snap = &remoteWriteSnapshot{}
+		snap.bucket = make([]float64, len(h.bounds))
+		for i := range snap.bucket {
+			snap.bucket[i] = math.NaN()
+		}
+		h.pending[ts] = snap
+	}
+	return snap
+}
+
+// writes complete snapshots in timestamp order, first complete snapshot only initializes state
+func (h *remoteWriteHistogram) flush(r *PromRemoteWrite) {
+	timestamps := make([]int64, 0, len(h.pending))
+	for ts := range h.pending {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	for _, ts := range timestamps {
+		snap := h.pending[ts]
+		if snap == nil || len(h.bounds) == 0 || !math.IsInf(h.bounds[len(h.bounds)-1], 1) ||
+			snap.received != len(h.bounds) || !snap.hasSum || !snap.hasCount {
+			continue
+		}
+		curr := &snap.scrapeHistogramSeries
+		curr.decumulate()
+		if prev := h.prev; prev != nil && curr.count >= prev.count {
+			h.bucketDeltas(prev, curr, func(i int, v float64) {
+				b := &r.metric
+				b.Reset()
+				h.setBucketMetric(b, i, v)
+				remoteWriteSetTs(b, ts)
+				r.emit(b, h.descriptionB)
+			})
+			if count := curr.count - prev.count; count > 0 {
+				b := &r.metric
+				b.Reset()
+				b.Name = appendString(b.Name, h.nameS)
+				for _, v := range h.tags {
+					b.Tags = appendTag(b.Tags, v.Name, v.Value)
+				}
+				b.SetCounter(count)
+				setMetricValue(b, curr.sum-prev.sum)
+				remoteWriteSetTs(b, ts)
+				r.emit(b, h.descriptionS)
+			}
+		}
+		h.prev, h.prevTs = curr, ts
+		for t := range h.pending {
+			if t <= ts {
+				delete(h.pending, t)
+			}
+		}
+	}
+	for len(h.pending) > remoteWriteMaxPending {
+		oldest := int64(math.MaxInt64)
+		for t := range h.pending {
+			if t < oldest {
+				oldest = t
+			}
+		}
+		delete(h.pending, oldest)
+	}
+}
+
+func insertAt[T any](s []T, i int, v T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func remoteWriteNameLE(l []prompb.Label) (name string, le string) {
+	for _, v := range l {
+		switch v.Name {
+		case labels.MetricName:
+			name = v.Value
+		case labels.BucketLabel:
+			le = v.Value
+		}
+	}
+	return name, le
+}
+
+// service labels are dropped the same way as for scrape
+func remoteWriteTags(l []prompb.Label, skipLE bool) []labels.Label {
+	res := make([]labels.Label, 0, len(l))
+	for _, v := range l {
+		if strings.HasPrefix(v.Name, model.ReservedLabelPrefix) || (skipLE && v.Name == labels.BucketLabel) {
+			continue
+		}
+		res = append(res, labels.Label{Name: v.Name, Value: v.Value})
+	}
+	return res
+}
+
+func remoteWriteAppendTags(b *tlstatshouse.MetricBytes, l []prompb.Label) {
+	for _, v := range l {
+		if strings.HasPrefix(v.Name, model.ReservedLabelPrefix) {
+			continue
+		}
+		b.Tags = appendTag(b.Tags, v.Name, v.Value)
+	}
+}
+
+func remoteWriteSetTs(b *tlstatshouse.MetricBytes, timestampMs int64) {
+	if ts := timestampMs / 1000; ts > 0 && ts <= math.MaxUint32 {
+		b.SetTs(uint32(ts))
+	}
+}
+
+func metricFullName(namespace string, name string) string {
+	if namespace != "" {
+		return namespace + format.NamespaceSeparator + name
+	}
+	return name
+}
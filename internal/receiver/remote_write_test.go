@@ -0,0 +1,153 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse-go"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func remoteWriteSeries(name string, ts int64, v float64, kv ...string) prompb.TimeSeries {
+	l := []prompb.Label{{Name: "__name__", Value: name}}
+	for i := 0; i+1 < len(kv); i += 2 {
+		l = append(l, prompb.Label{Name: kv[i], Value: kv[i+1]})
+	}
+	return prompb.TimeSeries{Labels: l, Samples: []prompb.Sample{{Value: v, Timestamp: ts}}}
+}
+
+func remoteWriteBody(t *testing.T, req *prompb.WriteRequest) []byte {
+	pkt, err := req.Marshal()
+	require.NoError(t, err)
+	return snappy.Encode(nil, pkt)
+}
+
+func TestPromRemoteWriteGaugeCounter(t *testing.T) {
+	var res []otlpCollected
+	r := MakePromRemoteWrite(nil, otlpCollector(&res))
+	now := time.Now()
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+		remoteWriteSeries("temperature", 1700000000000, 21.5, "__scrape_namespace__", "home", "room", "kitchen"),
+		remoteWriteSeries("requests_total", 1700000000000, 10, "job", "api"),
+	}}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Len(t, res, 1) // first counter sample only initializes state
+	require.Equal(t, "home:temperature", res[0].name)
+	require.Equal(t, map[string]string{"room": "kitchen"}, res[0].tags)
+	require.Equal(t, []float64{21.5}, res[0].value)
+	require.Equal(t, uint32(1700000000), res[0].ts)
+
+	req.Timeseries = []prompb.TimeSeries{remoteWriteSeries("requests_total", 1700000015000, 25, "job", "api")}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Len(t, res, 2)
+	require.Equal(t, "requests_total", res[1].name)
+	require.Equal(t, 15.0, res[1].counter)
+	require.Equal(t, map[string]string{"job": "api"}, res[1].tags)
+
+	// with metadata, "_total" suffix is not needed to detect counter
+	req.Metadata = []prompb.MetricMetadata{{Type: prompb.MetricMetadata_COUNTER, MetricFamilyName: "jobs_done", Help: "done"}}
+	req.Timeseries = []prompb.TimeSeries{remoteWriteSeries("jobs_done", 1700000000000, 1)}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	req.Timeseries = []prompb.TimeSeries{remoteWriteSeries("jobs_done", 1700000015000, 4)}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Len(t, res, 3)
+	require.Equal(t, 3.0, res[2].counter)
+
+	require.Error(t, r.handleRequest([]byte("garbage"), now))
+}
+
+func TestPromRemoteWriteHistogram(t *testing.T) {
+	var res []otlpCollected
+	r := MakePromRemoteWrite(nil, otlpCollector(&res))
+	now := time.Now()
+	snapshot := func(ts int64, b1, bInf, sum float64) []prompb.TimeSeries {
+		return []prompb.TimeSeries{
+			remoteWriteSeries("latency_bucket", ts, b1, "le", "1", "method", "GET"),
+			remoteWriteSeries("latency_bucket", ts, bInf, "le", "+Inf", "method", "GET"),
+			remoteWriteSeries("latency_sum", ts, sum, "method", "GET"),
+			remoteWriteSeries("latency_count", ts, bInf, "method", "GET"),
+		}
+	}
+	first := snapshot(1700000000000, 1, 2, 3)
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, &prompb.WriteRequest{Timeseries: first}), now))
+	require.Len(t, res, 0)
+	// second snapshot is split between requests, as Prometheus shards series
+	second := snapshot(1700000015000, 3, 5, 10)
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, &prompb.WriteRequest{Timeseries: second[:1]}), now))
+	require.Len(t, res, 0)
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, &prompb.WriteRequest{Timeseries: second[1:]}), now))
+	require.Len(t, res, 3)
+	require.Equal(t, "latency_bucket", res[0].name)
+	require.Equal(t, strconv.Itoa(int(statshouse.LexEncode(1))), res[0].tags[format.LETagName])
+	require.Equal(t, "GET", res[0].tags["method"])
+	require.Equal(t, 2.0, res[0].counter)
+	require.Equal(t, 1.0, res[1].counter) // +Inf bucket: (5-3) - (2-1)
+	require.Equal(t, "latency_sum", res[2].name)
+	require.Equal(t, 3.0, res[2].counter)
+	require.Equal(t, []float64{7}, res[2].value)
+	require.Equal(t, uint32(1700000015), res[2].ts)
+}
+
+func TestPromRemoteWriteHTTP(t *testing.T) {
+	var res []otlpCollected
+	r := MakePromRemoteWrite(nil, otlpCollector(&res))
+	body := remoteWriteBody(t, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{remoteWriteSeries("up", 1700000000000, 1)}})
+	req := httptest.NewRequest(http.MethodPost, PromRemoteWritePath, bytes.NewReader(body))
+	req.Header.Set("Content-Encoding", "snappy")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, res, 1)
+
+	req = httptest.NewRequest(http.MethodPost, "/write", bytes.NewReader(body))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPromRemoteWriteCleanup(t *testing.T) {
+	var res []otlpCollected
+	r := MakePromRemoteWrite(nil, otlpCollector(&res))
+	now := time.Now()
+	req := &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{{Type: prompb.MetricMetadata_COUNTER, MetricFamilyName: "jobs_done"}},
+		Timeseries: []prompb.TimeSeries{
+			remoteWriteSeries("jobs_done", 1700000000000, 1),
+			remoteWriteSeries("latency_bucket", 1700000000000, 1, "le", "+Inf"),
+		},
+	}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Len(t, r.metadata, 1)
+	require.Len(t, r.families, 1)
+	require.Len(t, r.counters, 1)
+	require.Len(t, r.histograms, 1)
+
+	// state of families not seen for a while is forgotten together with their series
+	now = now.Add(remoteWriteSeriesTTL + time.Minute)
+	req = &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{remoteWriteSeries("temperature", 1700000000000, 21.5)}}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Len(t, r.metadata, 0)
+	require.Len(t, r.families, 0)
+	require.Len(t, r.counters, 0)
+	require.Len(t, r.histograms, 0)
+
+	// without metadata and "_bucket" series, "jobs_done" is a gauge again
+	req = &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{remoteWriteSeries("jobs_done", 1700003700000, 2)}}
+	require.NoError(t, r.handleRequest(remoteWriteBody(t, req), now))
+	require.Equal(t, "jobs_done", res[len(res)-1].name)
+	require.Equal(t, []float64{2}, res[len(res)-1].value)
+}
@@ -478,76 +478,38 @@ func (s *scraper) scrape(opt scrapeOptions) error {
 	}
 	for hashSum, currValue := range counters {
 		if prev := s.counters[hashSum]; prev != nil {
-			v := currValue - prev.value
-			if v > 0 {
+			if v := prev.delta(currValue); v > 0 {
 				s.resetMetric(&b, opt.job, len(prev.tags))
-				b.Name = appendString(b.Name, prev.name)
-				for _, tag := range prev.tags {
-					b.Tags = appendTag(b.Tags, tag.Name, tag.Value)
-				}
-				b.SetCounter(v)
+				prev.setMetric(&b, v)
 				s.handler.HandleMetrics(data_model.HandlerArgs{
 					MetricBytes:    &b,
 					Description:    prev.description,
 					ScrapeInterval: int(opt.interval.Seconds()),
 				})
 			}
-			prev.value = currValue
 		}
 	}
 	for metricName, curr := range histograms {
 		// calculate buckets diff
 		for _, s := range curr.series {
-			for i := len(s.bucket); i > 1; i-- {
-				s.bucket[i-1] -= s.bucket[i-2]
-			}
+			s.decumulate()
 		}
 		metric := s.histograms[metricName]
 		if len(metric.buckets) == 0 {
-			// build description
-			var sb strings.Builder
-			if len(metric.descriptionS) != 0 {
-				sb.WriteString(metric.descriptionS)
-				sb.WriteByte('\n')
-				sb.WriteByte('\n')
-			}
-			sb.WriteString(format.HistogramBucketsStartMark)
-			sb.WriteString(curr.buckets[0])
-			for i := 1; i < len(curr.buckets); i++ {
-				sb.WriteByte(format.HistogramBucketsDelimC)
-				sb.WriteString(curr.buckets[i])
-			}
-			sb.WriteByte(format.HistogramBucketsEndMarkC)
-			metric.descriptionB = sb.String()
-			// encode bucket tag values
-			metric.buckets = make([]string, len(curr.buckets))
-			for i := 0; i < len(curr.buckets); i++ {
-				var bucket float64
-				if bucket, err = strconv.ParseFloat(curr.buckets[i], 32); err == nil {
-					metric.buckets[i] = strconv.FormatInt(int64(statshouse.LexEncode(float32(bucket))), 10)
-				}
-			}
+			metric.setBuckets(curr.buckets)
 		}
 		for hashSum, curr := range curr.series {
 			if prev, ok := metric.series[hashSum]; ok {
 				// "_bucket" metric
-				for i := 0; i < len(prev.bucket) && i < len(curr.bucket) && i < len(metric.buckets); i++ {
-					v := curr.bucket[i] - prev.bucket[i]
-					if v > 0 {
-						s.resetMetric(&b, opt.job, len(metric.tags)+1)
-						b.Name = appendString(b.Name, metric.nameB)
-						for _, v := range metric.tags {
-							b.Tags = appendTag(b.Tags, v.Name, v.Value)
-						}
-						b.Tags = appendTag(b.Tags, format.LETagName, metric.buckets[i])
-						b.SetCounter(v)
-						s.handler.HandleMetrics(data_model.HandlerArgs{
-							MetricBytes:    &b,
-							Description:    metric.descriptionB,
-							ScrapeInterval: int(opt.interval.Seconds()),
-						})
-					}
-				}
+				metric.bucketDeltas(prev, curr, func(i int, v float64) {
+					s.resetMetric(&b, opt.job, len(metric.tags)+1)
+					metric.setBucketMetric(&b, i, v)
+					s.handler.HandleMetrics(data_model.HandlerArgs{
+						MetricBytes:    &b,
+						Description:    metric.descriptionB,
+						ScrapeInterval: int(opt.interval.Seconds()),
+					})
+				})
 			}
 			// "_sum" metric
 			if curr.count > 0 {
@@ -571,6 +533,57 @@ func (s *scraper) scrape(opt scrapeOptions) error {
 	return nil
 }
 
+// returns counter increment since last value, counter reset gives negative increment
+func (c *scrapeCounter) delta(v float64) float64 {
+	d := v - c.value
+	c.value = v
+	return d
+}
+
+func (c *scrapeCounter) setMetric(b *tlstatshouse.MetricBytes, v float64) {
+	b.Name = appendString(b.Name, c.name)
+	for _, tag := range c.tags {
+		b.Tags = appendTag(b.Tags, tag.Name, tag.Value)
+	}
+	b.SetCounter(v)
+}
+
+// converts cumulative "le" bucket values to values of individual buckets
+func (s *scrapeHistogramSeries) decumulate() {
+	for i := len(s.bucket); i > 1; i-- {
+		s.bucket[i-1] -= s.bucket[i-2]
+	}
+}
+
+// sets "_bucket" metric description and encodes "le" tag values
+func (h *scrapeHistogram) setBuckets(buckets []string) {
+	h.descriptionB = histogramBucketsDescription(h.descriptionS, buckets)
+	h.buckets = make([]string, len(buckets))
+	for i := 0; i < len(buckets); i++ {
+		if bucket, err := strconv.ParseFloat(buckets[i], 32); err == nil {
+			h.buckets[i] = strconv.FormatInt(int64(statshouse.LexEncode(float32(bucket))), 10)
+		}
+	}
+}
+
+// calls f for every bucket which value has grown, both series must be decumulated
+func (h *scrapeHistogram) bucketDeltas(prev, curr *scrapeHistogramSeries, f func(i int, v float64)) {
+	for i := 0; i < len(prev.bucket) && i < len(curr.bucket) && i < len(h.buckets); i++ {
+		if v := curr.bucket[i] - prev.bucket[i]; v > 0 {
+			f(i, v)
+		}
+	}
+}
+
+func (h *scrapeHistogram) setBucketMetric(b *tlstatshouse.MetricBytes, i int, v float64) {
+	b.Name = appendString(b.Name, h.nameB)
+	for _, v := range h.tags {
+		b.Tags = appendTag(b.Tags, v.Name, v.Value)
+	}
+	b.Tags = appendTag(b.Tags, format.LETagName, h.buckets[i])
+	b.SetCounter(v)
+}
+
 func (s *scraper) readBytes(timeout time.Duration) ([]byte, string, error) {
 	// set timeout
 	s.request.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64))
@@ -0,0 +1,182 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+// StatsD and DogStatsD line protocol, one or more lines separated by '\n'
+//   <name>:<value>[:<value>...]|<type>[|@<sample rate>][|#<tag>:<value>,...][|T<unix timestamp>][|c:<container id>]
+// Unknown sections are ignored. Relative gauges (+N, -N) are reported as parse errors, because agent does not keep last gauge value.
+// Tags without value are ignored, because empty tag value means "not set" in statshouse.
+// Events (_e{...}) and service checks (_sc|...) are reported as parse errors.
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/zeebo/xxh3"
+
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+)
+
+var (
+	statsdEventPrefix        = []byte("_e{")
+	statsdServiceCheckPrefix = []byte("_sc|")
+)
+
+// first line must start with a name and contain both value and type separators
+func statsdLooksLikeLine(pkt []byte) bool {
+	if len(pkt) == 0 {
+		return false
+	}
+	if bytes.HasPrefix(pkt, statsdServiceCheckPrefix) {
+		return true
+	}
+	c := pkt[0]
+	if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_') {
+		return false
+	}
+	colon := false
+	for _, c := range pkt {
+		switch {
+		case c == '\n':
+			return false
+		case c == ':':
+			colon = true
+		case c == '|':
+			return colon
+		case c < 0x20 || c >= 0x7F:
+			return false
+		}
+	}
+	return false
+}
+
+// returns number of lines which were not parsed, each error is reported to handler
+func statsdUnmarshalBatch(batch *tlstatshouse.AddMetricsBatchBytes, pkt []byte, parseError func([]byte, error)) (errors int) {
+	batch.Metrics = batch.Metrics[:0]
+	for len(pkt) != 0 {
+		line := pkt
+		if i := bytes.IndexByte(pkt, '\n'); i >= 0 {
+			line, pkt = pkt[:i], pkt[i+1:]
+		} else {
+			pkt = nil
+		}
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		if len(line) == 0 {
+			continue
+		}
+		if err := statsdAppendMetric(batch, line); err != nil {
+			parseError(line, err)
+			errors++
+		}
+	}
+	return errors
+}
+
+func statsdAppendMetric(batch *tlstatshouse.AddMetricsBatchBytes, line []byte) error {
+	n := len(batch.Metrics)
+	if cap(batch.Metrics) > n {
+		batch.Metrics = batch.Metrics[:n+1]
+	} else {
+		batch.Metrics = append(batch.Metrics, tlstatshouse.MetricBytes{})
+	}
+	m := &batch.Metrics[n]
+	m.Reset()
+	if err := statsdParseLine(m, line); err != nil {
+		batch.Metrics = batch.Metrics[:n]
+		return err
+	}
+	return nil
+}
+
+func statsdParseLine(m *tlstatshouse.MetricBytes, line []byte) error {
+	if bytes.HasPrefix(line, statsdEventPrefix) || bytes.HasPrefix(line, statsdServiceCheckPrefix) {
+		return fmt.Errorf("statsd events and service checks are not supported")
+	}
+	colon := bytes.IndexByte(line, ':')
+	if colon <= 0 {
+		return fmt.Errorf("statsd metric name not found")
+	}
+	pipe := bytes.IndexByte(line[colon:], '|')
+	if pipe < 0 {
+		return fmt.Errorf("statsd metric type not found")
+	}
+	m.Name = appendString(m.Name, sanitizeName(string(line[:colon]), false))
+	values := line[colon+1 : colon+pipe]
+	sections := bytes.Split(line[colon+pipe+1:], []byte{'|'})
+	typ := string(sections[0])
+	rate := 1.0
+	for _, s := range sections[1:] {
+		if len(s) == 0 {
+			continue
+		}
+		switch {
+		case s[0] == '@':
+			r, err := strconv.ParseFloat(string(s[1:]), 64)
+			if err != nil || !(r > 0 && r <= 1) {
+				return fmt.Errorf("statsd sample rate %q must be in (0, 1]", s[1:])
+			}
+			rate = r
+		case s[0] == '#':
+			for _, t := range bytes.Split(s[1:], []byte{','}) {
+				k, v, ok := bytes.Cut(t, []byte{':'})
+				if !ok || len(k) == 0 || len(v) == 0 {
+					continue
+				}
+				m.Tags = appendTag(m.Tags, sanitizeName(string(k), false), string(v))
+			}
+		case s[0] == 'T':
+			ts, err := strconv.ParseUint(string(s[1:]), 10, 32)
+			if err != nil {
+				return fmt.Errorf("statsd timestamp %q: %w", s[1:], err)
+			}
+			m.SetTs(uint32(ts))
+		}
+	}
+	switch typ {
+	case "c":
+		sum := 0.0
+		for _, s := range bytes.Split(values, []byte{':'}) {
+			v, err := strconv.ParseFloat(string(s), 64)
+			if err != nil {
+				return fmt.Errorf("statsd counter value %q: %w", s, err)
+			}
+			sum += v
+		}
+		m.SetCounter(sum / rate)
+		return nil
+	case "g", "ms", "h", "d":
+		m.Value = m.Value[:0]
+		for _, s := range bytes.Split(values, []byte{':'}) {
+			if typ == "g" && len(s) != 0 && (s[0] == '+' || s[0] == '-') {
+				return fmt.Errorf("statsd relative gauge %q is not supported", s)
+			}
+			v, err := strconv.ParseFloat(string(s), 64)
+			if err != nil {
+				return fmt.Errorf("statsd value %q: %w", s, err)
+			}
+			m.Value = append(m.Value, v)
+		}
+		m.SetValue(m.Value)
+		if rate < 1 {
+			m.SetCounter(float64(len(m.Value)) / rate)
+		}
+		return nil
+	case "s":
+		// set members are arbitrary strings, integers are kept as is so they can be compared with uniques from other clients
+		v, err := strconv.ParseInt(string(values), 10, 64)
+		if err != nil {
+			v = int64(xxh3.Hash(values))
+		}
+		m.SetUnique(append(m.Unique[:0], v))
+		if rate < 1 {
+			m.SetCounter(1 / rate)
+		}
+		return nil
+	}
+	return fmt.Errorf("statsd metric type %q is not supported", typ)
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+)
+
+func TestStatsDLooksLikeLine(t *testing.T) {
+	require.True(t, statsdLooksLikeLine([]byte("page.views:1|c")))
+	require.True(t, statsdLooksLikeLine([]byte("_sc|name|0")))
+	require.True(t, statsdLooksLikeLine([]byte("SHARD.x:1|g\nother:2|c")))
+	require.False(t, statsdLooksLikeLine([]byte(`{"metrics":[]}`)))
+	require.False(t, statsdLooksLikeLine([]byte("page.views\n:1|c")))
+	require.False(t, statsdLooksLikeLine([]byte{0x99, 0x68, ':', '|'}))
+	require.False(t, statsdLooksLikeLine(nil))
+}
+
+func TestStatsDUnmarshal(t *testing.T) {
+	var batch tlstatshouse.AddMetricsBatchBytes
+	var parseErrors []string
+	pkt := "page.views:3|c|@0.5|#env:prod,host:h1,bare\n" +
+		"latency:10:20|ms|@0.25\r\n" +
+		"\n" +
+		"queue.size:5|g|T1700000000\n" +
+		"queue.size:-1|g\n" +
+		"queue.size:+1|g\n" +
+		"users:alice|s\n" +
+		"ids:42|s|#kind:int\n" +
+		"broken:x|c\n" +
+		"_e{5,4}:title|text\n" +
+		"_sc|check|0\n" +
+		"unknown:1|zz\n" +
+		"dist:1.5|d|c:container|e:ignored"
+	errors := statsdUnmarshalBatch(&batch, []byte(pkt), func(line []byte, err error) {
+		parseErrors = append(parseErrors, string(line))
+	})
+	require.Equal(t, 6, errors)
+	require.Equal(t, []string{"queue.size:-1|g", "queue.size:+1|g", "broken:x|c", "_e{5,4}:title|text", "_sc|check|0", "unknown:1|zz"}, parseErrors)
+	require.Len(t, batch.Metrics, 6)
+
+	m := batch.Metrics[0]
+	require.Equal(t, "page_views", string(m.Name))
+	require.Equal(t, 6.0, m.Counter)
+	require.Len(t, m.Tags, 2)
+	require.Equal(t, "env", string(m.Tags[0].Key))
+	require.Equal(t, "prod", string(m.Tags[0].Value))
+	require.Equal(t, "host", string(m.Tags[1].Key))
+
+	m = batch.Metrics[1]
+	require.Equal(t, []float64{10, 20}, m.Value)
+	require.Equal(t, 8.0, m.Counter)
+
+	m = batch.Metrics[2]
+	require.Equal(t, "queue_size", string(m.Name))
+	require.Equal(t, []float64{5}, m.Value)
+	require.False(t, m.IsSetCounter())
+	require.Equal(t, uint32(1700000000), m.Ts)
+
+	m = batch.Metrics[3]
+	require.True(t, m.IsSetUnique())
+	require.Len(t, m.Unique, 1)
+
+	m = batch.Metrics[4]
+	require.Equal(t, []int64{42}, m.Unique)
+
+	m = batch.Metrics[5]
+	require.Equal(t, []float64{1.5}, m.Value)
+	require.Len(t, m.Tags, 0)
+
+	// batch memory is reused between packets
+	errors = statsdUnmarshalBatch(&batch, []byte("a:1|c"), func(line []byte, err error) {})
+	require.Equal(t, 0, errors)
+	require.Len(t, batch.Metrics, 1)
+	require.Len(t, batch.Metrics[0].Tags, 0)
+	require.Equal(t, 1.0, batch.Metrics[0].Counter)
+}
+
+func TestStatsDSampleRate(t *testing.T) {
+	var batch tlstatshouse.AddMetricsBatchBytes
+	for _, line := range []string{"a:1|c|@0", "a:1|c|@1.5", "a:1|c|@x", "a:1|c|T-1"} {
+		errors := statsdUnmarshalBatch(&batch, []byte(line), func(line []byte, err error) {})
+		require.Equal(t, 1, errors, line)
+	}
+}
+
+func TestStatsDServe(t *testing.T) {
+	recv, err := ListenUDP("udp", "127.0.0.1:", DefaultConnBufSize, false, nil, nil)
+	require.NoError(t, err)
+	conn, err := net.Dial("udp", recv.Addr())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("jobs:2|c|#queue:default\nbad:1|zz\n"))
+	require.NoError(t, err)
+	var names []string
+	var parseErrors int
+	err = recv.Serve(CallbackHandler{
+		Metrics: func(m *tlstatshouse.MetricBytes, cb data_model.MapCallbackFunc) (h data_model.MappedMetricHeader, done bool) {
+			names = append(names, string(m.Name))
+			_ = recv.Close()
+			return h, true
+		},
+		ParseError: func(pkt []byte, err error) {
+			parseErrors++
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"jobs"}, names)
+	require.Equal(t, 1, parseErrors)
+	require.Equal(t, uint64(0), recv.StatBatchesTotalOK())
+	require.Equal(t, uint64(1), recv.StatBatchesTotalErr())
+}
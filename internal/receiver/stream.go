@@ -0,0 +1,214 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+// Stream protocol over TCP or Unix stream sockets, all integers are little-endian.
+//
+// Client sends frames:
+//   uint32 length of payload
+//   uint32 batch id, 0 means no acknowledgement is required
+//   payload, boxed statshouse.addMetricsBatch, same as in UDP packets
+// For every frame with non-zero batch id server sends acknowledgement:
+//   uint32 batch id
+//   uint32 length of error text, 0 means all metrics in batch were accepted
+//   error text
+// Frames are processed in order and acknowledgements are sent in the same order.
+// Next frame is not read until the previous one is completely mapped, so when mapping queue
+// is full the socket is not read and clients are slowed down by transport flow control.
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/vkcom/statshouse/internal/agent"
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+	"github.com/vkcom/statshouse/internal/mapping"
+)
+
+const (
+	streamFrameHeaderSize = 8
+	streamMaxFrameSize    = DefaultConnBufSize
+	streamOverloadTimeout = 30 * time.Second // after that time batch is acknowledged with queue overload error
+	streamOverloadBackoff = 10 * time.Millisecond
+)
+
+type Stream struct {
+	handler Handler
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+
+	batchSizeOK   *agent.BuiltInItemValue
+	batchSizeErr  *agent.BuiltInItemValue
+	packetSizeOK  *agent.BuiltInItemValue
+	packetSizeErr *agent.BuiltInItemValue
+
+	StatConnectionsTotal atomic.Uint64
+	StatBatchesTotalOK   atomic.Uint64
+	StatBatchesTotalErr  atomic.Uint64
+}
+
+func MakeStream(ag *agent.Agent, h Handler) *Stream {
+	return &Stream{
+		handler:       h,
+		conns:         map[net.Conn]struct{}{},
+		batchSizeOK:   createBatchSizeValue(ag, format.TagValueIDPacketFormatStream, format.TagValueIDAgentReceiveStatusOK),
+		batchSizeErr:  createBatchSizeValue(ag, format.TagValueIDPacketFormatStream, format.TagValueIDAgentReceiveStatusError),
+		packetSizeOK:  createPacketSizeValue(ag, format.TagValueIDPacketFormatStream, format.TagValueIDAgentReceiveStatusOK),
+		packetSizeErr: createPacketSizeValue(ag, format.TagValueIDPacketFormatStream, format.TagValueIDAgentReceiveStatusError),
+	}
+}
+
+// Serve blocks until listener is closed, connections accepted from this listener are closed before return
+func (s *Stream) Serve(ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer s.closeConns()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		s.StatConnectionsTotal.Inc()
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = s.serveConn(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (s *Stream) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func (s *Stream) serveConn(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	var header [streamFrameHeaderSize]byte
+	var frame []byte
+	var batch tlstatshouse.AddMetricsBatchBytes
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		size := binary.LittleEndian.Uint32(header[0:])
+		id := binary.LittleEndian.Uint32(header[4:])
+		if size > streamMaxFrameSize {
+			return fmt.Errorf("stream frame size %d exceeds maximum %d", size, streamMaxFrameSize)
+		}
+		if cap(frame) < int(size) {
+			frame = make([]byte, size)
+		}
+		frame = frame[:size]
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return err
+		}
+		batchErr := s.handleFrame(&batch, frame)
+		if id == 0 {
+			continue
+		}
+		var errText string
+		if batchErr != nil {
+			errText = batchErr.Error()
+		}
+		binary.LittleEndian.PutUint32(header[0:], id)
+		binary.LittleEndian.PutUint32(header[4:], uint32(len(errText)))
+		_, _ = w.Write(header[:])
+		_, _ = w.WriteString(errText)
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// returns first parse or mapping error, like RPC receiver
+func (s *Stream) handleFrame(batch *tlstatshouse.AddMetricsBatchBytes, frame []byte) error {
+	if _, err := batch.ReadBoxed(frame); err != nil {
+		s.StatBatchesTotalErr.Inc()
+		s.handler.HandleParseError(frame, err)
+		setValueSize(s.batchSizeErr, len(frame))
+		setValueSize(s.packetSizeErr, len(frame))
+		return fmt.Errorf("failed to deserialize statshouse.addMetricsBatch: %w", err)
+	}
+	var firstError error
+	notDoneCount := 0
+	ch := make(chan error, len(batch.Metrics)) // buffer enough so that worker does not wait
+	cb := func(m tlstatshouse.MetricBytes, h data_model.MappedMetricHeader) {
+		ch <- mapping.MapErrorFromHeader(m, h)
+	}
+	for i := range batch.Metrics {
+		h, done := s.handleMetric(&batch.Metrics[i], cb)
+		if done && firstError == nil && h.IngestionStatus != 0 {
+			firstError = mapping.MapErrorFromHeader(batch.Metrics[i], h)
+		}
+		if !done {
+			notDoneCount++
+		}
+	}
+	for i := 0; i < notDoneCount; i++ {
+		err := <-ch
+		if firstError == nil {
+			firstError = err
+		}
+	}
+	if firstError != nil {
+		s.StatBatchesTotalErr.Inc()
+		setValueSize(s.batchSizeErr, len(frame))
+		setValueSize(s.packetSizeErr, len(frame))
+		return firstError
+	}
+	s.StatBatchesTotalOK.Inc()
+	setValueSize(s.batchSizeOK, len(frame))
+	setValueSize(s.packetSizeOK, len(frame))
+	return nil
+}
+
+// when mapping queue is full, waits until there is space instead of losing metric,
+// rejection is recorded in ingestion status built-in metric once, when metric is finally dropped
+func (s *Stream) handleMetric(m *tlstatshouse.MetricBytes, cb data_model.MapCallbackFunc) (h data_model.MappedMetricHeader, done bool) {
+	backoff := streamOverloadBackoff
+	deadline := time.Now().Add(streamOverloadTimeout)
+	for {
+		retry := time.Now().Before(deadline)
+		h, done = s.handler.HandleMetrics(data_model.HandlerArgs{MetricBytes: m, MapCallback: cb, RetryOverload: retry}) // might move out metric, if needs to
+		if !retry || !done || !data_model.IngestionStatusQueueOverload(h.IngestionStatus) {
+			return h, done
+		}
+		time.Sleep(backoff)
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
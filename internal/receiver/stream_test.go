@@ -0,0 +1,109 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package receiver
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/vkcom/statshouse/internal/data_model"
+	"github.com/vkcom/statshouse/internal/data_model/gen2/tlstatshouse"
+	"github.com/vkcom/statshouse/internal/format"
+)
+
+func streamFrame(id uint32, payload []byte) []byte {
+	frame := make([]byte, streamFrameHeaderSize, streamFrameHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(frame[0:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[4:], id)
+	return append(frame, payload...)
+}
+
+func streamReadAck(t *testing.T, conn net.Conn) (uint32, string) {
+	var header [streamFrameHeaderSize]byte
+	_, err := io.ReadFull(conn, header[:])
+	require.NoError(t, err)
+	text := make([]byte, binary.LittleEndian.Uint32(header[4:]))
+	_, err = io.ReadFull(conn, text)
+	require.NoError(t, err)
+	return binary.LittleEndian.Uint32(header[0:]), string(text)
+}
+
+func streamServe(t *testing.T, h Handler) (*Stream, net.Conn) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := MakeStream(nil, h)
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(func() { _ = ln.Close() })
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return s, conn
+}
+
+func TestStreamAcknowledgements(t *testing.T) {
+	var names []string
+	namesCh := make(chan string, 10)
+	s, conn := streamServe(t, CallbackHandler{
+		Metrics: func(m *tlstatshouse.MetricBytes, cb data_model.MapCallbackFunc) (h data_model.MappedMetricHeader, done bool) {
+			namesCh <- string(m.Name)
+			return h, true
+		},
+	})
+	batch := tlstatshouse.AddMetricsBatchBytes{Metrics: []tlstatshouse.MetricBytes{{Name: []byte("a")}, {Name: []byte("b")}}}
+	payload := batch.WriteBoxed(nil)
+	var pkt []byte
+	pkt = append(pkt, streamFrame(0, payload)...) // no ack requested
+	pkt = append(pkt, streamFrame(7, payload)...)
+	pkt = append(pkt, streamFrame(8, []byte{1, 2, 3})...)
+	_, err := conn.Write(pkt)
+	require.NoError(t, err)
+
+	id, text := streamReadAck(t, conn)
+	require.Equal(t, uint32(7), id)
+	require.Equal(t, "", text)
+	id, text = streamReadAck(t, conn)
+	require.Equal(t, uint32(8), id)
+	require.NotEmpty(t, text)
+	for i := 0; i < 4; i++ {
+		names = append(names, <-namesCh)
+	}
+	require.Equal(t, []string{"a", "b", "a", "b"}, names)
+	require.Equal(t, uint64(2), s.StatBatchesTotalOK.Load())
+	require.Equal(t, uint64(1), s.StatBatchesTotalErr.Load())
+
+	// oversized frame closes connection
+	_, err = conn.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 9, 0, 0, 0})
+	require.NoError(t, err)
+	_, err = io.ReadFull(conn, make([]byte, 1))
+	require.Error(t, err)
+}
+
+func TestStreamBackpressure(t *testing.T) {
+	var attempts atomic.Int32
+	_, conn := streamServe(t, CallbackHandler{
+		Metrics: func(m *tlstatshouse.MetricBytes, cb data_model.MapCallbackFunc) (h data_model.MappedMetricHeader, done bool) {
+			if attempts.Inc() < 3 {
+				h.IngestionStatus = format.TagValueIDSrcIngestionStatusErrMapGlobalQueueOverload
+				return h, true
+			}
+			go cb(*m, h) // mapped asynchronously
+			return h, false
+		},
+	})
+	batch := tlstatshouse.AddMetricsBatchBytes{Metrics: []tlstatshouse.MetricBytes{{Name: []byte("billing")}}}
+	_, err := conn.Write(streamFrame(1, batch.WriteBoxed(nil)))
+	require.NoError(t, err)
+	id, text := streamReadAck(t, conn)
+	require.Equal(t, uint32(1), id)
+	require.Equal(t, "", text)
+	require.Equal(t, int32(3), attempts.Load())
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol, only what is needed for page headers and file metadata
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+type compactWriter struct {
+	buf       bytes.Buffer
+	lastField []int16 // last field ID of each open struct
+}
+
+func (w *compactWriter) reset() {
+	w.buf.Reset()
+	w.lastField = w.lastField[:0]
+}
+
+func (w *compactWriter) structBegin() {
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *compactWriter) structEnd() {
+	w.buf.WriteByte(0) // stop
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; 0 < delta && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *compactWriter) varint(v int64) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (w *compactWriter) i32(v int32) {
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) string(s string) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	w.buf.WriteString(s)
+}
+
+func (w *compactWriter) fieldI32(id int16, v int32) {
+	w.fieldHeader(id, compactI32)
+	w.i32(v)
+}
+
+func (w *compactWriter) fieldI64(id int16, v int64) {
+	w.fieldHeader(id, compactI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) fieldString(id int16, s string) {
+	w.fieldHeader(id, compactBinary)
+	w.string(s)
+}
+
+func (w *compactWriter) fieldStructBegin(id int16) {
+	w.fieldHeader(id, compactStruct)
+	w.structBegin()
+}
+
+// list elements follow, structs must be started with "structBegin"
+func (w *compactWriter) fieldListBegin(id int16, elemType byte, size int) {
+	w.fieldHeader(id, compactList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xf0 | elemType)
+		w.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package parquet writes flat Parquet files without compression, one data page per column chunk.
+// Row groups are written as soon as they are passed, so file can be streamed, only footer and
+// the row group being written are kept in memory. Invalid row group is not written at all.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+type ColumnType int
+
+const (
+	TimestampMillis ColumnType = iota // []int64, required
+	Int64                             // []int64, required
+	String                            // []string, required
+	Double                            // []float64, optional, NaN is written as null
+)
+
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+var magic = []byte("PAR1")
+
+// parquet.thrift constants
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+	pageTypeData      = 0
+)
+
+type columnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+}
+
+type rowGroup struct {
+	numRows int64
+	size    int64
+	columns []columnChunk
+}
+
+type Writer struct {
+	w         io.Writer
+	columns   []Column
+	offset    int64
+	numRows   int64
+	rowGroups []rowGroup
+	page      bytes.Buffer
+	group     bytes.Buffer // row group is written only if all columns are valid
+	header    compactWriter
+}
+
+// NewWriter writes file header
+func NewWriter(w io.Writer, columns []Column) (*Writer, error) {
+	res := &Writer{w: w, columns: columns}
+	if err := res.write(magic); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// WriteRowGroup writes one value slice per column, all of the same length
+func (w *Writer) WriteRowGroup(values []interface{}) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("got %d columns, want %d", len(values), len(w.columns))
+	}
+	numRows := valuesLen(values[0])
+	if numRows == 0 {
+		return nil
+	}
+	g := rowGroup{numRows: int64(numRows), columns: make([]columnChunk, len(w.columns))}
+	w.group.Reset()
+	for i, c := range w.columns {
+		w.page.Reset()
+		var n int
+		switch v := values[i].(type) {
+		case []int64:
+			if c.Type != TimestampMillis && c.Type != Int64 {
+				return fmt.Errorf("column %q: unexpected []int64", c.Name)
+			}
+			n = len(v)
+			for _, x := range v {
+				w.page.Write(binary.LittleEndian.AppendUint64(nil, uint64(x)))
+			}
+		case []string:
+			if c.Type != String {
+				return fmt.Errorf("column %q: unexpected []string", c.Name)
+			}
+			n = len(v)
+			for _, x := range v {
+				w.page.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(x))))
+				w.page.WriteString(x)
+			}
+		case []float64:
+			if c.Type != Double {
+				return fmt.Errorf("column %q: unexpected []float64", c.Name)
+			}
+			n = len(v)
+			writeDefinitionLevels(&w.page, v)
+			for _, x := range v {
+				if !math.IsNaN(x) {
+					w.page.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(x)))
+				}
+			}
+		default:
+			return fmt.Errorf("column %q: unsupported values type %T", c.Name, v)
+		}
+		if n != numRows {
+			return fmt.Errorf("column %q has %d values, want %d", c.Name, n, numRows)
+		}
+		w.header.reset()
+		w.header.structBegin()
+		w.header.fieldI32(1, pageTypeData)
+		w.header.fieldI32(2, int32(w.page.Len()))
+		w.header.fieldI32(3, int32(w.page.Len()))
+		w.header.fieldStructBegin(5)
+		w.header.fieldI32(1, int32(n))
+		w.header.fieldI32(2, encodingPlain)
+		w.header.fieldI32(3, encodingRLE)
+		w.header.fieldI32(4, encodingRLE)
+		w.header.structEnd()
+		w.header.structEnd()
+		g.columns[i] = columnChunk{
+			offset:           w.offset + int64(w.group.Len()),
+			numValues:        int64(n),
+			uncompressedSize: int64(w.header.buf.Len() + w.page.Len()),
+		}
+		g.size += g.columns[i].uncompressedSize
+		w.group.Write(w.header.buf.Bytes())
+		w.group.Write(w.page.Bytes())
+	}
+	if err := w.write(w.group.Bytes()); err != nil {
+		return err
+	}
+	w.numRows += g.numRows
+	w.rowGroups = append(w.rowGroups, g)
+	return nil
+}
+
+func valuesLen(v interface{}) int {
+	switch v := v.(type) {
+	case []int64:
+		return len(v)
+	case []string:
+		return len(v)
+	case []float64:
+		return len(v)
+	}
+	return 0
+}
+
+// NaN values are null, definition levels are RLE encoded with bit width 1, prefixed by length
+func writeDefinitionLevels(buf *bytes.Buffer, v []float64) {
+	var rle []byte
+	for i := 0; i < len(v); {
+		j := i + 1
+		for j < len(v) && math.IsNaN(v[j]) == math.IsNaN(v[i]) {
+			j++
+		}
+		rle = binary.AppendUvarint(rle, uint64(j-i)<<1)
+		if math.IsNaN(v[i]) {
+			rle = append(rle, 0)
+		} else {
+			rle = append(rle, 1)
+		}
+		i = j
+	}
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(rle))))
+	buf.Write(rle)
+}
+
+// Close writes file footer, underlying writer is not closed
+func (w *Writer) Close() error {
+	var m compactWriter
+	m.structBegin()
+	m.fieldI32(1, 1) // version
+	m.fieldListBegin(2, compactStruct, len(w.columns)+1)
+	m.structBegin()
+	m.fieldString(4, "schema")
+	m.fieldI32(5, int32(len(w.columns)))
+	m.structEnd()
+	for _, c := range w.columns {
+		m.structBegin()
+		switch c.Type {
+		case TimestampMillis:
+			m.fieldI32(1, typeInt64)
+			m.fieldI32(3, repetitionRequired)
+			m.fieldString(4, c.Name)
+			m.fieldI32(6, convertedTimestampMillis)
+		case Int64:
+			m.fieldI32(1, typeInt64)
+			m.fieldI32(3, repetitionRequired)
+			m.fieldString(4, c.Name)
+		case String:
+			m.fieldI32(1, typeByteArray)
+			m.fieldI32(3, repetitionRequired)
+			m.fieldString(4, c.Name)
+			m.fieldI32(6, convertedUTF8)
+		case Double:
+			m.fieldI32(1, typeDouble)
+			m.fieldI32(3, repetitionOptional)
+			m.fieldString(4, c.Name)
+		}
+		m.structEnd()
+	}
+	m.fieldI64(3, w.numRows)
+	m.fieldListBegin(4, compactStruct, len(w.rowGroups))
+	for _, g := range w.rowGroups {
+		m.structBegin()
+		m.fieldListBegin(1, compactStruct, len(g.columns))
+		for i, c := range g.columns {
+			m.structBegin()
+			m.fieldI64(2, c.offset)
+			m.fieldStructBegin(3)
+			m.fieldI32(1, physicalType(w.columns[i].Type))
+			m.fieldListBegin(2, compactI32, 2)
+			m.i32(encodingPlain)
+			m.i32(encodingRLE)
+			m.fieldListBegin(3, compactBinary, 1)
+			m.string(w.columns[i].Name)
+			m.fieldI32(4, codecUncompressed)
+			m.fieldI64(5, c.numValues)
+			m.fieldI64(6, c.uncompressedSize)
+			m.fieldI64(7, c.uncompressedSize)
+			m.fieldI64(9, c.offset)
+			m.structEnd()
+			m.structEnd()
+		}
+		m.fieldI64(2, g.size)
+		m.fieldI64(3, g.numRows)
+		m.structEnd()
+	}
+	m.fieldString(6, "statshouse")
+	m.structEnd()
+	if err := w.write(m.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := w.write(binary.LittleEndian.AppendUint32(nil, uint32(m.buf.Len()))); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
+
+func physicalType(t ColumnType) int32 {
+	switch t {
+	case String:
+		return typeByteArray
+	case Double:
+		return typeDouble
+	default:
+		return typeInt64
+	}
+}
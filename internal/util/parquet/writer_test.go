@@ -0,0 +1,62 @@
+// Copyright 2022 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{"time", TimestampMillis}, {"env", String}, {"count", Double}})
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRowGroup([]interface{}{[]int64{1000, 2000}, []string{"production", "staging"}, []float64{1, math.NaN()}}))
+	require.NoError(t, w.WriteRowGroup([]interface{}{[]int64{}, []string{}, []float64{}}))
+	require.Error(t, w.WriteRowGroup([]interface{}{[]int64{3000}, []string{}, []float64{3}}))
+	require.Error(t, w.WriteRowGroup([]interface{}{[]int64{3000}, []float64{3}, []string{"production"}}))
+	require.NoError(t, w.Close())
+	require.Len(t, w.rowGroups, 1)
+	require.Equal(t, int64(2), w.numRows)
+
+	b := buf.Bytes()
+	require.Equal(t, magic, b[:4])
+	require.Equal(t, magic, b[len(b)-4:])
+	footer := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	require.Less(t, footer, len(b)-12)
+	require.Equal(t, int64(4), w.rowGroups[0].columns[0].offset)
+}
+
+// testdata/writer.parquet was verified by reading it back with independent implementation,
+// any change of writer output must be verified the same way before updating the file
+func TestWriterGolden(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{"time", TimestampMillis}, {"env", String}, {"count", Double}, {"id", Int64}})
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRowGroup([]interface{}{[]int64{1000, 2000}, []string{"production", "staging"}, []float64{1, math.NaN()}, []int64{7, -7}}))
+	size := buf.Len()
+	require.Error(t, w.WriteRowGroup([]interface{}{[]int64{3000}, []string{"x"}, []float64{3}, []int64{}}))
+	require.Equal(t, size, buf.Len(), "invalid row group must not be written")
+	require.NoError(t, w.WriteRowGroup([]interface{}{[]int64{3000}, []string{""}, []float64{2.5}, []int64{0}}))
+	require.NoError(t, w.Close())
+
+	golden, err := os.ReadFile("testdata/writer.parquet")
+	require.NoError(t, err)
+	require.Equal(t, golden, buf.Bytes())
+}
+
+func TestDefinitionLevels(t *testing.T) {
+	var buf bytes.Buffer
+	writeDefinitionLevels(&buf, []float64{1, 2, math.NaN(), 3})
+	// runs of 2 defined, 1 null, 1 defined
+	require.Equal(t, []byte{6, 0, 0, 0, 2 << 1, 1, 1 << 1, 0, 1 << 1, 1}, buf.Bytes())
+}
@@ -1,11 +1,11 @@
 #!/bin/bash
 set -e
 if [ -z "$STATSHOUSE_DEBUG" ]; then
-  /bin/statshouse agent --cluster=local_test_cluster --log-level=trace --remote-write-enabled \
+  /bin/statshouse agent --cluster=local_test_cluster --log-level=trace \
    --agg-addr="aggregator:13336,aggregator:13336,aggregator:13336=" --cache-dir=/var/lib/statshouse/cache/agent \
    -u=root -g=root &
 else
-  /bin/dlv exec --headless --listen=:8000 --api-version=2 /bin/statshouse -- agent --cluster=local_test_cluster --log-level=trace --remote-write-enabled \
+  /bin/dlv exec --headless --listen=:8000 --api-version=2 /bin/statshouse -- agent --cluster=local_test_cluster --log-level=trace \
    --agg-addr="aggregator:13336,aggregator:13336,aggregator:13336=" --cache-dir=/var/lib/statshouse/cache/agent \
    -u=root -g=root &
 fi
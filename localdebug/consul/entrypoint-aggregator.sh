@@ -24,7 +24,7 @@ $META --db-path=/var/lib/statshouse/metadata/db --binlog-prefix=/var/lib/statsho
 until clickhouse-client --query="SELECT 1"; do sleep 0.2; done
 $AGGREGATOR aggregator --cluster=local_test_cluster --log-level=trace --agg-addr=':13336' --kh=127.0.0.1:8123 \
   --auto-create -cache-dir=/var/lib/statshouse/cache/aggregator -u=root -g=root &
-$AGENT agent --cluster=local_test_cluster --log-level=trace --remote-write-enabled \
+$AGENT agent --cluster=local_test_cluster --log-level=trace \
   --agg-addr='127.0.0.1:13336,127.0.0.1:13336,127.0.0.1:13336' --cache-dir=/var/lib/statshouse/cache/agent \
   -u=root -g=root &
 $API --verbose --insecure-mode --local-mode --access-log --clickhouse-v1-addrs= --clickhouse-v2-addrs=127.0.0.1:9000 \
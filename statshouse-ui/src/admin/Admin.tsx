@@ -7,6 +7,8 @@
 import * as React from 'react';
 import { FormPage } from './pages/FormPage';
 import { CreatePage } from './pages/CreatePage';
+import { CardinalityPage } from './pages/CardinalityPage';
+import { SamplingExplainPage } from './pages/SamplingExplainPage';
 import { Route, Routes } from 'react-router-dom';
 
 export function Admin(props: { yAxisSize: number; adminMode: boolean }) {
@@ -16,6 +18,8 @@ export function Admin(props: { yAxisSize: number; adminMode: boolean }) {
     <Routes>
       <Route path="create" element={<CreatePage yAxisSize={yAxisSize} />} />
       <Route path="edit/:metricName" element={<FormPage adminMode={adminMode} yAxisSize={yAxisSize} />} />
+      <Route path="cardinality/:metricName" element={<CardinalityPage yAxisSize={yAxisSize} />} />
+      <Route path="sampling/:metricName" element={<SamplingExplainPage yAxisSize={yAxisSize} />} />
     </Routes>
   );
 }
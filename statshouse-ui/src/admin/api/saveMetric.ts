@@ -6,6 +6,18 @@
 
 import { IBackendKind, IBackendMetric, IMetric } from '../models/metric';
 
+// options were set by magic words in description before version 1
+const metricOptionsVersion = 1;
+
+function parseHistogramBuckets(s?: string): number[] | undefined {
+  const buckets = (s ?? '')
+    .split(',')
+    .map((v) => v.trim())
+    .filter((v) => v !== '')
+    .map(Number);
+  return buckets.length > 0 ? buckets : undefined;
+}
+
 export function saveMetric(metric: IMetric) {
   const body: IBackendMetric = {
     description: metric.description,
@@ -43,6 +55,10 @@ export function saveMetric(metric: IMetric) {
     metric_type: metric.metric_type,
     version: metric.version,
     group_id: metric.group_id,
+    options_version: metricOptionsVersion,
+    round_sample_factors: !!metric.round_sample_factors,
+    shard_unique_values: !!metric.shard_unique_values,
+    histogram_buckets: parseHistogramBuckets(metric.histogramBuckets),
   };
 
   return fetch(`/api/metric${metric.id ? `?s=${metric.name}` : ''}`, {
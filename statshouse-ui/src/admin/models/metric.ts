@@ -51,6 +51,9 @@ export interface IMetric extends IShortMetric {
   readonly metric_type?: string;
   readonly version?: number;
   readonly group_id?: number;
+  readonly round_sample_factors?: boolean;
+  readonly shard_unique_values?: boolean;
+  readonly histogramBuckets?: string;
 }
 
 export interface IBackendMetric {
@@ -74,4 +77,8 @@ export interface IBackendMetric {
   readonly metric_type?: string;
   readonly version?: number;
   readonly group_id?: number;
+  readonly options_version?: number;
+  readonly round_sample_factors?: boolean;
+  readonly shard_unique_values?: boolean;
+  readonly histogram_buckets?: number[];
 }
@@ -0,0 +1,128 @@
+// Copyright 2023 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+import React, { useEffect, useState } from 'react';
+import { Link, useParams } from 'react-router-dom';
+import cn from 'classnames';
+import { GET_PARAMS } from '../../api/enum';
+import { apiMetricCardinalityFetch, CardinalityResp } from '../../api/metricCardinality';
+
+const ranges = [
+  { label: 'last hour', value: -3600 },
+  { label: 'last day', value: -86400 },
+  { label: 'last week', value: -7 * 86400 },
+];
+
+const formatCount = (n: number) => Math.round(n).toLocaleString();
+const formatShare = (n: number) => `${Math.round(n * 1000) / 10}%`;
+
+export function CardinalityPage(props: { yAxisSize: number }) {
+  const { yAxisSize } = props;
+  const { metricName } = useParams();
+  const [range, setRange] = useState(ranges[0].value);
+  const [data, setData] = useState<CardinalityResp | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    document.title = `${metricName + ': cardinality'} — StatsHouse`;
+  }, [metricName]);
+
+  useEffect(() => {
+    if (!metricName) {
+      return;
+    }
+    setLoading(true);
+    apiMetricCardinalityFetch({
+      [GET_PARAMS.metricName]: metricName,
+      [GET_PARAMS.fromTime]: range.toString(),
+      [GET_PARAMS.toTime]: '0',
+    })
+      .then(({ response, error }) => {
+        setData(response?.data ?? null);
+        setError(error?.message ?? null);
+      })
+      .finally(() => {
+        setLoading(false);
+      });
+  }, [metricName, range]);
+
+  return (
+    <div className="container-xl pt-3 pb-3" style={{ paddingLeft: `${yAxisSize}px` }}>
+      <h6 className="overflow-force-wrap font-monospace fw-bold me-3 mb-3">
+        {metricName}
+        <>
+          <span className="text-secondary me-4">: cardinality</span>
+          <Link className="text-decoration-none fw-normal small me-3" to={`../../view?s=${metricName}`}>
+            view
+          </Link>
+          <Link className="text-decoration-none fw-normal small" to={`../edit/${metricName}`}>
+            edit
+          </Link>
+        </>
+      </h6>
+      <div className="btn-group mb-3">
+        {ranges.map((r) => (
+          <button
+            key={r.value}
+            type="button"
+            className={cn('btn btn-sm', r.value === range ? 'btn-primary' : 'btn-outline-primary')}
+            onClick={() => setRange(r.value)}
+          >
+            {r.label}
+          </button>
+        ))}
+      </div>
+      {error && <div className="alert alert-danger">{error}</div>}
+      {loading && !data ? (
+        <div className="d-flex justify-content-center align-items-center mt-5">
+          <div className="spinner-border text-secondary" role="status">
+            <span className="visually-hidden">Loading...</span>
+          </div>
+        </div>
+      ) : (
+        data && (
+          <>
+            <p className="text-secondary">
+              Unique series per hour: <span className="font-monospace">{formatCount(data.cardinality)}</span>
+            </p>
+            <table className="table table-sm">
+              <thead>
+                <tr>
+                  <th>Tag</th>
+                  <th
+                    className="text-end"
+                    title="Unique values per hour, depending on how values are spread between aggregator shards"
+                  >
+                    Unique values
+                  </th>
+                  <th>Top values by share of new series</th>
+                </tr>
+              </thead>
+              <tbody>
+                {data.tags.map((tag) => (
+                  <tr key={tag.id}>
+                    <td className="font-monospace">{tag.name || `tag ${tag.id}`}</td>
+                    <td className="font-monospace text-end text-nowrap">
+                      {formatCount(tag.distinct_lower)} – {formatCount(tag.distinct_upper)}
+                    </td>
+                    <td>
+                      {tag.top_values?.map((v) => (
+                        <span key={v.value} className="badge text-bg-light font-monospace me-1">
+                          {v.value}: {formatShare(v.share)}
+                        </span>
+                      ))}
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </>
+        )
+      )}
+    </div>
+  );
+}
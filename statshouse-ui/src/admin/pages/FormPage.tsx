@@ -72,6 +72,9 @@ export function FormPage(props: { yAxisSize: number; adminMode: boolean }) {
           metric_type: metric.metric_type,
           version: metric.version,
           group_id: metric.group_id,
+          round_sample_factors: metric.round_sample_factors,
+          shard_unique_values: metric.shard_unique_values,
+          histogramBuckets: metric.histogram_buckets?.join(', '),
         });
       });
   }, [metricName]);
@@ -87,9 +90,15 @@ export function FormPage(props: { yAxisSize: number; adminMode: boolean }) {
         {metricName}
         <>
           <span className="text-secondary me-4">: edit</span>
-          <Link className="text-decoration-none fw-normal small" to={`../../view?s=${metricName}`}>
+          <Link className="text-decoration-none fw-normal small me-3" to={`../../view?s=${metricName}`}>
             view
           </Link>
+          <Link className="text-decoration-none fw-normal small me-3" to={`../cardinality/${metricName}`}>
+            cardinality
+          </Link>
+          <Link className="text-decoration-none fw-normal small" to={`../sampling/${metricName}`}>
+            sampling
+          </Link>
         </>
       </h6>
       {metricName && !initMetric ? (
@@ -573,6 +582,74 @@ export function EditForm(props: { isReadonly: boolean; adminMode: boolean }) {
         </div>
         <div id="skip_sum_squareHelpBlock" className="form-text"></div>
       </div>
+      <div className="row align-items-baseline mb-3">
+        <label htmlFor="round_sample_factors" className="col-sm-2 col-form-label">
+          Round sample factors
+        </label>
+        <div className="col-sm-auto pt-1">
+          <div className="form-check form-switch">
+            <input
+              id="round_sample_factors"
+              name="round_sample_factors"
+              type="checkbox"
+              className="form-check-input"
+              checked={!!values.round_sample_factors}
+              onChange={(e) => dispatch({ round_sample_factors: e.target.checked })}
+              disabled={isReadonly || !adminMode}
+            />
+            <label htmlFor="round_sample_factors" className="form-check-label">
+              {' '}
+            </label>
+          </div>
+        </div>
+        <div id="round_sample_factorsHelpBlock" className="form-text">
+          Experimental. Sample factors are rounded to integers, so sampled counters stay integer.
+        </div>
+      </div>
+      <div className="row align-items-baseline mb-3">
+        <label htmlFor="shard_unique_values" className="col-sm-2 col-form-label">
+          Shard unique values
+        </label>
+        <div className="col-sm-auto pt-1">
+          <div className="form-check form-switch">
+            <input
+              id="shard_unique_values"
+              name="shard_unique_values"
+              type="checkbox"
+              className="form-check-input"
+              checked={!!values.shard_unique_values}
+              onChange={(e) => dispatch({ shard_unique_values: e.target.checked })}
+              disabled={isReadonly || !adminMode}
+            />
+            <label htmlFor="shard_unique_values" className="form-check-label">
+              {' '}
+            </label>
+          </div>
+        </div>
+        <div id="shard_unique_valuesHelpBlock" className="form-text">
+          Experimental. Agents send unique values to shards by value, so unique counts of different shards do not
+          intersect.
+        </div>
+      </div>
+      <div className="row mb-3">
+        <label htmlFor="histogram_buckets" className="col-sm-2 col-form-label">
+          Histogram buckets
+        </label>
+        <div className="col-sm-auto">
+          <input
+            id="histogram_buckets"
+            name="histogram_buckets"
+            className="form-control"
+            value={values.histogramBuckets ?? ''}
+            placeholder="0.1, 0.5, 1"
+            onChange={(e) => dispatch({ histogramBuckets: e.target.value })}
+            disabled={values.kind !== 'counter' || isReadonly}
+          />
+        </div>
+        <div id="histogram_bucketsHelpBlock" className="form-text">
+          Upper bounds of Prometheus histogram buckets in ascending order, counters only.
+        </div>
+      </div>
 
       <div>
         <button type="button" disabled={isRunning || isReadonly} className="btn btn-primary me-3" onClick={onSubmit}>
@@ -0,0 +1,133 @@
+// Copyright 2023 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+import React, { useCallback, useEffect, useState } from 'react';
+import { Link, useParams } from 'react-router-dom';
+import cn from 'classnames';
+import { GET_PARAMS } from '../../api/enum';
+import { apiSamplingExplainFetch, SamplingExplainResp, SamplingExplainStep } from '../../api/samplingExplain';
+
+const formatTime = (ts: number) => new Date(ts * 1000).toLocaleTimeString();
+
+const formatStep = (step: SamplingExplainStep) =>
+  `${step.level} ${step.id}: ${step.sum_size} of ${Math.round(step.share)} bytes (weight ${step.weight} of ${step.sum_weight})`;
+
+export function SamplingExplainPage(props: { yAxisSize: number }) {
+  const { yAxisSize } = props;
+  const { metricName } = useParams();
+  const [data, setData] = useState<SamplingExplainResp | null>(null);
+  const [error, setError] = useState<string | null>(null);
+  const [loading, setLoading] = useState(false);
+
+  useEffect(() => {
+    document.title = `${metricName + ': sampling'} — StatsHouse`;
+  }, [metricName]);
+
+  const load = useCallback(() => {
+    if (!metricName) {
+      return;
+    }
+    setLoading(true);
+    apiSamplingExplainFetch({ [GET_PARAMS.metricName]: metricName })
+      .then(({ response, error }) => {
+        setData(response?.data ?? null);
+        setError(error?.message ?? null);
+      })
+      .finally(() => {
+        setLoading(false);
+      });
+  }, [metricName]);
+
+  useEffect(() => {
+    load();
+  }, [load]);
+
+  return (
+    <div className="container-xl pt-3 pb-3" style={{ paddingLeft: `${yAxisSize}px` }}>
+      <h6 className="overflow-force-wrap font-monospace fw-bold me-3 mb-3">
+        {metricName}
+        <>
+          <span className="text-secondary me-4">: sampling</span>
+          <Link className="text-decoration-none fw-normal small me-3" to={`../../view?s=${metricName}`}>
+            view
+          </Link>
+          <Link className="text-decoration-none fw-normal small me-3" to={`../../view?s=__src_sampling_factor`}>
+            __src_sampling_factor
+          </Link>
+          <Link className="text-decoration-none fw-normal small" to={`../../view?s=__agg_sampling_factor`}>
+            __agg_sampling_factor
+          </Link>
+        </>
+      </h6>
+      <div className="d-flex align-items-center mb-3">
+        <button type="button" className="btn btn-sm btn-outline-primary me-3" onClick={load} disabled={loading}>
+          Refresh
+        </button>
+        {data && (
+          <span className="text-secondary small">
+            Explain mode is on for {Math.round(data.armed_for / 60)} minutes, new decisions appear after the next
+            sampling runs.
+          </span>
+        )}
+      </div>
+      {error && <div className="alert alert-danger">{error}</div>}
+      {data?.sources.map((source) => (
+        <div key={source.address} className="mb-4">
+          <h6 className="font-monospace">{source.address}</h6>
+          {source.error && <div className="alert alert-warning">{source.error}</div>}
+          {!source.error && source.explanations.length === 0 && (
+            <div className="text-secondary small">No decisions recorded yet</div>
+          )}
+          {source.explanations.length > 0 && (
+            <table className="table table-sm">
+              <thead>
+                <tr>
+                  <th>Time</th>
+                  <th className="text-end">SF</th>
+                  <th className="text-end">Rows</th>
+                  <th className="text-end" title="Rows kept with SF 1 because of large counters">
+                    Whales
+                  </th>
+                  <th className="text-end">Fair keys</th>
+                  <th>Steps</th>
+                </tr>
+              </thead>
+              <tbody>
+                {source.explanations
+                  .slice()
+                  .reverse()
+                  .map((e, index) => (
+                    <tr key={index}>
+                      <td className="font-monospace text-nowrap">{formatTime(e.time)}</td>
+                      <td className={cn('font-monospace text-end', e.sf > 1 && 'text-danger')}>{e.sf.toFixed(2)}</td>
+                      <td className="font-monospace text-end">{e.items}</td>
+                      <td className="font-monospace text-end">{e.whales}</td>
+                      <td className="font-monospace text-end">
+                        {e.fair_keys ? `${e.fair_keys_sampled ?? 0} / ${e.fair_keys}` : ''}
+                      </td>
+                      <td>
+                        {e.no_sample_agent && <span className="badge text-bg-info me-1">no sample agent</span>}
+                        {!!e.stratified && <span className="badge text-bg-info me-1">{e.stratified} stratified</span>}
+                        {!!e.min_budget && <span className="badge text-bg-info me-1">min budget {e.min_budget}</span>}
+                        {e.steps.map((step) => (
+                          <div
+                            key={step.level}
+                            className={cn('font-monospace small', step.sampled ? 'text-danger' : 'text-secondary')}
+                          >
+                            {formatStep(step)}
+                          </div>
+                        ))}
+                      </td>
+                    </tr>
+                  ))}
+              </tbody>
+            </table>
+          )}
+        </div>
+      ))}
+    </div>
+  );
+}
@@ -53,6 +53,10 @@ export type MetricMetaValue = {
   skip_sum_square?: boolean;
   pre_key_only?: boolean;
   metric_type?: string;
+  options_version?: number;
+  round_sample_factors?: boolean;
+  shard_unique_values?: boolean;
+  histogram_buckets?: number[];
 };
 
 export type MetricMetaTag = {
@@ -0,0 +1,49 @@
+// Copyright 2023 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+import { GET_PARAMS } from './enum';
+import { apiFetch } from './api';
+
+const ApiMetricCardinalityEndpoint = '/api/cardinality';
+
+/**
+ * Response endpoint api/cardinality
+ */
+export type ApiMetricCardinality = {
+  data: CardinalityResp;
+};
+
+/**
+ * Get params endpoint api/cardinality
+ */
+export type ApiMetricCardinalityGet = {
+  [GET_PARAMS.metricName]: string;
+  [GET_PARAMS.fromTime]: string;
+  [GET_PARAMS.toTime]: string;
+};
+
+export type CardinalityResp = {
+  metric: string;
+  cardinality: number;
+  tags: CardinalityTag[];
+};
+
+export type CardinalityTag = {
+  id: string;
+  name?: string;
+  distinct_lower: number;
+  distinct_upper: number;
+  top_values?: CardinalityTagValue[];
+};
+
+export type CardinalityTagValue = {
+  value: string;
+  share: number;
+};
+
+export async function apiMetricCardinalityFetch(params: ApiMetricCardinalityGet, keyRequest?: unknown) {
+  return await apiFetch<ApiMetricCardinality>({ url: ApiMetricCardinalityEndpoint, get: params, keyRequest });
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 V Kontakte LLC
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+import { GET_PARAMS } from './enum';
+import { apiFetch } from './api';
+
+const ApiSamplingExplainEndpoint = '/api/sampling-explain';
+
+/**
+ * Response endpoint api/sampling-explain
+ */
+export type ApiSamplingExplain = {
+  data: SamplingExplainResp;
+};
+
+/**
+ * Get params endpoint api/sampling-explain
+ */
+export type ApiSamplingExplainGet = {
+  [GET_PARAMS.metricName]: string;
+};
+
+export type SamplingExplainResp = {
+  armed_for: number;
+  sources: SamplingExplainSource[];
+};
+
+export type SamplingExplainSource = {
+  address: string;
+  error?: string;
+  explanations: SamplingExplanation[];
+};
+
+export type SamplingExplanation = {
+  time: number;
+  metric: number;
+  metric_name: string;
+  namespace: number;
+  namespace_name?: string;
+  group: number;
+  sf: number;
+  items: number;
+  whales: number;
+  stratified?: number;
+  min_budget?: number;
+  fair_keys?: number;
+  fair_keys_sampled?: number;
+  no_sample_agent?: boolean;
+  steps: SamplingExplainStep[];
+};
+
+export type SamplingExplainStep = {
+  level: 'namespace' | 'group' | 'metric' | 'key';
+  id: number;
+  weight: number;
+  sum_size: number;
+  budget: number;
+  sum_weight: number;
+  share: number;
+  sampled: boolean;
+};
+
+export async function apiSamplingExplainFetch(params: ApiSamplingExplainGet, keyRequest?: unknown) {
+  return await apiFetch<ApiSamplingExplain>({ url: ApiSamplingExplainEndpoint, get: params, keyRequest });
+}